@@ -0,0 +1,144 @@
+// Command multicastbench compares the multicast and broadcast trees built on the topologies of several
+// inter-satellite link protocols, e.g. to find the protocol distributing content with the fewest links.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/internal/computing"
+	"github.com/keniack/stardustGo/internal/ground"
+	"github.com/keniack/stardustGo/internal/routing"
+	"github.com/keniack/stardustGo/internal/satellite"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+func main() {
+	tleString := flag.String(
+		"tle",
+		"starlink_1000.tle",
+		"TLE file in ./resources/tle",
+	)
+	islConfigString := flag.String(
+		"islConfigs",
+		"./resources/configs/islMstConfig.yaml,./resources/configs/islMstSmartLoopConfig.yaml,./resources/configs/islNearestConfig.yaml",
+		"Paths to inter satellite link config files (comma-separated list)",
+	)
+	groundStationsString := flag.String(
+		"groundStations",
+		"./resources/yml/ground_stations.yml",
+		"Path to ground station file",
+	)
+	computingConfigString := flag.String(
+		"computingConfig",
+		"./resources/configs/computingConfig.yaml",
+		"Path to computing config file",
+	)
+	sourceString := flag.String(
+		"source",
+		"Vienna",
+		"Name of the ground station sending the data",
+	)
+	flag.Parse()
+
+	computingConfig, err := configs.LoadConfigFromFile[[]configs.ComputingConfig](*computingConfigString)
+	if err != nil {
+		log.Fatalf("Failed to load computing configuration: %v", err)
+	}
+
+	fmt.Printf("%-48s %-10s %-20s %6s %6s %8s %8s %12s %10s\n",
+		"isl", "receivers", "algorithm", "dests", "links", "unicast", "savings", "link-latency", "max-delay")
+	for _, islString := range strings.Split(*islConfigString, ",") {
+		islConfig, err := configs.LoadConfigFromFile[configs.InterSatelliteLinkConfig](islString)
+		if err != nil {
+			log.Fatalf("Failed to load isl configuration: %v", err)
+		}
+		bench(islString, *tleString, *islConfig, *computingConfig, *groundStationsString, *sourceString)
+	}
+}
+
+// bench loads the constellation with one ISL protocol, updates its links once and compares the trees.
+func bench(name string, tle string, islConfig configs.InterSatelliteLinkConfig, computingConfig []configs.ComputingConfig, groundStations string, sourceName string) {
+	simTime := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	routerBuilder := routing.NewRouterBuilder(configs.RouterConfig{Protocol: routing.Dijkstra})
+	computingBuilder := computing.NewComputingBuilder(computingConfig)
+
+	satBuilder := satellite.NewSatelliteBuilder(routerBuilder, computingBuilder, islConfig)
+	constellationLoader := satellite.NewSatelliteConstellationLoader()
+	constellationLoader.RegisterDataSourceLoader("tle", satellite.NewTleLoader(islConfig, satBuilder))
+	satellites, err := constellationLoader.LoadSatelliteConstellation(fmt.Sprintf("./resources/tle/%s", tle), "tle")
+	if err != nil {
+		log.Fatalf("Failed to load satellites: %v", err)
+	}
+
+	groundLinkConfig := configs.GroundLinkConfig{Protocol: "nearest"}
+	groundStationBuilder := ground.NewGroundStationBuilder(simTime, routerBuilder, computingBuilder, groundLinkConfig)
+	stations, err := ground.NewGroundStationYmlLoader(groundLinkConfig, groundStationBuilder).Load(groundStations, satellites)
+	if err != nil {
+		log.Fatalf("Failed to load ground stations: %v", err)
+	}
+
+	nodes := make([]types.Node, 0, len(satellites)+len(stations))
+	receivers := make([]types.Node, 0, len(stations))
+	var source types.Node
+	for _, sat := range satellites {
+		nodes = append(nodes, sat)
+	}
+	for _, gs := range stations {
+		nodes = append(nodes, gs)
+		if gs.GetName() == sourceName && source == nil {
+			source = gs
+		} else {
+			receivers = append(receivers, gs)
+		}
+	}
+	if source == nil {
+		log.Fatalf("Unknown source ground station: %s", sourceName)
+	}
+
+	// Bring the topology into the state of one simulation step
+	var wg sync.WaitGroup
+	for _, n := range nodes {
+		wg.Add(1)
+		go func(n types.Node) {
+			defer wg.Done()
+			n.UpdatePosition(simTime)
+		}(n)
+	}
+	wg.Wait()
+	for _, n := range nodes {
+		wg.Add(1)
+		go func(n types.Node) {
+			defer wg.Done()
+			n.GetLinkNodeProtocol().UpdateLinks()
+		}(n)
+	}
+	wg.Wait()
+
+	engine := routerBuilder.Engine()
+	engine.Invalidate()
+	for _, algorithm := range []string{routing.MulticastShortestPathTree, routing.MulticastSteiner} {
+		multicast, err := engine.MulticastTree(source, receivers, algorithm)
+		if err != nil {
+			log.Fatalf("Failed to build multicast tree: %v", err)
+		}
+		printTree(name, "ground", multicast)
+
+		broadcast, err := engine.BroadcastTree(source, algorithm)
+		if err != nil {
+			log.Fatalf("Failed to build broadcast tree: %v", err)
+		}
+		printTree(name, "all", broadcast)
+	}
+}
+
+func printTree(name string, receivers string, t *routing.MulticastTree) {
+	fmt.Printf("%-48s %-10s %-20s %6d %6d %8d %7.1f%% %12.1f %10.1f\n",
+		name, receivers, t.Algorithm, len(t.Latencies), t.LinkCount(), t.UnicastLinks,
+		t.LinkSavings()*100, t.TotalLatency(), t.MaxLatency())
+}
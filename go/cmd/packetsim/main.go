@@ -0,0 +1,207 @@
+// Command packetsim sends constant bit rate flows or the demand of a traffic config between ground stations
+// through the packet-level network while the constellation moves, and reports delay, jitter, loss and throughput per flow.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/internal/computing"
+	"github.com/keniack/stardustGo/internal/ground"
+	"github.com/keniack/stardustGo/internal/network"
+	"github.com/keniack/stardustGo/internal/routing"
+	"github.com/keniack/stardustGo/internal/satellite"
+	"github.com/keniack/stardustGo/internal/traffic"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+func main() {
+	tleString := flag.String(
+		"tle",
+		"starlink_1000.tle",
+		"TLE file in ./resources/tle",
+	)
+	islConfigString := flag.String(
+		"islConfig",
+		"./resources/configs/islMstSmartLoopConfig.yaml",
+		"Path to inter satellite link config file",
+	)
+	routerConfigString := flag.String(
+		"routerConfig",
+		"./resources/configs/routerDijkstraConfig.yaml",
+		"Path to router config file",
+	)
+	groundStationsString := flag.String(
+		"groundStations",
+		"./resources/yml/ground_stations.yml",
+		"Path to ground station file",
+	)
+	computingConfigString := flag.String(
+		"computingConfig",
+		"./resources/configs/computingConfig.yaml",
+		"Path to computing config file",
+	)
+	flowsString := flag.String(
+		"flows",
+		"Vienna>New York,Vienna>Tokyo,Frankfurt>Sydney",
+		"Flows between ground stations as source>target (comma-separated list)",
+	)
+	trafficConfigString := flag.String(
+		"trafficConfig",
+		"",
+		"Path to traffic config file, replaces the flows if set (optional)",
+	)
+	rate := flag.Float64("rate", 200, "Constant bit rate of every flow in Mbit/s")
+	transferRate := flag.Float64("transferRate", 100, "Sending rate of transfers of the traffic config in Mbit/s")
+	packetSize := flag.Int("packetSize", 1500, "Packet size in bytes")
+	bufferSize := flag.Int("bufferSize", network.DefaultBufferSize, "Queue size per link direction in bytes")
+	steps := flag.Int("steps", 5, "Number of topology updates")
+	stepSeconds := flag.Float64("stepSeconds", 1, "Seconds between topology updates")
+	flag.Parse()
+
+	computingConfig, err := configs.LoadConfigFromFile[[]configs.ComputingConfig](*computingConfigString)
+	if err != nil {
+		log.Fatalf("Failed to load computing configuration: %v", err)
+	}
+	islConfig, err := configs.LoadConfigFromFile[configs.InterSatelliteLinkConfig](*islConfigString)
+	if err != nil {
+		log.Fatalf("Failed to load isl configuration: %v", err)
+	}
+	routerConfig, err := configs.LoadConfigFromFile[configs.RouterConfig](*routerConfigString)
+	if err != nil {
+		log.Fatalf("Failed to load router configuration: %v", err)
+	}
+
+	simTime := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	routerBuilder := routing.NewRouterBuilder(*routerConfig)
+	routerBuilder.GeoFence().SetEpoch(simTime)
+	computingBuilder := computing.NewComputingBuilder(*computingConfig)
+
+	satBuilder := satellite.NewSatelliteBuilder(routerBuilder, computingBuilder, *islConfig)
+	constellationLoader := satellite.NewSatelliteConstellationLoader()
+	constellationLoader.RegisterDataSourceLoader("tle", satellite.NewTleLoader(*islConfig, satBuilder))
+	satellites, err := constellationLoader.LoadSatelliteConstellation(fmt.Sprintf("./resources/tle/%s", *tleString), "tle")
+	if err != nil {
+		log.Fatalf("Failed to load satellites: %v", err)
+	}
+
+	groundLinkConfig := configs.GroundLinkConfig{Protocol: "nearest"}
+	groundStationBuilder := ground.NewGroundStationBuilder(simTime, routerBuilder, computingBuilder, groundLinkConfig)
+	stations, err := ground.NewGroundStationYmlLoader(groundLinkConfig, groundStationBuilder).Load(*groundStationsString, satellites)
+	if err != nil {
+		log.Fatalf("Failed to load ground stations: %v", err)
+	}
+
+	nodes := make([]types.Node, 0, len(satellites)+len(stations))
+	stationNames := make(map[string]types.Node, len(stations))
+	for _, sat := range satellites {
+		nodes = append(nodes, sat)
+	}
+	for _, gs := range stations {
+		nodes = append(nodes, gs)
+		if _, exists := stationNames[gs.GetName()]; !exists {
+			stationNames[gs.GetName()] = gs
+		}
+	}
+
+	net := network.NewNetwork(*bufferSize, 0)
+	if *trafficConfigString != "" {
+		trafficConfig, err := configs.LoadConfigFromFile[configs.TrafficConfig](*trafficConfigString)
+		if err != nil {
+			log.Fatalf("Failed to load traffic configuration: %v", err)
+		}
+		demands, err := traffic.Generate(*trafficConfig, stations, simTime)
+		if err != nil {
+			log.Fatalf("Failed to generate traffic: %v", err)
+		}
+		if err := traffic.Inject(net, demands, *packetSize, *transferRate*1_000_000); err != nil {
+			log.Fatalf("Failed to inject traffic: %v", err)
+		}
+		log.Printf("Generated %d demands", len(demands))
+	} else {
+		addFlows(net, *flowsString, stationNames, *packetSize, *rate*1_000_000, simTime)
+	}
+
+	// Run the traffic of every interval on the topology at its start
+	step := time.Duration(*stepSeconds * float64(time.Second))
+	for range *steps {
+		updateTopology(nodes, simTime)
+		routerBuilder.UpdateTopology(simTime)
+		simTime = simTime.Add(step)
+		net.RunUntil(simTime)
+	}
+
+	fmt.Printf("%-32s %8s %9s %8s %10s %10s %10s %10s %10s\n",
+		"flow", "sent", "delivered", "loss", "min-delay", "mean-delay", "max-delay", "jitter", "Mbit/s")
+	for _, f := range net.Flows() {
+		s := f.Stats()
+		fmt.Printf("%-32s %8d %9d %7.2f%% %10s %10s %10s %10s %10.1f\n",
+			f.ID, s.Sent, s.Delivered, s.Loss()*100,
+			s.MinDelay.Round(time.Microsecond), s.MeanDelay.Round(time.Microsecond),
+			s.MaxDelay.Round(time.Microsecond), s.Jitter.Round(time.Microsecond), s.Throughput()/1_000_000)
+		reasons := make([]string, 0, len(s.Dropped))
+		for reason, count := range s.Dropped {
+			reasons = append(reasons, fmt.Sprintf("%s=%d", reason, count))
+		}
+		sort.Strings(reasons)
+		if len(reasons) > 0 {
+			fmt.Printf("%-32s dropped: %s\n", "", strings.Join(reasons, " "))
+		}
+	}
+}
+
+// addFlows adds constant bit rate flows between ground stations given as source>target list.
+func addFlows(net *network.Network, flows string, stationNames map[string]types.Node, packetSize int, rate float64, start time.Time) {
+	for _, spec := range strings.Split(flows, ",") {
+		names := strings.SplitN(spec, ">", 2)
+		if len(names) != 2 {
+			log.Fatalf("Invalid flow %q, expected source>target", spec)
+		}
+		source, ok := stationNames[strings.TrimSpace(names[0])]
+		if !ok {
+			log.Fatalf("Unknown ground station: %s", names[0])
+		}
+		target, ok := stationNames[strings.TrimSpace(names[1])]
+		if !ok {
+			log.Fatalf("Unknown ground station: %s", names[1])
+		}
+		err := net.AddFlow(&network.Flow{
+			ID:         spec,
+			Source:     source,
+			Target:     target,
+			PacketSize: packetSize,
+			Rate:       rate,
+			Start:      start,
+		})
+		if err != nil {
+			log.Fatalf("Failed to add flow: %v", err)
+		}
+	}
+}
+
+// updateTopology moves all nodes to the given time and updates their links.
+func updateTopology(nodes []types.Node, simTime time.Time) {
+	var wg sync.WaitGroup
+	for _, n := range nodes {
+		wg.Add(1)
+		go func(n types.Node) {
+			defer wg.Done()
+			n.UpdatePosition(simTime)
+		}(n)
+	}
+	wg.Wait()
+	for _, n := range nodes {
+		wg.Add(1)
+		go func(n types.Node) {
+			defer wg.Done()
+			n.GetLinkNodeProtocol().UpdateLinks()
+		}(n)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,218 @@
+// Command routingbench compares the shared routing engine with the previous per-node routing
+// implementations on real constellations, e.g. with 1000 to 6000 satellites.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/internal/computing"
+	"github.com/keniack/stardustGo/internal/ground"
+	"github.com/keniack/stardustGo/internal/routing"
+	"github.com/keniack/stardustGo/internal/satellite"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+func main() {
+	tleString := flag.String(
+		"tle",
+		"starlink_1000.tle,starlink_2000.tle,starlink_3000.tle,starlink_6000.tle",
+		"TLE files in ./resources/tle (comma-separated list)",
+	)
+	islConfigString := flag.String(
+		"islConfig",
+		"./resources/configs/islMstConfig.yaml",
+		"Path to inter satellite link config file",
+	)
+	groundStationsString := flag.String(
+		"groundStations",
+		"./resources/yml/ground_stations.yml",
+		"Path to ground station file (optional)",
+	)
+	computingConfigString := flag.String(
+		"computingConfig",
+		"./resources/configs/computingConfig.yaml",
+		"Path to computing config file",
+	)
+	samples := flag.Int(
+		"samples",
+		20,
+		"Number of sources used to extrapolate the legacy all-pairs time and number of A* queries",
+	)
+	flag.Parse()
+
+	islConfig, err := configs.LoadConfigFromFile[configs.InterSatelliteLinkConfig](*islConfigString)
+	if err != nil {
+		log.Fatalf("Failed to load isl configuration: %v", err)
+	}
+	computingConfig, err := configs.LoadConfigFromFile[[]configs.ComputingConfig](*computingConfigString)
+	if err != nil {
+		log.Fatalf("Failed to load computing configuration: %v", err)
+	}
+
+	fmt.Printf("%-22s %6s %6s %16s %16s %9s %14s %14s %9s\n",
+		"constellation", "nodes", "edges", "legacy-apsp", "engine-apsp", "speedup", "legacy-astar", "engine-astar", "speedup")
+	for _, tle := range strings.Split(*tleString, ",") {
+		bench(tle, *islConfig, *computingConfig, *groundStationsString, *samples)
+	}
+}
+
+// bench loads one constellation, updates its links once and measures all routing variants.
+func bench(tle string, islConfig configs.InterSatelliteLinkConfig, computingConfig []configs.ComputingConfig, groundStations string, samples int) {
+	simTime := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	routerBuilder := routing.NewRouterBuilder(configs.RouterConfig{Protocol: routing.AStar})
+	computingBuilder := computing.NewComputingBuilder(computingConfig)
+
+	satBuilder := satellite.NewSatelliteBuilder(routerBuilder, computingBuilder, islConfig)
+	constellationLoader := satellite.NewSatelliteConstellationLoader()
+	constellationLoader.RegisterDataSourceLoader("tle", satellite.NewTleLoader(islConfig, satBuilder))
+	satellites, err := constellationLoader.LoadSatelliteConstellation(fmt.Sprintf("./resources/tle/%s", tle), "tle")
+	if err != nil {
+		log.Fatalf("Failed to load satellites: %v", err)
+	}
+
+	nodes := make([]types.Node, 0, len(satellites))
+	for _, sat := range satellites {
+		nodes = append(nodes, sat)
+	}
+	if groundStations != "" {
+		groundLinkConfig := configs.GroundLinkConfig{Protocol: "nearest"}
+		groundStationBuilder := ground.NewGroundStationBuilder(simTime, routerBuilder, computingBuilder, groundLinkConfig)
+		stations, err := ground.NewGroundStationYmlLoader(groundLinkConfig, groundStationBuilder).Load(groundStations, satellites)
+		if err != nil {
+			log.Fatalf("Failed to load ground stations: %v", err)
+		}
+		for _, gs := range stations {
+			nodes = append(nodes, gs)
+		}
+	}
+
+	// Bring the topology into the state of one simulation step
+	var wg sync.WaitGroup
+	for _, n := range nodes {
+		wg.Add(1)
+		go func(n types.Node) {
+			defer wg.Done()
+			n.UpdatePosition(simTime)
+		}(n)
+	}
+	wg.Wait()
+	for _, n := range nodes {
+		wg.Add(1)
+		go func(n types.Node) {
+			defer wg.Done()
+			n.GetLinkNodeProtocol().UpdateLinks()
+		}(n)
+	}
+	wg.Wait()
+
+	rnd := rand.New(rand.NewSource(1))
+	sources := make([]types.Node, samples)
+	targets := make([]types.Node, samples)
+	for i := range sources {
+		sources[i] = nodes[rnd.Intn(len(nodes))]
+		targets[i] = nodes[rnd.Intn(len(nodes))]
+	}
+
+	// Legacy all-pairs: one sort based Dijkstra per node, extrapolated from the sampled sources
+	start := time.Now()
+	for _, src := range sources {
+		legacyDijkstra(src)
+	}
+	legacyApsp := time.Since(start) / time.Duration(samples) * time.Duration(len(nodes))
+
+	engine := routerBuilder.Engine()
+	engine.Invalidate()
+	start = time.Now()
+	engine.ComputeAll()
+	engineApsp := time.Since(start)
+
+	start = time.Now()
+	for i := range sources {
+		legacyAStar(sources[i], targets[i])
+	}
+	legacyAStarTime := time.Since(start) / time.Duration(samples)
+
+	start = time.Now()
+	for i := range sources {
+		sources[i].GetRouter().RouteToNode(targets[i], nil)
+	}
+	engineAStarTime := time.Since(start) / time.Duration(samples)
+
+	fmt.Printf("%-22s %6d %6d %16s %16s %8.1fx %14s %14s %8.1fx\n",
+		tle, len(nodes), engine.Snapshot().EdgeCount(),
+		legacyApsp.Round(time.Millisecond), engineApsp.Round(time.Millisecond), float64(legacyApsp)/float64(engineApsp),
+		legacyAStarTime.Round(time.Microsecond), engineAStarTime.Round(time.Microsecond), float64(legacyAStarTime)/float64(engineAStarTime))
+}
+
+type legacyEntry struct {
+	target  types.Node
+	latency float64
+}
+
+// legacyDijkstra is the previous DijkstraRouter.CalculateRoutingTable, re-sorting the queue after every pop.
+func legacyDijkstra(src types.Node) map[types.Node]float64 {
+	routes := map[types.Node]float64{src: 0}
+	visited := map[types.Node]bool{src: true}
+	queue := []legacyEntry{}
+	for _, l := range src.GetLinkNodeProtocol().Established() {
+		queue = append(queue, legacyEntry{l.GetOther(src), l.Latency()})
+	}
+	sort.Slice(queue, func(i, j int) bool { return queue[i].latency < queue[j].latency })
+
+	for len(queue) > 0 {
+		entry := queue[0]
+		queue = queue[1:]
+		if visited[entry.target] {
+			continue
+		}
+		visited[entry.target] = true
+		routes[entry.target] = entry.latency
+		for _, l := range entry.target.GetLinkNodeProtocol().Established() {
+			neighbor := l.GetOther(entry.target)
+			if !visited[neighbor] {
+				queue = append(queue, legacyEntry{neighbor, entry.latency + l.Latency()})
+			}
+		}
+		sort.Slice(queue, func(i, j int) bool { return queue[i].latency < queue[j].latency })
+	}
+	return routes
+}
+
+// legacyAStar is the previous AStarRouter.RouteTo, scanning a map for the best open node.
+func legacyAStar(src, target types.Node) float64 {
+	h := func(n types.Node) float64 { return n.DistanceTo(target) / configs.SpeedOfLight * 1000 }
+	openset := map[types.Node]float64{src: h(src)}
+	gScore := map[types.Node]float64{src: 0}
+	for len(openset) > 0 {
+		var current types.Node
+		minScore := math.MaxFloat64
+		for n, score := range openset {
+			if score < minScore {
+				current = n
+				minScore = score
+			}
+		}
+		delete(openset, current)
+		if current == target {
+			return gScore[current]
+		}
+		for _, l := range current.GetLinkNodeProtocol().Established() {
+			neighbor := l.GetOther(current)
+			alt := gScore[current] + l.Latency()
+			if prev, ok := gScore[neighbor]; !ok || alt < prev {
+				gScore[neighbor] = alt
+				openset[neighbor] = alt + h(neighbor)
+			}
+		}
+	}
+	return math.Inf(1)
+}
@@ -64,6 +64,41 @@ func main() {
 		"",
 		"Plugin names (optional, comma-separated list)",
 	)
+	capacityConfigString := flag.String(
+		"capacityConfig",
+		"",
+		"Path to capacity config file, estimates the throughput of a traffic demand per step (optional)",
+	)
+	deploymentConfigString := flag.String(
+		"deploymentConfig",
+		"",
+		"Path to deployment config file, declares the services deployed during the simulation (optional)",
+	)
+	sloConfigString := flag.String(
+		"sloConfig",
+		"",
+		"Path to SLO config file, evaluates the declared SLOs after every step (optional)",
+	)
+	computingMappingFile := flag.String(
+		"computingMappingFile",
+		"",
+		"Path to CSV file assigning hardware profiles of the computing config to nodes by name (optional)",
+	)
+	offloadConfigString := flag.String(
+		"offloadConfig",
+		"",
+		"Path to offload config file, executes the declared task workloads on the nodes (optional)",
+	)
+	faasConfigString := flag.String(
+		"faasConfig",
+		"",
+		"Path to FaaS config file, executes the declared function invocations on the serverless platform (optional)",
+	)
+	appConfigString := flag.String(
+		"appConfig",
+		"",
+		"Path to app config file, runs the declared apps on the app runtime (optional)",
+	)
 	flag.Parse()
 
 	simulationPluginList := strings.Split(*simulationPluginString, ",")
@@ -92,42 +127,207 @@ func main() {
 		log.Fatalf("Failed to load simulation configuration: %v", err)
 	}
 
+	var capacityConfig *configs.CapacityConfig
+	if *capacityConfigString != "" {
+		capacityConfig, err = configs.LoadConfigFromFile[configs.CapacityConfig](*capacityConfigString)
+		if err != nil {
+			log.Fatalf("Failed to load capacity configuration: %v", err)
+		}
+	}
+
+	var deploymentConfig *configs.DeploymentConfig
+	if *deploymentConfigString != "" {
+		deploymentConfig, err = configs.LoadConfigFromFile[configs.DeploymentConfig](*deploymentConfigString)
+		if err != nil {
+			log.Fatalf("Failed to load deployment configuration: %v", err)
+		}
+	}
+
+	var sloConfig *configs.SloConfig
+	if *sloConfigString != "" {
+		sloConfig, err = configs.LoadConfigFromFile[configs.SloConfig](*sloConfigString)
+		if err != nil {
+			log.Fatalf("Failed to load SLO configuration: %v", err)
+		}
+	}
+
+	var offloadConfig *configs.OffloadConfig
+	if *offloadConfigString != "" {
+		offloadConfig, err = configs.LoadConfigFromFile[configs.OffloadConfig](*offloadConfigString)
+		if err != nil {
+			log.Fatalf("Failed to load offload configuration: %v", err)
+		}
+	}
+
+	var faasConfig *configs.FaasConfig
+	if *faasConfigString != "" {
+		faasConfig, err = configs.LoadConfigFromFile[configs.FaasConfig](*faasConfigString)
+		if err != nil {
+			log.Fatalf("Failed to load FaaS configuration: %v", err)
+		}
+	}
+
+	var appConfig *configs.AppConfig
+	if *appConfigString != "" {
+		appConfig, err = configs.LoadConfigFromFile[configs.AppConfig](*appConfigString)
+		if err != nil {
+			log.Fatalf("Failed to load app configuration: %v", err)
+		}
+	}
+
+	options := simulationOptions{
+		Simulation:        *simulationConfig,
+		Computing:         *computingConfig,
+		ComputingMapping:  *computingMappingFile,
+		Router:            *routerConfig,
+		Capacity:          capacityConfig,
+		Deployment:        deploymentConfig,
+		Slo:               sloConfig,
+		Offload:           offloadConfig,
+		Faas:              faasConfig,
+		Apps:              appConfig,
+		SimulationPlugins: simulationPluginList,
+	}
+
 	var simService types.SimulationController
 	if *simulationStateInputFile != "" {
-		simService = startSimulationIteration(*simulationConfig, *computingConfig, *routerConfig, *simulationStateInputFile, simulationPluginList)
+		simService = startSimulationIteration(options, *simulationStateInputFile)
 	} else {
-		simService = startSimulation(*simulationConfig, *islConfigString, *groundLinkConfigString, *computingConfig, *routerConfig, simulationStateOutputFile, simulationPluginList, statePluginList)
+		simService = startSimulation(options, *islConfigString, *groundLinkConfigString, simulationStateOutputFile, statePluginList)
 	}
 
 	myCode(simService, *simulationConfig)
 }
 
-func startSimulationIteration(simulationConfig configs.SimulationConfig, computingConfig []configs.ComputingConfig, routerConfig configs.RouterConfig, simulationStateInputFile string, simulationPluginList []string) types.SimulationController {
+// simulationOptions are the configurations shared by the live and the precomputed simulation.
+// The optional configurations are nil if not given.
+type simulationOptions struct {
+	Simulation        configs.SimulationConfig
+	Computing         []configs.ComputingConfig
+	ComputingMapping  string // CSV file assigning hardware profiles to nodes, empty if not given
+	Router            configs.RouterConfig
+	Capacity          *configs.CapacityConfig
+	Deployment        *configs.DeploymentConfig
+	Slo               *configs.SloConfig
+	Offload           *configs.OffloadConfig
+	Faas              *configs.FaasConfig
+	Apps              *configs.AppConfig
+	SimulationPlugins []string
+}
+
+// simulationParts are the builders and plugins both simulation modes are built from.
+type simulationParts struct {
+	computingBuilder *computing.DefaultComputingBuilder
+	routerBuilder    *routing.RouterBuilder
+	orchestrator     *deployment.DeploymentOrchestrator
+	simPlugins       []types.SimulationPlugin
+	sloPlugin        *stateplugin.SloEvaluationPlugin // nil without SLO config
+}
+
+// buildSimulationParts builds the computing and router builders, the orchestrator and the simulation plugins
+// of the options, and registers the plugins observing the lifecycle of the replicas.
+func buildSimulationParts(options simulationOptions) simulationParts {
 	// Step 2: Build computing builder with configured strategies
-	var computingBuilder computing.ComputingBuilder = computing.NewComputingBuilder(computingConfig)
+	computingBuilder := computing.NewComputingBuilder(options.Computing)
+	if options.ComputingMapping != "" {
+		if err := computingBuilder.LoadMapping(options.ComputingMapping); err != nil {
+			log.Fatalf("Failed to load computing mapping: %v", err)
+		}
+	}
 
 	// Step 3: Build router builder
+	routerConfig := options.Router
 	routerBuilder := routing.NewRouterBuilder(routerConfig)
+	routerBuilder.GeoFence().SetEpoch(options.Simulation.SimulationStartTime)
 
 	// Step 4.1: Initialize plugin builder
 	simPluginBuilder := simplugin.NewPluginBuilder()
-	simPlugins, err := simPluginBuilder.BuildPlugins(simulationPluginList)
+	simPlugins, err := simPluginBuilder.BuildPlugins(options.SimulationPlugins)
 	if err != nil {
 		log.Fatalf("Failed to build simualtion plugins: %v", err)
-		return nil
 	}
+	if routerConfig.LinkStateStats != "" {
+		simPlugins = append(simPlugins, simplugin.NewLinkStatePlugin(routerBuilder.LinkStateControlPlane(), routerConfig.LinkStateStats))
+	}
+	if routerConfig.AnycastExport != nil {
+		simPlugins = append(simPlugins, simplugin.NewAnycastAssignmentPlugin(routerBuilder.Anycast(), *routerConfig.AnycastExport))
+	}
+	if balancing := routerConfig.ServiceBalancing; balancing != nil && balancing.File != "" {
+		simPlugins = append(simPlugins, simplugin.NewServiceBalancingPlugin(routerBuilder.ServiceBalancer(), balancing.File))
+	}
+	if options.Capacity != nil {
+		simPlugins = append(simPlugins, simplugin.NewCapacityPlugin(*options.Capacity, options.Simulation.SimulationStartTime))
+	}
+	if options.Offload != nil {
+		offloadPlugin, err := simplugin.NewOffloadPlugin(*options.Offload)
+		if err != nil {
+			log.Fatalf("Failed to build offload plugin: %v", err)
+		}
+		simPlugins = append(simPlugins, offloadPlugin)
+	}
+	if options.Faas != nil {
+		faasPlugin, err := simplugin.NewFaasPlugin(*options.Faas)
+		if err != nil {
+			log.Fatalf("Failed to build FaaS plugin: %v", err)
+		}
+		simPlugins = append(simPlugins, faasPlugin)
+	}
+	if options.Apps != nil {
+		appPlugin, err := simplugin.NewAppPlugin(*options.Apps)
+		if err != nil {
+			log.Fatalf("Failed to build app plugin: %v", err)
+		}
+		simPlugins = append(simPlugins, appPlugin)
+	}
+
+	// Step 4.2: Initialize orchestrator and the declared deployments (if used)
+	orchestrator := deployment.NewDeploymentOrchestrator()
+	if options.Deployment != nil {
+		deploymentPlugin, err := simplugin.NewDeploymentPlugin(orchestrator, *options.Deployment, options.Simulation.SimulationStartTime)
+		if err != nil {
+			log.Fatalf("Failed to build deployment plugin: %v", err)
+		}
+		simPlugins = append(simPlugins, deploymentPlugin)
+	}
+	for _, plugin := range simPlugins {
+		if observer, ok := plugin.(deployment.LifecycleObserver); ok {
+			orchestrator.Lifecycle().Observe(observer)
+		}
+	}
+
+	// Step 4.3: Initialize the SLO evaluation (if used)
+	var sloPlugin *stateplugin.SloEvaluationPlugin
+	if options.Slo != nil {
+		sloPlugin, err = stateplugin.NewSloEvaluationPlugin(*options.Slo)
+		if err != nil {
+			log.Fatalf("Failed to build SLO plugin: %v", err)
+		}
+	}
+
+	return simulationParts{
+		computingBuilder: computingBuilder,
+		routerBuilder:    routerBuilder,
+		orchestrator:     orchestrator,
+		simPlugins:       simPlugins,
+		sloPlugin:        sloPlugin,
+	}
+}
+
+func startSimulationIteration(options simulationOptions, simulationStateInputFile string) types.SimulationController {
+	parts := buildSimulationParts(options)
 
 	// Step 5: State Plugin Builder
 	statePluginBuilder := stateplugin.NewStatePluginPrecompBuilder(simulationStateInputFile)
+	if parts.sloPlugin != nil {
+		statePluginBuilder.AddLivePlugin(parts.sloPlugin)
+	}
 
-	// Step 6: Inject orchestrator (if used)
-	orchestrator := deployment.NewDeploymentOrchestrator()
-
-	simStateDeserializer := simulation.NewSimulationStateDeserializer(&simulationConfig, simulationStateInputFile, computingBuilder, routerBuilder, orchestrator, simPlugins, statePluginBuilder)
+	simulationConfig := options.Simulation
+	simStateDeserializer := simulation.NewSimulationStateDeserializer(&simulationConfig, simulationStateInputFile, parts.computingBuilder, parts.routerBuilder, parts.orchestrator, parts.simPlugins, statePluginBuilder)
 	return simStateDeserializer.LoadIterator()
 }
 
-func startSimulation(simulationConfig configs.SimulationConfig, islConfigString string, groundLinkConfigString string, computingConfig []configs.ComputingConfig, routerConfig configs.RouterConfig, simulationStateOutputFile *string, simulationPluginList []string, statePluginList []string) types.SimulationController {
+func startSimulation(options simulationOptions, islConfigString string, groundLinkConfigString string, simulationStateOutputFile *string, statePluginList []string) types.SimulationController {
 	islConfig, err := configs.LoadConfigFromFile[configs.InterSatelliteLinkConfig](islConfigString)
 	if err != nil {
 		log.Fatalf("Failed to load isl configuration: %v", err)
@@ -137,28 +337,24 @@ func startSimulation(simulationConfig configs.SimulationConfig, islConfigString
 	if err != nil {
 		log.Fatalf("Failed to load isl configuration: %v", err)
 	}
-
-	// Step 2: Build computing builder with configured strategies
-	computingBuilder := computing.NewComputingBuilder(computingConfig)
-
-	// Step 3: Build router builder
-	routerBuilder := routing.NewRouterBuilder(routerConfig)
-
-	// Step 4.1: Initialize plugin builder
-	simPluginBuilder := simplugin.NewPluginBuilder()
-	simPlugins, err := simPluginBuilder.BuildPlugins(simulationPluginList)
-	if err != nil {
-		log.Fatalf("Failed to build simualtion plugins: %v", err)
-		return nil
+	if routing.RequiresContactPlan(options.Router, islConfig.Routers) {
+		log.Fatalf("Failed to build routers: %v", routing.ErrNoContactPlan)
 	}
 
-	// Step 4.2: Initialize state plugin builder
+	parts := buildSimulationParts(options)
+	simulationConfig := options.Simulation
+	computingBuilder, routerBuilder := parts.computingBuilder, parts.routerBuilder
+
+	// Step 4.4: Initialize state plugin builder
 	statePluginBuilder := stateplugin.NewStatePluginBuilder()
 	statePlugins, err := statePluginBuilder.BuildPlugins(statePluginList)
 	if err != nil {
 		log.Fatalf("Failed to build state plugins: %v", err)
 		return nil
 	}
+	if parts.sloPlugin != nil {
+		statePlugins = append(statePlugins, parts.sloPlugin)
+	}
 
 	// Step 5.1: Initialize the satellite builder
 	satBuilder := satellite.NewSatelliteBuilder(routerBuilder, computingBuilder, *islConfig)
@@ -173,11 +369,10 @@ func startSimulation(simulationConfig configs.SimulationConfig, islConfigString
 	constellationLoader.RegisterDataSourceLoader("tle", tleLoader)
 
 	// Step 5: Initialize simulation service
-	simService := simulation.NewSimulationService(&simulationConfig, routerBuilder, computingBuilder, simPlugins, types.NewStatePluginRepository(statePlugins), simulationStateOutputFile)
+	simService := simulation.NewSimulationService(&simulationConfig, routerBuilder, computingBuilder, parts.simPlugins, types.NewStatePluginRepository(statePlugins), simulationStateOutputFile)
 
-	// Step 6: Inject orchestrator (if used)
-	orchestrator := deployment.NewDeploymentOrchestrator()
-	simService.Inject(orchestrator)
+	// Step 6: Inject orchestrator
+	simService.Inject(parts.orchestrator)
 
 	// Step 8: Load satellites using the loader service
 	loaderService := satellite.NewSatelliteLoaderService(*islConfig, satBuilder, constellationLoader, simService, fmt.Sprintf("./resources/%s/%s", simulationConfig.SatelliteDataSourceType, simulationConfig.SatelliteDataSource), simulationConfig.SatelliteDataSourceType)
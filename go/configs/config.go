@@ -24,8 +24,15 @@ type SimulationConfig struct {
 }
 
 type InterSatelliteLinkConfig struct {
-	Neighbours int    `json:"Neighbours" yaml:"Neighbours"` // Number of neighbors per satellite
-	Protocol   string `json:"Protocol" yaml:"Protocol"`     // Strategy name: "mst", "nearest", etc.
+	Neighbours int              `json:"Neighbours" yaml:"Neighbours"` // Number of neighbors per satellite
+	Protocol   string           `json:"Protocol" yaml:"Protocol"`     // Strategy name: "mst", "nearest", etc.
+	Routers    []RouterSelector `json:"Routers" yaml:"Routers"`       // Router per satellite, first match wins
+}
+
+// RouterSelector selects the router protocol of all satellites whose name matches.
+type RouterSelector struct {
+	Name   string `json:"Name" yaml:"Name"`     // Regular expression matched against the satellite name
+	Router string `json:"Router" yaml:"Router"` // Router protocol, e.g. "dijkstra", or "default"
 }
 
 type GroundLinkConfig struct {
@@ -33,13 +40,260 @@ type GroundLinkConfig struct {
 }
 
 type RouterConfig struct {
-	Protocol string `json:"Protocol" yaml:"Protocol"`
+	Protocol         string                  `json:"Protocol" yaml:"Protocol"`
+	LinkCost         string                  `json:"LinkCost" yaml:"LinkCost"`                 // Link cost minimized by "dijkstra" and "a-star", default "latency"
+	LinkCostWeights  map[string]float64      `json:"LinkCostWeights" yaml:"LinkCostWeights"`   // Weights of the link costs combined by "weighted"
+	PathCount        int                     `json:"PathCount" yaml:"PathCount"`               // Paths per target of the "k-shortest-paths" router
+	EcmpTolerance    float64                 `json:"EcmpTolerance" yaml:"EcmpTolerance"`       // Relative latency tolerance of equal-cost paths, e.g. 0.05
+	QosMode          string                  `json:"QosMode" yaml:"QosMode"`                   // Path selection of the "qos" router: "latency", "widest", "latency-constrained-widest"
+	Regions          string                  `json:"Regions" yaml:"Regions"`                   // GeoJSON file with the regions of geographic routing constraints
+	AnycastGroups    []AnycastGroupConfig    `json:"AnycastGroups" yaml:"AnycastGroups"`       // Named groups of nodes routed to by anycast
+	AnycastExport    *AnycastExportConfig    `json:"AnycastExport" yaml:"AnycastExport"`       // Export of the gateway assignments per step
+	ServiceBalancing *ServiceBalancingConfig `json:"ServiceBalancing" yaml:"ServiceBalancing"` // Selection of the replica serving a service request, closest replica if not set
+	LinkStateStats   string                  `json:"LinkStateStats" yaml:"LinkStateStats"`     // CSV file of the link-state control plane statistics per step, empty to disable
+}
+
+// AnycastGroupConfig selects the members of an anycast group. A node is a member if it matches all given criteria.
+type AnycastGroupConfig struct {
+	Name          string              `json:"Name" yaml:"Name"`
+	NodeType      string              `json:"NodeType" yaml:"NodeType"`           // "ground" or "satellite", empty for both
+	ComputingType types.ComputingType `json:"ComputingType" yaml:"ComputingType"` // e.g. "Cloud"
+	Tag           string              `json:"Tag" yaml:"Tag"`                     // Tag of the ground station, e.g. "gateway"
+	NodeName      string              `json:"NodeName" yaml:"NodeName"`           // Regular expression matched against the node name
+}
+
+// AnycastExportConfig configures the export of the best gateway of every user terminal per simulation step.
+type AnycastExportConfig struct {
+	Terminals string `json:"Terminals" yaml:"Terminals"` // Anycast group of the user terminals
+	Gateways  string `json:"Gateways" yaml:"Gateways"`   // Anycast group of the gateways
+	File      string `json:"File" yaml:"File"`           // CSV output file
+}
+
+// ServiceBalancingConfig selects the replica serving each service request among the replicas known to the client's router.
+type ServiceBalancingConfig struct {
+	Policy string `json:"Policy" yaml:"Policy"` // "lowest-latency" (default), "round-robin", "least-loaded" or "power-of-two"
+	Seed   int64  `json:"Seed" yaml:"Seed"`     // Seed of the random choices of "power-of-two"
+	File   string `json:"File" yaml:"File"`     // CSV output of the requests per replica and step (optional)
+}
+
+// TrafficConfig describes the traffic demand between ground stations.
+type TrafficConfig struct {
+	Model            string                 `json:"Model" yaml:"Model"`                       // "matrix", "gravity", "poisson" or "trace"
+	Seed             int64                  `json:"Seed" yaml:"Seed"`                         // Seed of the random generator, same seed same demand
+	Start            time.Time              `json:"Start" yaml:"Start"`                       // Start of the demand, zero for the simulation start
+	Duration         float64                `json:"Duration" yaml:"Duration"`                 // Duration of the demand in seconds
+	Tag              string                 `json:"Tag" yaml:"Tag"`                           // Only ground stations with this tag, empty for all
+	Matrix           []TrafficMatrixEntry   `json:"Matrix" yaml:"Matrix"`                     // Constant rates of the "matrix" model
+	TotalRate        float64                `json:"TotalRate" yaml:"TotalRate"`               // Rate in bits per second distributed by the "gravity" model
+	DistanceExponent float64                `json:"DistanceExponent" yaml:"DistanceExponent"` // Decay of the "gravity" model with the distance, 0 for none
+	ArrivalRate      float64                `json:"ArrivalRate" yaml:"ArrivalRate"`           // Transfers per second of the "poisson" model
+	Size             SizeDistributionConfig `json:"Size" yaml:"Size"`                         // Transfer sizes of the "poisson" model
+	Trace            string                 `json:"Trace" yaml:"Trace"`                       // CSV file replayed by the "trace" model
+}
+
+// TrafficMatrixEntry is the constant rate from one ground station to another.
+type TrafficMatrixEntry struct {
+	Source string  `json:"Source" yaml:"Source"`
+	Target string  `json:"Target" yaml:"Target"`
+	Rate   float64 `json:"Rate" yaml:"Rate"` // bits per second
 }
 
+// SizeDistributionConfig selects the distribution of transfer sizes.
+type SizeDistributionConfig struct {
+	Type  string  `json:"Type" yaml:"Type"`   // "constant", "exponential", "pareto" or "lognormal"
+	Mean  float64 `json:"Mean" yaml:"Mean"`   // Mean size in bytes
+	Shape float64 `json:"Shape" yaml:"Shape"` // Shape of "pareto" (alpha > 1) and sigma of "lognormal"
+}
+
+// CapacityConfig configures the flow-level capacity estimation of a traffic demand per simulation step.
+type CapacityConfig struct {
+	Traffic  TrafficConfig `json:"Traffic" yaml:"Traffic"`   // Demand routed through the network
+	Fairness string        `json:"Fairness" yaml:"Fairness"` // Rate allocation: "max-min" (default) or "proportional"
+	File     string        `json:"File" yaml:"File"`         // CSV output of the totals per step
+	FlowFile string        `json:"FlowFile" yaml:"FlowFile"` // CSV output of the rate per flow and step (optional)
+	LinkFile string        `json:"LinkFile" yaml:"LinkFile"` // CSV output of the load per link and step (optional)
+}
+
+// DeploymentConfig declares the services deployed during the simulation.
+type DeploymentConfig struct {
+	Services      []ServiceDeploymentConfig `json:"Services" yaml:"Services"`
+	Registry      string                    `json:"Registry" yaml:"Registry"`           // Node the images are pulled from, empty to start without pulling
+	LifecycleFile string                    `json:"LifecycleFile" yaml:"LifecycleFile"` // CSV output of the state transitions of the replicas (optional)
+	MigrationFile string                    `json:"MigrationFile" yaml:"MigrationFile"` // CSV output of the migration statistics per service and step (optional)
+	SloFile       string                    `json:"SloFile" yaml:"SloFile"`             // CSV output of the SLO compliance timeline of the autoscaled services (optional)
+}
+
+// ServiceDeploymentConfig declares the replicas of a service and the nodes they may be placed on.
+type ServiceDeploymentConfig struct {
+	Name           string               `json:"Name" yaml:"Name"`
+	Cpu            float64              `json:"Cpu" yaml:"Cpu"`
+	Memory         float64              `json:"Memory" yaml:"Memory"`
+	Accelerators   int                  `json:"Accelerators" yaml:"Accelerators"`     // Accelerators required per replica
+	Storage        float64              `json:"Storage" yaml:"Storage"`               // Storage in GB required per replica
+	ImageSize      float64              `json:"ImageSize" yaml:"ImageSize"`           // Size of the image in MB pulled from the registry
+	StartupTime    float64              `json:"StartupTime" yaml:"StartupTime"`       // Seconds from the pulled image until running
+	ShutdownTime   float64              `json:"ShutdownTime" yaml:"ShutdownTime"`     // Seconds from terminating until removed
+	Replicas       int                  `json:"Replicas" yaml:"Replicas"`             // Number of replicas, default 1
+	DeploymentType string               `json:"DeploymentType" yaml:"DeploymentType"` // Placement, default "min-latency" with users, else "first-fit"
+	ComputingType  types.ComputingType  `json:"ComputingType" yaml:"ComputingType"`   // Only nodes of this computing type, empty for all
+	NodeSelector   NodeSelectorConfig   `json:"NodeSelector" yaml:"NodeSelector"`     // Only nodes matching all given labels
+	AntiAffinity   []string             `json:"AntiAffinity" yaml:"AntiAffinity"`     // Services whose replicas must not share a node
+	Users          []UserLocationConfig `json:"Users" yaml:"Users"`                   // Locations of the users the replicas are placed close to
+	Start          time.Time            `json:"Start" yaml:"Start"`                   // Time of the deployment, zero for the simulation start
+	Duration       float64              `json:"Duration" yaml:"Duration"`             // Seconds until the deployment is deleted, 0 to keep it
+	Migration      *MigrationConfig     `json:"Migration" yaml:"Migration"`           // Migration of the replicas following their users (optional)
+	Autoscaling    *AutoscalingConfig   `json:"Autoscaling" yaml:"Autoscaling"`       // Scaling of the replicas to keep the SLOs of the users (optional)
+}
+
+// MigrationConfig moves a replica to a new host when it drifts away from its users.
+// The users of a replica are the users it is the closest replica for.
+type MigrationConfig struct {
+	LatencyThreshold float64 `json:"LatencyThreshold" yaml:"LatencyThreshold"` // Mean latency in ms of the users above which the replica migrates, 0 to disable
+	MinElevation     float64 `json:"MinElevation" yaml:"MinElevation"`         // Elevation in degrees above which a satellite covers a user
+	CoverageHorizon  float64 `json:"CoverageHorizon" yaml:"CoverageHorizon"`   // Seconds ahead a satellite host is checked to leave the coverage of all its users, 0 to disable
+	StateSize        float64 `json:"StateSize" yaml:"StateSize"`               // State in MB transferred from the old to the new host
+	CutoverTime      float64 `json:"CutoverTime" yaml:"CutoverTime"`           // Seconds no replica serves while switching to the new host
+}
+
+// NodeSelectorConfig selects nodes by their labels. A node matches if it matches all given criteria.
+type NodeSelectorConfig struct {
+	NodeType string `json:"NodeType" yaml:"NodeType"` // "ground" or "satellite", empty for both
+	Tag      string `json:"Tag" yaml:"Tag"`           // Tag of the ground station, e.g. "gateway"
+	NodeName string `json:"NodeName" yaml:"NodeName"` // Regular expression matched against the node name
+}
+
+// UserLocationConfig locates users at a ground station or at coordinates, which are served by the closest ground station.
+type UserLocationConfig struct {
+	GroundStation string  `json:"GroundStation" yaml:"GroundStation"` // Name of the ground station, coordinates are ignored if set
+	Latitude      float64 `json:"Latitude" yaml:"Latitude"`
+	Longitude     float64 `json:"Longitude" yaml:"Longitude"`
+}
+
+// AutoscalingConfig keeps the latency of the clients and the utilization of the replicas within their SLOs
+// by adding and removing replicas. The clients are the users of the service.
+type AutoscalingConfig struct {
+	LatencySLO           float64 `json:"LatencySLO" yaml:"LatencySLO"`                     // Latency in ms the percentile of the clients must not exceed, 0 to disable
+	LatencyPercentile    float64 `json:"LatencyPercentile" yaml:"LatencyPercentile"`       // Percentile of the client latencies, default 95
+	UtilizationSLO       float64 `json:"UtilizationSLO" yaml:"UtilizationSLO"`             // Utilization no replica must exceed, default 0.8
+	ScaleDownUtilization float64 `json:"ScaleDownUtilization" yaml:"ScaleDownUtilization"` // Replicas are removed if the utilization stays below, default half the UtilizationSLO
+	RequestRate          float64 `json:"RequestRate" yaml:"RequestRate"`                   // Requests per second of every client
+	ReplicaCapacity      float64 `json:"ReplicaCapacity" yaml:"ReplicaCapacity"`           // Requests per second a replica serves, 0 to ignore the utilization
+	MinReplicas          int     `json:"MinReplicas" yaml:"MinReplicas"`                   // Default 1
+	MaxReplicas          int     `json:"MaxReplicas" yaml:"MaxReplicas"`                   // 0 for no limit
+	ScaleUpCooldown      float64 `json:"ScaleUpCooldown" yaml:"ScaleUpCooldown"`           // Seconds after scaling before the next scale up
+	ScaleDownCooldown    float64 `json:"ScaleDownCooldown" yaml:"ScaleDownCooldown"`       // Seconds after scaling before the next scale down
+}
+
+// OffloadConfig declares the tasks clients offload to nodes during the simulation.
+type OffloadConfig struct {
+	Workloads []OffloadWorkloadConfig `json:"Workloads" yaml:"Workloads"`
+	File      string                  `json:"File" yaml:"File"` // CSV output of the response time of every completed task
+}
+
+// OffloadWorkloadConfig declares tasks issued periodically by clients and executed on a target node.
+type OffloadWorkloadConfig struct {
+	Name     string   `json:"Name" yaml:"Name"`
+	Clients  []string `json:"Clients" yaml:"Clients"`   // Names of the nodes issuing the tasks
+	Target   string   `json:"Target" yaml:"Target"`     // "local", "edge" or "cloud" for the closest other node of the type, or the name of a node
+	Work     float64  `json:"Work" yaml:"Work"`         // CPU time in core seconds per task
+	Interval float64  `json:"Interval" yaml:"Interval"` // Seconds between two tasks of a client
+}
+
+// FaasConfig declares the functions of the serverless platform and the clients invoking them.
+type FaasConfig struct {
+	Placement   string             `json:"Placement" yaml:"Placement"` // Node of an invocation: "closest" (default), "warm-first" or "least-loaded"
+	Seed        int64              `json:"Seed" yaml:"Seed"`           // Seed of the Poisson arrivals
+	Functions   []FunctionConfig   `json:"Functions" yaml:"Functions"`
+	Invocations []InvocationConfig `json:"Invocations" yaml:"Invocations"`
+	File        string             `json:"File" yaml:"File"`               // CSV output of every completed invocation (optional)
+	SummaryFile string             `json:"SummaryFile" yaml:"SummaryFile"` // CSV output of latency percentiles, cold starts and evictions per function (optional)
+}
+
+// FunctionConfig declares a function, every instance serves one invocation at a time.
+type FunctionConfig struct {
+	Name            string              `json:"Name" yaml:"Name"`
+	Memory          float64             `json:"Memory" yaml:"Memory"`                   // Memory in MB per instance
+	ColdStart       float64             `json:"ColdStart" yaml:"ColdStart"`             // Seconds to start an instance
+	Execution       float64             `json:"Execution" yaml:"Execution"`             // Seconds of an execution on a warm instance
+	KeepAlive       float64             `json:"KeepAlive" yaml:"KeepAlive"`             // Seconds an idle instance is kept warm, the upper bound of the adaptive policy
+	KeepAlivePolicy string              `json:"KeepAlivePolicy" yaml:"KeepAlivePolicy"` // "fixed" (default), "adaptive" or "none"
+	MaxInstances    int                 `json:"MaxInstances" yaml:"MaxInstances"`       // Concurrent instances per node, 0 for unlimited
+	ComputingType   types.ComputingType `json:"ComputingType" yaml:"ComputingType"`     // Only nodes of this computing type, empty for all
+	NodeSelector    NodeSelectorConfig  `json:"NodeSelector" yaml:"NodeSelector"`       // Only nodes matching all given labels
+}
+
+// InvocationConfig declares clients invoking a function.
+type InvocationConfig struct {
+	Function string   `json:"Function" yaml:"Function"`
+	Clients  []string `json:"Clients" yaml:"Clients"`   // Names of the nodes invoking the function
+	Rate     float64  `json:"Rate" yaml:"Rate"`         // Invocations per second of each client
+	Arrivals string   `json:"Arrivals" yaml:"Arrivals"` // "poisson" (default) or "periodic"
+}
+
+// AppConfig declares the apps run by the app runtime during the simulation.
+type AppConfig struct {
+	Apps []AppDeploymentConfig `json:"Apps" yaml:"Apps"`
+	File string                `json:"File" yaml:"File"` // CSV output of the round-trip times of the ping apps per step (optional)
+}
+
+// AppDeploymentConfig deploys an app on a node.
+type AppDeploymentConfig struct {
+	App      string  `json:"App" yaml:"App"`           // "ping" or "echo"
+	Node     string  `json:"Node" yaml:"Node"`         // Name of the node running the app
+	Service  string  `json:"Service" yaml:"Service"`   // Service pinged by "ping", provided by "echo"
+	Interval int     `json:"Interval" yaml:"Interval"` // Steps between the pings of "ping", 1 if not set
+	Cpu      float64 `json:"Cpu" yaml:"Cpu"`           // CPU reserved on the node
+	Memory   float64 `json:"Memory" yaml:"Memory"`     // Memory reserved on the node in MB
+}
+
+// SloConfig declares service level objectives evaluated after every simulation step.
+type SloConfig struct {
+	Objectives    []SloDefinitionConfig `json:"Objectives" yaml:"Objectives"`
+	TimelineFile  string                `json:"TimelineFile" yaml:"TimelineFile"`   // CSV output of the evaluation of every SLO per step (optional)
+	ViolationFile string                `json:"ViolationFile" yaml:"ViolationFile"` // CSV output of the violations and their durations (optional)
+	SummaryFile   string                `json:"SummaryFile" yaml:"SummaryFile"`     // CSV output of the availability per SLO (optional)
+}
+
+// SloDefinitionConfig declares an SLO on the route latencies between two groups of nodes.
+type SloDefinitionConfig struct {
+	Name       string            `json:"Name" yaml:"Name"`
+	Type       string            `json:"Type" yaml:"Type"`             // "latency" (default) or "reachability"
+	From       SloEndpointConfig `json:"From" yaml:"From"`             // Nodes the routes start at
+	To         SloEndpointConfig `json:"To" yaml:"To"`                 // Nodes or service the routes lead to
+	Threshold  float64           `json:"Threshold" yaml:"Threshold"`   // Latency in ms which must not be exceeded
+	Percentile float64           `json:"Percentile" yaml:"Percentile"` // Percentile of the route latencies of a latency SLO, default 95
+	Window     float64           `json:"Window" yaml:"Window"`         // Seconds of past route latencies in the percentile, 0 for the current step
+}
+
+// SloEndpointConfig selects the nodes at one end of the routes of an SLO. A node is selected if it matches all given criteria.
+type SloEndpointConfig struct {
+	Nodes         []string            `json:"Nodes" yaml:"Nodes"`                 // Names of the nodes, empty for all
+	NodeSelector  NodeSelectorConfig  `json:"NodeSelector" yaml:"NodeSelector"`   // Only nodes matching all given labels
+	ComputingType types.ComputingType `json:"ComputingType" yaml:"ComputingType"` // Only nodes of this computing type, empty for all
+	Service       string              `json:"Service" yaml:"Service"`             // Route to the service instead of nodes, only for To
+}
+
+// ComputingConfig is a hardware profile. A node gets the profile of its mapping file entry, else the first profile
+// whose NodeSelector and Shell match it, else the first profile of its computing type.
 type ComputingConfig struct {
-	Cores  int                 `json:"Cores" yaml:"Cores"`
-	Memory int                 `json:"Memory" yaml:"Memory"`
-	Type   types.ComputingType `json:"Type" yaml:"Type"` // Should be either "Edge" or "Cloud"
+	Name         string              `json:"Name" yaml:"Name"` // Name of the profile, required for mapping files
+	Cores        int                 `json:"Cores" yaml:"Cores"`
+	Memory       int                 `json:"Memory" yaml:"Memory"`
+	Accelerators int                 `json:"Accelerators" yaml:"Accelerators"` // Number of GPUs or other accelerators
+	Storage      float64             `json:"Storage" yaml:"Storage"`           // Storage capacity in GB
+	Power        float64             `json:"Power" yaml:"Power"`               // Power draw in W
+	Type         types.ComputingType `json:"Type" yaml:"Type"`                 // Should be either "Edge" or "Cloud"
+	Scheduling   string              `json:"Scheduling" yaml:"Scheduling"`     // Scheduling of tasks on the cores: "processor-sharing" (default) or "fifo"
+	NodeSelector NodeSelectorConfig  `json:"NodeSelector" yaml:"NodeSelector"` // Nodes matching all given labels get the profile (optional)
+	Shell        *ShellConfig        `json:"Shell" yaml:"Shell"`               // Satellites of the orbital shell get the profile (optional)
+}
+
+// ShellConfig selects the satellites of an orbital shell by inclination and altitude, zero bounds are open.
+type ShellConfig struct {
+	MinInclination float64 `json:"MinInclination" yaml:"MinInclination"` // in degrees
+	MaxInclination float64 `json:"MaxInclination" yaml:"MaxInclination"` // in degrees
+	MinAltitude    float64 `json:"MinAltitude" yaml:"MinAltitude"`       // in km
+	MaxAltitude    float64 `json:"MaxAltitude" yaml:"MaxAltitude"`       // in km
 }
 
 // LoadConfigFromFile loads a configuration of type T from a file.
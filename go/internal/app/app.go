@@ -0,0 +1,78 @@
+package app
+
+import (
+	"time"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// App is an application running on a node. It is deployed as service with the name of the app,
+// so other apps can address it by node or by service name.
+type App interface {
+	// Name returns the name of the app, which is also its service name
+	Name() string
+
+	// Start is called once at the first simulation step after the app has been deployed
+	Start(ctx *Context) error
+
+	// Tick is called at every simulation step
+	Tick(ctx *Context) error
+
+	// Receive is called when a message arrives, the context time is the arrival time
+	Receive(ctx *Context, msg *Message) error
+}
+
+// BaseApp implements the callbacks of App without doing anything, embed it to implement only the needed ones.
+type BaseApp struct{}
+
+func (BaseApp) Start(*Context) error { return nil }
+
+func (BaseApp) Tick(*Context) error { return nil }
+
+func (BaseApp) Receive(*Context, *Message) error { return nil }
+
+// Message is sent from an app on one node to an app on another node.
+type Message struct {
+	ID        uint64
+	From      types.Node
+	FromApp   string
+	To        types.Node
+	ToApp     string
+	Payload   any
+	SentAt    time.Time
+	DeliverAt time.Time // arrival time, the send time plus the route latency
+}
+
+// Latency returns the time the message travels.
+func (m *Message) Latency() time.Duration {
+	return m.DeliverAt.Sub(m.SentAt)
+}
+
+// Context gives an app access to its node, the simulation time and the network.
+type Context struct {
+	runtime *Runtime
+	app     *deployedApp
+	now     time.Time
+}
+
+// Node returns the node the app runs on.
+func (c *Context) Node() types.Node {
+	return c.app.node
+}
+
+// Now returns the current simulation time.
+func (c *Context) Now() time.Time {
+	return c.now
+}
+
+// Send sends the payload to the app with the given name on the target node.
+// It returns ErrUnreachable if the router finds no route or fails, the message is dropped then.
+func (c *Context) Send(target types.Node, app string, payload any) error {
+	return c.runtime.send(c, target, app, payload)
+}
+
+// SendToService sends the payload to the closest node hosting the service, to the app of the same name.
+// It returns ErrUnreachable if the service is not reachable, the message is dropped then.
+func (c *Context) SendToService(service string, payload any) error {
+	return c.runtime.sendToService(c, service, payload)
+}
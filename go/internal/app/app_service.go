@@ -0,0 +1,31 @@
+package app
+
+import "github.com/keniack/stardustGo/pkg/types"
+
+var _ types.DeployableService = (*appService)(nil)
+
+// appService is the service an app occupies on the computing unit of its node.
+type appService struct {
+	name     string
+	cpu      float64
+	memory   float64
+	deployed bool
+}
+
+func (s *appService) GetServiceName() string { return s.name }
+
+func (s *appService) GetCpuUsage() float64 { return s.cpu }
+
+func (s *appService) GetMemoryUsage() float64 { return s.memory }
+
+func (s *appService) IsDeployed() bool { return s.deployed }
+
+func (s *appService) Deploy() error {
+	s.deployed = true
+	return nil
+}
+
+func (s *appService) Remove() error {
+	s.deployed = false
+	return nil
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	_ App = (*PingApp)(nil)
+	_ App = (*EchoApp)(nil)
+)
+
+// Ping is the payload exchanged by PingApp and EchoApp.
+type Ping struct {
+	Seq    int
+	SentAt time.Time
+}
+
+// PingApp is an example app which sends a ping to the closest replica of a service every Interval ticks
+// and measures the round-trip times of the echoed pings.
+type PingApp struct {
+	BaseApp
+
+	Service  string // service answering the pings, e.g. an EchoApp
+	Interval int    // ticks between pings, 1 if not set
+
+	ticks int
+	seq   int
+	rtts  []time.Duration
+	lost  int
+}
+
+func (a *PingApp) Name() string {
+	return "ping"
+}
+
+// Tick sends the next ping
+func (a *PingApp) Tick(ctx *Context) error {
+	a.ticks++
+	if a.Interval > 1 && (a.ticks-1)%a.Interval != 0 {
+		return nil
+	}
+	a.seq++
+	err := ctx.SendToService(a.Service, Ping{Seq: a.seq, SentAt: ctx.Now()})
+	if errors.Is(err, ErrUnreachable) {
+		a.lost++
+		return nil
+	}
+	return err
+}
+
+// Receive records the round-trip time of an echoed ping
+func (a *PingApp) Receive(ctx *Context, msg *Message) error {
+	if ping, ok := msg.Payload.(Ping); ok {
+		a.rtts = append(a.rtts, ctx.Now().Sub(ping.SentAt))
+	}
+	return nil
+}
+
+// RoundTripTimes returns the round-trip times of the answered pings.
+func (a *PingApp) RoundTripTimes() []time.Duration {
+	return a.rtts
+}
+
+// Lost returns the number of pings which could not be sent as the service was unreachable.
+func (a *PingApp) Lost() int {
+	return a.lost
+}
+
+// EchoApp is an example app which sends every message back to its sender.
+type EchoApp struct {
+	BaseApp
+
+	Service string // name of the app, i.e. the service it provides
+}
+
+func (a *EchoApp) Name() string {
+	return a.Service
+}
+
+// Receive echoes the message
+func (a *EchoApp) Receive(ctx *Context, msg *Message) error {
+	err := ctx.Send(msg.From, msg.FromApp, msg.Payload)
+	if errors.Is(err, ErrUnreachable) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,291 @@
+package app
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/keniack/stardustGo/internal/routing"
+	"github.com/keniack/stardustGo/pkg/helper"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var _ types.SimulationPlugin = (*Runtime)(nil)
+
+// ErrUnreachable is returned when a message is dropped as its target is not reachable.
+var ErrUnreachable = errors.New("target unreachable")
+
+// Reasons of dropped messages
+const (
+	DropUnreachable = "unreachable" // the router found no route or failed to route at send time
+	DropNoApp       = "no-app"      // the target app was not deployed on the target node at arrival
+)
+
+// MessageStats count the messages of all apps.
+type MessageStats struct {
+	Sent      int
+	Delivered int
+	Dropped   map[string]int // dropped messages by reason
+}
+
+// deployedApp is an app running on a node.
+type deployedApp struct {
+	node    types.Node
+	app     App
+	service *appService
+	started bool
+}
+
+// Runtime runs the apps deployed on the nodes and delivers their messages.
+// Messages are delayed by the route latency at send time and delivered in time order;
+// the apps tick once per simulation step.
+type Runtime struct {
+	apps   []*deployedApp
+	byNode map[types.Node]map[string]*deployedApp
+
+	now    time.Time
+	queue  messageQueue
+	nextID uint64
+	stats  MessageStats
+}
+
+// NewRuntime creates an app runtime without apps.
+func NewRuntime() *Runtime {
+	return &Runtime{
+		byNode: make(map[types.Node]map[string]*deployedApp),
+		stats:  MessageStats{Dropped: make(map[string]int)},
+	}
+}
+
+func (r *Runtime) Name() string {
+	return "AppRuntime"
+}
+
+// PostSimulationStep delivers the messages up to the simulation time and ticks the apps
+func (r *Runtime) PostSimulationStep(simulation types.SimulationController) error {
+	return r.RunUntil(simulation.GetSimulationTime())
+}
+
+// Deploy places the app with the given resource requirements on the computing unit of the node,
+// which advertises it as service. The app starts at the next simulation step.
+func (r *Runtime) Deploy(node types.Node, app App, cpu, memory float64) error {
+	name := app.Name()
+	if name == "" {
+		return errors.New("app requires a name")
+	}
+	if _, exists := r.byNode[node][name]; exists {
+		return fmt.Errorf("app %s is already deployed on %s", name, node.GetName())
+	}
+
+	// Apps run at once, so the service is deployed before placement advertises it
+	service := &appService{name: name, cpu: cpu, memory: memory}
+	if err := service.Deploy(); err != nil {
+		return err
+	}
+	placed, err := node.GetComputing().TryPlaceDeploymentAsync(service)
+	if err != nil {
+		return err
+	}
+	if !placed {
+		return fmt.Errorf("app %s cannot be placed on %s", name, node.GetName())
+	}
+
+	d := &deployedApp{node: node, app: app, service: service}
+	r.apps = append(r.apps, d)
+	if r.byNode[node] == nil {
+		r.byNode[node] = make(map[string]*deployedApp)
+	}
+	r.byNode[node][name] = d
+	return nil
+}
+
+// Undeploy removes the app from the node and withdraws its service. Messages on the way to it are dropped.
+func (r *Runtime) Undeploy(node types.Node, name string) error {
+	d, ok := r.byNode[node][name]
+	if !ok {
+		return fmt.Errorf("app %s is not deployed on %s", name, node.GetName())
+	}
+	delete(r.byNode[node], name)
+	for i, a := range r.apps {
+		if a == d {
+			r.apps = append(r.apps[:i], r.apps[i+1:]...)
+			break
+		}
+	}
+	if err := d.service.Remove(); err != nil {
+		return err
+	}
+	return node.GetComputing().RemoveDeploymentAsync(d.service)
+}
+
+// App returns the app with the given name on the node.
+func (r *Runtime) App(node types.Node, name string) (App, bool) {
+	d, ok := r.byNode[node][name]
+	if !ok {
+		return nil, false
+	}
+	return d.app, true
+}
+
+// Stats returns the message statistics so far.
+func (r *Runtime) Stats() MessageStats {
+	stats := r.stats
+	stats.Dropped = make(map[string]int, len(r.stats.Dropped))
+	for reason, count := range r.stats.Dropped {
+		stats.Dropped[reason] = count
+	}
+	return stats
+}
+
+// RunUntil delivers all messages arriving up to the given time, then starts new apps and ticks all apps.
+// Messages sent meanwhile are delivered as well if they arrive up to this time.
+func (r *Runtime) RunUntil(t time.Time) error {
+	if err := r.deliverUntil(t); err != nil {
+		return err
+	}
+	r.now = t
+
+	apps := append([]*deployedApp(nil), r.apps...)
+	for _, d := range apps {
+		ctx := &Context{runtime: r, app: d, now: t}
+		if !d.started {
+			d.started = true
+			if err := d.app.Start(ctx); err != nil {
+				return fmt.Errorf("start of app %s on %s: %w", d.app.Name(), d.node.GetName(), err)
+			}
+		}
+		if err := d.app.Tick(ctx); err != nil {
+			return fmt.Errorf("tick of app %s on %s: %w", d.app.Name(), d.node.GetName(), err)
+		}
+	}
+	return r.deliverUntil(t)
+}
+
+// deliverUntil delivers the messages arriving up to the given time in time order.
+func (r *Runtime) deliverUntil(t time.Time) error {
+	for r.queue.Len() > 0 && !r.queue.msgs[0].DeliverAt.After(t) {
+		msg := heap.Pop(&r.queue).(*Message)
+		r.now = msg.DeliverAt
+
+		d, ok := r.byNode[msg.To][msg.ToApp]
+		if !ok {
+			r.stats.Dropped[DropNoApp]++
+			continue
+		}
+		r.stats.Delivered++
+		if err := d.app.Receive(&Context{runtime: r, app: d, now: msg.DeliverAt}, msg); err != nil {
+			return fmt.Errorf("receive of app %s on %s: %w", d.app.Name(), d.node.GetName(), err)
+		}
+	}
+	return nil
+}
+
+func (r *Runtime) send(ctx *Context, target types.Node, app string, payload any) error {
+	if target == ctx.app.node {
+		return r.enqueue(ctx, target, app, payload, 0)
+	}
+	result, err := r.router(ctx).RouteToNode(target, payload)
+	if err != nil {
+		return r.dropUnreachable(err)
+	}
+	return r.enqueueRoute(ctx, target, app, payload, result)
+}
+
+func (r *Runtime) sendToService(ctx *Context, service string, payload any) error {
+	result, err := r.router(ctx).RouteToService(service, payload)
+	if err != nil {
+		return r.dropUnreachable(err)
+	}
+	if !result.Reachable() {
+		return r.dropUnreachable(nil)
+	}
+
+	// Resolve the node hosting the replica, routers answer without route if the sender hosts the service
+	target, ok := routing.Destination(result)
+	if !ok {
+		if !ctx.app.node.GetComputing().HostsService(service) {
+			return r.dropUnreachable(fmt.Errorf("route result %T does not name the replica of %s", result, service))
+		}
+		target = ctx.app.node
+	}
+	return r.enqueueRoute(ctx, target, service, payload, result)
+}
+
+// router returns the router of the sending app, moved to the time of the message.
+func (r *Runtime) router(ctx *Context) types.Router {
+	router := ctx.app.node.GetRouter()
+	if timed, ok := router.(types.TimedRouter); ok {
+		timed.AdvanceTo(ctx.now)
+	}
+	return router
+}
+
+// dropUnreachable counts a message dropped as the router found no route, or failed with the given error.
+// The returned error matches ErrUnreachable, so a routing failure does not abort the apps.
+func (r *Runtime) dropUnreachable(err error) error {
+	r.stats.Sent++
+	r.stats.Dropped[DropUnreachable]++
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnreachable, err)
+	}
+	return ErrUnreachable
+}
+
+// enqueueRoute schedules the message with the latency of the route, or drops it if the route is unreachable.
+func (r *Runtime) enqueueRoute(ctx *Context, target types.Node, app string, payload any, result types.RouteResult) error {
+	if !result.Reachable() {
+		return r.dropUnreachable(nil)
+	}
+	return r.enqueue(ctx, target, app, payload, routeLatency(result, ctx.now))
+}
+
+func (r *Runtime) enqueue(ctx *Context, target types.Node, app string, payload any, latency time.Duration) error {
+	r.nextID++
+	r.stats.Sent++
+	heap.Push(&r.queue, &Message{
+		ID:        r.nextID,
+		From:      ctx.app.node,
+		FromApp:   ctx.app.app.Name(),
+		To:        target,
+		ToApp:     app,
+		Payload:   payload,
+		SentAt:    ctx.now,
+		DeliverAt: ctx.now.Add(latency),
+	})
+	return nil
+}
+
+// routeLatency returns the most exact latency the route result knows, contact routes deliver at their expected time.
+func routeLatency(result types.RouteResult, now time.Time) time.Duration {
+	if res, ok := result.(*routing.ContactRouteResult); ok && res.ExpectedDeliveryTime().After(now) {
+		return res.ExpectedDeliveryTime().Sub(now)
+	}
+	return helper.Milliseconds(routing.ResultLatency(result))
+}
+
+// messageQueue is a min-heap of messages ordered by arrival time, then by send order.
+type messageQueue struct {
+	msgs []*Message
+}
+
+func (q *messageQueue) Len() int { return len(q.msgs) }
+
+func (q *messageQueue) Less(i, j int) bool {
+	if q.msgs[i].DeliverAt.Equal(q.msgs[j].DeliverAt) {
+		return q.msgs[i].ID < q.msgs[j].ID
+	}
+	return q.msgs[i].DeliverAt.Before(q.msgs[j].DeliverAt)
+}
+
+func (q *messageQueue) Swap(i, j int) { q.msgs[i], q.msgs[j] = q.msgs[j], q.msgs[i] }
+
+func (q *messageQueue) Push(x any) { q.msgs = append(q.msgs, x.(*Message)) }
+
+func (q *messageQueue) Pop() any {
+	last := len(q.msgs) - 1
+	m := q.msgs[last]
+	q.msgs[last] = nil
+	q.msgs = q.msgs[:last]
+	return m
+}
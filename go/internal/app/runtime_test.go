@@ -0,0 +1,211 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/keniack/stardustGo/internal/computing"
+	"github.com/keniack/stardustGo/internal/routing"
+	"github.com/keniack/stardustGo/internal/simtest"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var start = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// line mounts QoS routers and computing units on A-B-C with links of 1 ms, D has no links.
+func line(t *testing.T) []*simtest.Node {
+	t.Helper()
+	engine := routing.NewRoutingEngine(nil)
+	adverts := routing.NewServiceAdvertisementPlane()
+	var nodes []*simtest.Node
+	for _, name := range []string{"A", "B", "C", "D"} {
+		router, err := routing.NewQosRouter(engine, adverts, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		nodes = append(nodes, mount(t, name, router))
+	}
+	simtest.Connect(nodes[0], nodes[1], 1, 1e9)
+	simtest.Connect(nodes[1], nodes[2], 1, 1e9)
+	return nodes
+}
+
+func mount(t *testing.T, name string, router types.Router) *simtest.Node {
+	t.Helper()
+	n := simtest.NewNode(name)
+	if err := n.Mount(router, computing.NewComputing(8, 1024, types.Edge)); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+// recorder logs the payloads it receives, and sends the messages given by the test when it starts.
+type recorder struct {
+	BaseApp
+	name     string
+	log      *[]string
+	messages []func(ctx *Context) error
+}
+
+func (a *recorder) Name() string { return a.name }
+
+func (a *recorder) Start(ctx *Context) error {
+	for _, send := range a.messages {
+		if err := send(ctx); err != nil && !errors.Is(err, ErrUnreachable) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *recorder) Receive(ctx *Context, msg *Message) error {
+	*a.log = append(*a.log, fmt.Sprintf("%v@%s+%v", msg.Payload, ctx.Node().GetName(), ctx.Now().Sub(start)))
+	return nil
+}
+
+func TestRuntimeDelivery(t *testing.T) {
+	nodes := line(t)
+	a, b, c, d := nodes[0], nodes[1], nodes[2], nodes[3]
+	var log []string
+	sender := &recorder{name: "sender", log: &log, messages: []func(ctx *Context) error{
+		func(ctx *Context) error { return ctx.Send(c, "rec", "first") },
+		func(ctx *Context) error { return ctx.Send(b, "rec", "second") },
+		func(ctx *Context) error { return ctx.Send(b, "rec", "third") },
+		func(ctx *Context) error { return ctx.Send(d, "rec", "unreachable") },
+		func(ctx *Context) error { return ctx.Send(b, "missing", "no app") },
+		func(ctx *Context) error { return ctx.SendToService("rec", "closest") },
+	}}
+
+	r := NewRuntime()
+	for _, deploy := range []struct {
+		node *simtest.Node
+		app  App
+	}{
+		{a, sender},
+		{b, &recorder{name: "rec", log: &log}},
+		{c, &recorder{name: "rec", log: &log}},
+		{d, &recorder{name: "rec", log: &log}},
+	} {
+		if err := r.Deploy(deploy.node, deploy.app, 1, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.RunUntil(start); err != nil {
+		t.Fatal(err)
+	}
+	if len(log) != 0 {
+		t.Errorf("messages delivered before their latency: %v", log)
+	}
+	if err := r.RunUntil(start.Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Messages arrive by latency, then in send order
+	want := []string{"second@B+1ms", "third@B+1ms", "closest@B+1ms", "first@C+2ms"}
+	if !slices.Equal(log, want) {
+		t.Errorf("delivered %v, want %v", log, want)
+	}
+	stats := r.Stats()
+	if stats.Sent != 6 || stats.Delivered != 4 || stats.Dropped[DropUnreachable] != 1 || stats.Dropped[DropNoApp] != 1 {
+		t.Errorf("stats = %+v, want 6 sent, 4 delivered, 1 unreachable and 1 without app", stats)
+	}
+}
+
+func TestRuntimeUndeploy(t *testing.T) {
+	nodes := line(t)
+	var log []string
+	r := NewRuntime()
+	sender := &recorder{name: "sender", log: &log, messages: []func(ctx *Context) error{
+		func(ctx *Context) error { return ctx.SendToService("rec", "closest") },
+	}}
+	if err := r.Deploy(nodes[0], sender, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range nodes[1:3] {
+		if err := r.Deploy(n, &recorder{name: "rec", log: &log}, 1, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The withdrawn replica on B is no longer the closest one
+	if err := r.Undeploy(nodes[1], "rec"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Undeploy(nodes[1], "rec"); err == nil {
+		t.Error("app undeployed twice")
+	}
+	for _, at := range []time.Time{start, start.Add(time.Second)} {
+		if err := r.RunUntil(at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []string{"closest@C+2ms"}; !slices.Equal(log, want) {
+		t.Errorf("delivered %v, want %v", log, want)
+	}
+}
+
+// stubRouter answers all service routes with the same result.
+type stubRouter struct {
+	types.Router
+	result types.RouteResult
+}
+
+func (r *stubRouter) Mount(types.Node) error                { return nil }
+func (r *stubRouter) AdvertiseNewServiceAsync(string) error { return nil }
+
+func (r *stubRouter) RouteToService(string, types.Payload) (types.RouteResult, error) {
+	return r.result, nil
+}
+
+func TestRuntimeServiceReplica(t *testing.T) {
+	contact := func(target types.Node) types.RouteResult {
+		relay := simtest.NewNode("relay")
+		return routing.NewContactRouteResult(5000, start.Add(5*time.Second), []routing.Contact{
+			{From: simtest.NewNode("source"), To: relay},
+			{From: relay, To: target},
+		})
+	}
+	tests := []struct {
+		name   string
+		result func(target types.Node) types.RouteResult
+		want   []string
+	}{
+		{"contact route", contact, []string{"stored@target+5s"}},
+		{"route without replica", func(types.Node) types.RouteResult { return routing.NewPreRouteResult(1) }, nil},
+		{"unreachable", func(types.Node) types.RouteResult { return routing.UnreachableRouteResultInstance }, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := mount(t, "target", nil)
+			var log []string
+			var sendErr error
+			sender := &recorder{name: "sender", log: &log, messages: []func(ctx *Context) error{
+				func(ctx *Context) error {
+					sendErr = ctx.SendToService("rec", "stored")
+					return nil
+				},
+			}}
+			r := NewRuntime()
+			if err := r.Deploy(mount(t, "source", &stubRouter{result: tt.result(target)}), sender, 1, 1); err != nil {
+				t.Fatal(err)
+			}
+			if err := r.Deploy(target, &recorder{name: "rec", log: &log}, 1, 1); err != nil {
+				t.Fatal(err)
+			}
+			for _, at := range []time.Time{start, start.Add(time.Minute)} {
+				if err := r.RunUntil(at); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if !slices.Equal(log, tt.want) {
+				t.Errorf("delivered %v, want %v", log, tt.want)
+			}
+			if dropped := errors.Is(sendErr, ErrUnreachable); dropped != (tt.want == nil) {
+				t.Errorf("send error = %v", sendErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package capacity
+
+import (
+	"time"
+
+	"github.com/keniack/stardustGo/internal/routing"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// maxHops bounds routes, longer routes are taken as routing loops.
+const maxHops = 64
+
+// Route returns the directed links a payload takes from source to target at the given time,
+// as chosen by the routers of the nodes: hop by hop for forwarding routers, along the whole path
+// for routers returning one. It reports false if the target is unreachable, the route loops or
+// a store-and-forward route waits for a future contact.
+func Route(source, target types.Node, payload types.Payload, now time.Time) ([]DirectedLink, bool, error) {
+	var links []DirectedLink
+	visited := map[types.Node]bool{}
+	at := source
+	for at != target {
+		if visited[at] || len(links) >= maxHops {
+			return nil, false, nil
+		}
+		visited[at] = true
+
+		router := at.GetRouter()
+		if fwd, ok := router.(types.ForwardingRouter); ok {
+			link, err := fwd.NextHop(target, payload)
+			if err != nil || link == nil {
+				return nil, false, err
+			}
+			links = append(links, DirectedLink{Link: link, From: at})
+			at = link.GetOther(at)
+			continue
+		}
+
+		result, err := router.RouteToNode(target, payload)
+		if err != nil {
+			return nil, false, err
+		}
+		switch r := result.(type) {
+		case *routing.PathRouteResult:
+			path := r.Path()
+			for i, link := range path.Links {
+				links = append(links, DirectedLink{Link: link, From: path.Nodes[i]})
+			}
+			return links, len(path.Links) > 0, nil
+		case *routing.ContactRouteResult:
+			for _, c := range r.Contacts() {
+				if c.Start.After(now) {
+					return nil, false, nil
+				}
+				links = append(links, DirectedLink{Link: c.Link, From: c.From})
+			}
+			return links, len(r.Contacts()) > 0, nil
+		}
+		return nil, false, nil
+	}
+	return links, true, nil
+}
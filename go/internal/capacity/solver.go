@@ -0,0 +1,294 @@
+package capacity
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// Fairness criteria of the rate allocation
+const (
+	MaxMinFairness       = "max-min"      // no flow can get more without taking from a flow with a lower rate
+	ProportionalFairness = "proportional" // maximizes the sum of the logarithms of the rates
+)
+
+// Iterations of the proportional fair price updates
+const proportionalIterations = 2000
+
+// saturation is the relative remaining capacity below which a link counts as saturated.
+const saturation = 1e-9
+
+// DirectedLink is a link in the direction from one of its nodes. Links are full duplex,
+// both directions have the link bandwidth.
+type DirectedLink struct {
+	Link types.Link
+	From types.Node
+}
+
+// To returns the receiving node.
+func (d DirectedLink) To() types.Node {
+	return d.Link.GetOther(d.From)
+}
+
+// Flow is the traffic of one demand along its route.
+type Flow struct {
+	ID     string
+	Links  []DirectedLink
+	Demand float64 // requested rate in bits per second, 0 for elastic flows taking whatever they get
+}
+
+// LinkLoad is the load of a directed link.
+type LinkLoad struct {
+	DirectedLink
+	Capacity float64 // bits per second
+	Load     float64 // bits per second allocated to the flows
+	Flows    int     // number of flows crossing the link
+}
+
+// Utilization returns the share of the capacity in use, e.g. 0.5 for half.
+func (l LinkLoad) Utilization() float64 {
+	if l.Capacity <= 0 {
+		return 0
+	}
+	return l.Load / l.Capacity
+}
+
+// Saturated reports if the link has no capacity left.
+func (l LinkLoad) Saturated() bool {
+	return l.Load >= l.Capacity*(1-1e-6)
+}
+
+// Allocation is the rate allocation of a set of flows.
+type Allocation struct {
+	Rates       []float64  // rate in bits per second per flow
+	Bottlenecks []int      // index of the link limiting the flow in Links, -1 if the flow gets its demand
+	Links       []LinkLoad // loaded links in order of first use
+}
+
+// Solve allocates rates to the flows sharing the link bandwidths by the fairness criterion.
+// Flows without links get their demand.
+func Solve(flows []Flow, fairness string) (*Allocation, error) {
+	s := newSolver(flows)
+	switch strings.ToLower(fairness) {
+	case "", MaxMinFairness:
+		s.maxMin()
+	case ProportionalFairness:
+		s.proportional()
+	default:
+		return nil, fmt.Errorf("unknown fairness: %s", fairness)
+	}
+	return s.allocation(), nil
+}
+
+// solver holds the flows with their links mapped to dense indices.
+type solver struct {
+	flows      []Flow
+	capacities []float64
+	links      []DirectedLink
+	flowLinks  [][]int // link indices per flow
+	linkFlows  [][]int // flow indices per link
+	rates      []float64
+	bottleneck []int
+}
+
+func newSolver(flows []Flow) *solver {
+	s := &solver{
+		flows:      flows,
+		flowLinks:  make([][]int, len(flows)),
+		rates:      make([]float64, len(flows)),
+		bottleneck: make([]int, len(flows)),
+	}
+	index := make(map[DirectedLink]int)
+	for f, flow := range flows {
+		s.bottleneck[f] = -1
+		for _, l := range flow.Links {
+			ix, ok := index[l]
+			if !ok {
+				ix = len(s.links)
+				index[l] = ix
+				s.links = append(s.links, l)
+				s.capacities = append(s.capacities, l.Link.Bandwidth())
+				s.linkFlows = append(s.linkFlows, nil)
+			}
+			s.flowLinks[f] = append(s.flowLinks[f], ix)
+			s.linkFlows[ix] = append(s.linkFlows[ix], f)
+		}
+	}
+	return s
+}
+
+// demand returns the demand of the flow, infinite for elastic flows.
+func (s *solver) demand(f int) float64 {
+	if s.flows[f].Demand > 0 {
+		return s.flows[f].Demand
+	}
+	return math.Inf(1)
+}
+
+// maxMin allocates by progressive filling: the rates of all unfrozen flows grow equally
+// until a link saturates or a flow reaches its demand, which freezes the affected flows.
+func (s *solver) maxMin() {
+	remaining := append([]float64(nil), s.capacities...)
+	active := make([]int, len(s.links)) // unfrozen flows per link
+	frozen := make([]bool, len(s.flows))
+	unfrozen := 0
+	for f := range s.flows {
+		if len(s.flowLinks[f]) == 0 {
+			frozen[f] = true
+			if s.flows[f].Demand > 0 {
+				s.rates[f] = s.flows[f].Demand
+			}
+			continue
+		}
+		unfrozen++
+		for _, l := range s.flowLinks[f] {
+			active[l]++
+		}
+	}
+
+	for unfrozen > 0 {
+		// Largest equal increase possible for all unfrozen flows
+		inc := math.Inf(1)
+		for l, n := range active {
+			if n > 0 {
+				inc = min(inc, remaining[l]/float64(n))
+			}
+		}
+		for f := range s.flows {
+			if !frozen[f] {
+				inc = min(inc, s.demand(f)-s.rates[f])
+			}
+		}
+		inc = max(inc, 0)
+
+		for f := range s.flows {
+			if !frozen[f] {
+				s.rates[f] += inc
+			}
+		}
+		for l, n := range active {
+			remaining[l] -= inc * float64(n)
+		}
+
+		freeze := func(f int, bottleneck int) {
+			frozen[f] = true
+			s.bottleneck[f] = bottleneck
+			unfrozen--
+			for _, l := range s.flowLinks[f] {
+				active[l]--
+			}
+		}
+		for f := range s.flows {
+			if !frozen[f] && s.rates[f] >= s.demand(f)*(1-saturation) {
+				freeze(f, -1)
+			}
+		}
+		for l := range s.links {
+			if active[l] > 0 && remaining[l] <= s.capacities[l]*saturation {
+				for _, f := range s.linkFlows[l] {
+					if !frozen[f] {
+						freeze(f, l)
+					}
+				}
+			}
+		}
+	}
+}
+
+// proportional allocates by dual decomposition: every link has a price rising with its overload,
+// every flow takes the rate maximizing log(rate) - rate * price of its route. The prices are updated
+// multiplicatively with the relative overload, so links of very different bandwidths converge alike.
+// Remaining overloads are removed by scaling the flows down at the end.
+func (s *solver) proportional() {
+	prices := make([]float64, len(s.links))
+	for l := range s.links {
+		prices[l] = float64(len(s.linkFlows[l])) / s.capacities[l]
+	}
+	// Upper bound of every flow: its demand and the smallest capacity on its route
+	limits := make([]float64, len(s.flows))
+	for f := range s.flows {
+		limits[f] = s.demand(f)
+		for _, l := range s.flowLinks[f] {
+			limits[f] = min(limits[f], s.capacities[l])
+		}
+		if len(s.flowLinks[f]) == 0 && math.IsInf(limits[f], 1) {
+			limits[f] = 0
+		}
+	}
+
+	loads := make([]float64, len(s.links))
+	update := func() {
+		clear(loads)
+		for f := range s.flows {
+			price := 0.0
+			for _, l := range s.flowLinks[f] {
+				price += prices[l]
+			}
+			rate := limits[f]
+			if price > 0 {
+				rate = min(rate, 1/price)
+			}
+			s.rates[f] = rate
+			for _, l := range s.flowLinks[f] {
+				loads[l] += rate
+			}
+		}
+	}
+
+	for k := range proportionalIterations {
+		update()
+		step := 1 / math.Sqrt(float64(k+1))
+		for l := range s.links {
+			prices[l] *= math.Exp(step * (loads[l]/s.capacities[l] - 1))
+		}
+	}
+	update()
+
+	// Make the allocation feasible and find the bottlenecks
+	for f := range s.flows {
+		scale := 1.0
+		for _, l := range s.flowLinks[f] {
+			if loads[l] > s.capacities[l] {
+				scale = min(scale, s.capacities[l]/loads[l])
+			}
+		}
+		s.rates[f] *= scale
+	}
+	clear(loads)
+	for f := range s.flows {
+		for _, l := range s.flowLinks[f] {
+			loads[l] += s.rates[f]
+		}
+	}
+	for f := range s.flows {
+		if s.rates[f] >= s.demand(f)*(1-1e-6) {
+			continue
+		}
+		best := -1.0
+		for _, l := range s.flowLinks[f] {
+			if u := loads[l] / s.capacities[l]; u > best {
+				best = u
+				s.bottleneck[f] = l
+			}
+		}
+	}
+}
+
+func (s *solver) allocation() *Allocation {
+	a := &Allocation{
+		Rates:       s.rates,
+		Bottlenecks: s.bottleneck,
+		Links:       make([]LinkLoad, len(s.links)),
+	}
+	for l, link := range s.links {
+		a.Links[l] = LinkLoad{DirectedLink: link, Capacity: s.capacities[l], Flows: len(s.linkFlows[l])}
+	}
+	for f, rate := range s.rates {
+		for _, l := range s.flowLinks[f] {
+			a.Links[l].Load += rate
+		}
+	}
+	return a
+}
@@ -0,0 +1,121 @@
+package capacity
+
+import (
+	"math"
+	"testing"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// testLink is a link with a fixed bandwidth between two nodes.
+type testLink struct {
+	a, b      types.Node
+	bandwidth float64
+}
+
+func (l *testLink) Distance() float64  { return 0 }
+func (l *testLink) Latency() float64   { return 1 }
+func (l *testLink) Bandwidth() float64 { return l.bandwidth }
+func (l *testLink) IsReachable() bool  { return true }
+func (l *testLink) Nodes() (types.Node, types.Node) {
+	return l.a, l.b
+}
+func (l *testLink) GetOther(self types.Node) types.Node {
+	if self == l.a {
+		return l.b
+	}
+	return l.a
+}
+
+func TestSolve(t *testing.T) {
+	// Two links in a row, the first shared with a flow over both links
+	wide := &testLink{bandwidth: 10}
+	narrow := &testLink{bandwidth: 4}
+	up := DirectedLink{Link: wide}
+	down := DirectedLink{Link: narrow}
+
+	tests := []struct {
+		name        string
+		fairness    string
+		flows       []Flow
+		rates       []float64
+		bottlenecks []int
+		tolerance   float64
+	}{
+		{
+			name:     "max-min",
+			fairness: MaxMinFairness,
+			flows: []Flow{
+				{ID: "wide", Links: []DirectedLink{up}},
+				{ID: "both", Links: []DirectedLink{up, down}},
+				{ID: "narrow", Links: []DirectedLink{down}},
+			},
+			rates:       []float64{8, 2, 2},
+			bottlenecks: []int{0, 1, 1},
+			tolerance:   1e-9,
+		},
+		{
+			name:     "max-min with demands",
+			fairness: MaxMinFairness,
+			flows: []Flow{
+				{ID: "small", Links: []DirectedLink{up}, Demand: 3},
+				{ID: "elastic", Links: []DirectedLink{up}},
+				{ID: "local", Demand: 5},
+			},
+			rates:       []float64{3, 7, 5},
+			bottlenecks: []int{-1, 0, -1},
+			tolerance:   1e-9,
+		},
+		{
+			// maximizes log(x1) + log(x2) + log(x3) with x1 + x2 = 10 and x2 + x3 = 4
+			name:     "proportional",
+			fairness: ProportionalFairness,
+			flows: []Flow{
+				{ID: "wide", Links: []DirectedLink{up}},
+				{ID: "both", Links: []DirectedLink{up, down}},
+				{ID: "narrow", Links: []DirectedLink{down}},
+			},
+			rates:       []float64{8.239, 1.761, 2.239},
+			bottlenecks: []int{0, 1, 1},
+			tolerance:   0.01,
+		},
+		{
+			name:     "proportional with demands",
+			fairness: ProportionalFairness,
+			flows: []Flow{
+				{ID: "small", Links: []DirectedLink{up}, Demand: 3},
+				{ID: "elastic", Links: []DirectedLink{up}},
+			},
+			rates:       []float64{3, 7},
+			bottlenecks: []int{-1, 0},
+			tolerance:   0.01,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := Solve(tt.flows, tt.fairness)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for f, want := range tt.rates {
+				if got := a.Rates[f]; math.Abs(got-want) > tt.tolerance {
+					t.Errorf("rate of %s = %.4f, want %.4f", tt.flows[f].ID, got, want)
+				}
+				if got := a.Bottlenecks[f]; got != tt.bottlenecks[f] {
+					t.Errorf("bottleneck of %s = %d, want %d", tt.flows[f].ID, got, tt.bottlenecks[f])
+				}
+			}
+			for _, l := range a.Links {
+				if l.Load > l.Capacity*(1+1e-9) {
+					t.Errorf("link loaded with %.4f beyond its capacity %.4f", l.Load, l.Capacity)
+				}
+			}
+		})
+	}
+}
+
+func TestSolveUnknownFairness(t *testing.T) {
+	if _, err := Solve(nil, "greedy"); err == nil {
+		t.Error("unknown fairness accepted")
+	}
+}
@@ -3,21 +3,28 @@ package computing
 import (
 	"fmt"
 	"sync"
-	"time"
 
 	"github.com/keniack/stardustGo/pkg/types"
 )
 
 // Computing represents the computing resources of a node.
 type Computing struct {
-	Cpu         float64                   // Total CPU available
-	Memory      float64                   // Total memory available
-	Type        types.ComputingType       // Type of the computing unit
-	CpuUsage    float64                   // Current CPU usage
-	MemoryUsage float64                   // Current memory usage
-	Services    []types.DeployableService // List of deployed services (using IDeployedService)
-	mu          sync.Mutex                // Mutex to ensure thread safety
-	node        types.Node                // Node to which this computing is mounted
+	Cpu              float64                   // Total CPU available
+	Memory           float64                   // Total memory available
+	Accelerators     int                       // Total GPUs or other accelerators available
+	Storage          float64                   // Total storage available in GB
+	Power            float64                   // Power draw in W
+	Type             types.ComputingType       // Type of the computing unit
+	Profile          string                    // Name of the hardware profile, empty without
+	CpuUsage         float64                   // Current CPU usage
+	MemoryUsage      float64                   // Current memory usage
+	AcceleratorUsage int                       // Current accelerator usage
+	StorageUsage     float64                   // Current storage usage
+	Services         []types.DeployableService // List of deployed services (using IDeployedService)
+	Scheduling       string                    // Scheduling of the tasks on the cores, "processor-sharing" or "fifo"
+	mu               sync.Mutex                // Mutex to ensure thread safety
+	node             types.Node                // Node to which this computing is mounted
+	executor         *Executor                 // Executes the tasks submitted to the node, created on first use
 }
 
 func (c *Computing) GetServices() []types.DeployableService {
@@ -47,47 +54,98 @@ func (c *Computing) Mount(node types.Node) error {
 }
 
 // TryPlaceDeploymentAsync tries to place a service on this computing unit
+// and advertises it to the network through the node's router if it is already deployed,
+// services with a lifecycle are advertised once they are running
 func (c *Computing) TryPlaceDeploymentAsync(service types.DeployableService) (bool, error) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 
 	if c.node == nil {
+		c.mu.Unlock()
 		return false, fmt.Errorf("computing must be mounted to node before it can be used")
 	}
 
 	if !c.CanPlace(service) {
+		c.mu.Unlock()
 		return false, nil
 	}
 
 	c.Services = append(c.Services, service)
 	c.CpuUsage += service.GetCpuUsage()
 	c.MemoryUsage += service.GetMemoryUsage()
-
-	// Simulate advertising the new service
-	go func() {
-		time.Sleep(1 * time.Second) // Simulate async operation
-		// For example, advertising the service to other nodes or components
-		// c.node.Router.AdvertiseNewServiceAsync(service.GetServiceName())
-	}()
-
+	accelerators, storage := hardwareRequirements(service)
+	c.AcceleratorUsage += accelerators
+	c.StorageUsage += storage
+	c.mu.Unlock()
+
+	// Advertise the new service, the lock is released as the advertisement reaches other nodes
+	if router := c.node.GetRouter(); router != nil && service.IsDeployed() {
+		if err := router.AdvertiseNewServiceAsync(service.GetServiceName()); err != nil {
+			return true, err
+		}
+	}
 	return true, nil
 }
 
 // RemoveDeploymentAsync removes a deployed service from the computing unit
+// and withdraws its advertisement
 func (c *Computing) RemoveDeploymentAsync(service types.DeployableService) error {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 
 	// Find and remove the service
+	removed := false
 	for i, s := range c.Services {
 		if s.GetServiceName() == service.GetServiceName() {
 			c.Services = append(c.Services[:i], c.Services[i+1:]...)
 			c.CpuUsage -= service.GetCpuUsage()
 			c.MemoryUsage -= service.GetMemoryUsage()
-			return nil
+			accelerators, storage := hardwareRequirements(service)
+			c.AcceleratorUsage -= accelerators
+			c.StorageUsage -= storage
+			removed = true
+			break
 		}
 	}
-	return fmt.Errorf("service %s not found", service.GetServiceName())
+	c.mu.Unlock()
+
+	if !removed {
+		return fmt.Errorf("service %s not found", service.GetServiceName())
+	}
+	if c.node != nil && c.node.GetRouter() != nil {
+		return c.node.GetRouter().WithdrawServiceAsync(service.GetServiceName())
+	}
+	return nil
+}
+
+// ReserveMemory reserves memory besides the deployed services, e.g. for function instances, if enough is available.
+func (c *Computing) ReserveMemory(memory float64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if memory > c.MemoryAvailable() {
+		return false
+	}
+	c.MemoryUsage += memory
+	return true
+}
+
+// ReleaseMemory releases memory reserved by ReserveMemory.
+func (c *Computing) ReleaseMemory(memory float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MemoryUsage -= memory
+}
+
+// Executor returns the executor of the tasks submitted to this computing unit, running on all its cores.
+func (c *Computing) Executor() (*Executor, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.executor == nil {
+		executor, err := NewExecutor(c.Cpu, c.Scheduling)
+		if err != nil {
+			return nil, err
+		}
+		c.executor = executor
+	}
+	return c.executor, nil
 }
 
 // CanPlace checks if the service can be placed on this computing unit
@@ -98,6 +156,10 @@ func (c *Computing) CanPlace(service types.DeployableService) bool {
 	if service.GetMemoryUsage() > c.MemoryAvailable() {
 		return false
 	}
+	accelerators, storage := hardwareRequirements(service)
+	if accelerators > c.AcceleratorsAvailable() || storage > c.StorageAvailable() {
+		return false
+	}
 	for _, s := range c.Services {
 		if s.GetServiceName() == service.GetServiceName() {
 			return false
@@ -129,6 +191,32 @@ func (c *Computing) MemoryAvailable() float64 {
 	return c.Memory - c.MemoryUsage
 }
 
+// AcceleratorsAvailable returns the remaining accelerators available
+func (c *Computing) AcceleratorsAvailable() int {
+	return c.Accelerators - c.AcceleratorUsage
+}
+
+// StorageAvailable returns the remaining storage available in GB
+func (c *Computing) StorageAvailable() float64 {
+	return c.Storage - c.StorageUsage
+}
+
+// hardwareRequirements returns the accelerators and storage required by the service, zero if it requires none.
+func hardwareRequirements(service types.DeployableService) (int, float64) {
+	if r, ok := service.(types.HardwareRequirements); ok {
+		return r.GetAcceleratorUsage(), r.GetStorageUsage()
+	}
+	return 0, 0
+}
+
+// ProfileOf returns the hardware profile of a computing unit built by a ComputingBuilder, or an empty string if unknown.
+func ProfileOf(computing types.Computing) string {
+	if c, ok := computing.(*Computing); ok {
+		return c.Profile
+	}
+	return ""
+}
+
 // Clone creates a new copy of the current computing unit and returns it as IComputing.
 func (c *Computing) Clone() types.Computing {
 	// Clone each deployed service
@@ -136,12 +224,19 @@ func (c *Computing) Clone() types.Computing {
 	copy(servicesClone, c.Services)
 
 	return &Computing{
-		Cpu:         c.Cpu,
-		Memory:      c.Memory,
-		Type:        c.Type,
-		CpuUsage:    c.CpuUsage,
-		MemoryUsage: c.MemoryUsage,
-		Services:    servicesClone,
+		Cpu:              c.Cpu,
+		Memory:           c.Memory,
+		Accelerators:     c.Accelerators,
+		Storage:          c.Storage,
+		Power:            c.Power,
+		Type:             c.Type,
+		Profile:          c.Profile,
+		CpuUsage:         c.CpuUsage,
+		MemoryUsage:      c.MemoryUsage,
+		AcceleratorUsage: c.AcceleratorUsage,
+		StorageUsage:     c.StorageUsage,
+		Services:         servicesClone,
+		Scheduling:       c.Scheduling,
 	}
 }
 
@@ -1,6 +1,13 @@
 package computing
 
 import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
 	"github.com/keniack/stardustGo/configs"
 	"github.com/keniack/stardustGo/pkg/types"
 )
@@ -12,39 +19,205 @@ type ComputingBuilder interface {
 	// WithComputingType sets the computing type for the builder.
 	WithComputingType(computingType types.ComputingType) ComputingBuilder
 
+	// WithNode selects the hardware profile assigned to the node, the current one is kept if none is assigned.
+	WithNode(node NodeDescriptor) ComputingBuilder
+
+	// WithProfile selects the hardware profile by name, the current one is kept if it is unknown.
+	WithProfile(name string) ComputingBuilder
+
 	// Build creates and returns the final Computing instance.
 	Build() *Computing // Return a pointer to Computing
 }
 
+// NodeDescriptor describes the node a computing unit is built for.
+type NodeDescriptor struct {
+	Name        string
+	Satellite   bool
+	Tags        []string // Tags of a ground station
+	Inclination float64  // Orbital inclination of a satellite in degrees
+	Altitude    float64  // Orbital altitude of a satellite in km, 0 if unknown
+}
+
 // DefaultComputingBuilder builds a Computing instance based on a given configuration.
 type DefaultComputingBuilder struct {
 	computingConfiguration []configs.ComputingConfig
 	currentConfiguration   configs.ComputingConfig
+	selectors              []profileSelector // profiles assigned by labels or shell, in configuration order
+	mapping                map[string]string // profile name by node name
+}
+
+// profileSelector is a compiled assignment of a hardware profile.
+type profileSelector struct {
+	config   configs.ComputingConfig
+	nodeType string
+	name     *regexp.Regexp
 }
 
 // NewComputingBuilder creates a new instance of ComputingBuilder with the given configuration.
+// It panics if a profile name is used twice or a profile selector is invalid.
 func NewComputingBuilder(computingConfiguration []configs.ComputingConfig) *DefaultComputingBuilder {
-	return &DefaultComputingBuilder{
+	b := &DefaultComputingBuilder{
 		computingConfiguration: computingConfiguration,
 		currentConfiguration:   computingConfiguration[0], // No computing selected initially
+		mapping:                make(map[string]string),
 	}
+
+	names := make(map[string]bool)
+	for _, config := range computingConfiguration {
+		if config.Name != "" {
+			if names[config.Name] {
+				panic(fmt.Sprintf("duplicate hardware profile %q", config.Name))
+			}
+			names[config.Name] = true
+		}
+		if !assigned(config) {
+			continue
+		}
+		sel := profileSelector{config: config, nodeType: strings.ToLower(config.NodeSelector.NodeType)}
+		if sel.nodeType != "" && sel.nodeType != "ground" && sel.nodeType != "satellite" {
+			panic(fmt.Sprintf("invalid node type %q of hardware profile %q", config.NodeSelector.NodeType, config.Name))
+		}
+		if config.NodeSelector.NodeName != "" {
+			name, err := regexp.Compile(config.NodeSelector.NodeName)
+			if err != nil {
+				panic(fmt.Sprintf("invalid node name of hardware profile %q: %v", config.Name, err))
+			}
+			sel.name = name
+		}
+		b.selectors = append(b.selectors, sel)
+	}
+	return b
 }
 
 // WithComputingType configures the Computing instance with a specific ComputingType.
+// Profiles assigned to nodes by labels or shell are only used if the type has no other profile.
 func (b *DefaultComputingBuilder) WithComputingType(computingType types.ComputingType) ComputingBuilder {
-	if b.currentConfiguration.Type == computingType {
-		return b
-	}
+	found := false
 	for _, config := range b.computingConfiguration {
-		if config.Type == computingType {
+		if config.Type != computingType {
+			continue
+		}
+		if !assigned(config) {
+			b.currentConfiguration = config
+			return b
+		}
+		if !found {
 			b.currentConfiguration = config
+			found = true
+		}
+	}
+	return b
+}
+
+// WithNode configures the Computing instance with the profile of the node's mapping entry, else with the first
+// profile whose node selector and shell match the node.
+func (b *DefaultComputingBuilder) WithNode(node NodeDescriptor) ComputingBuilder {
+	if profile, ok := b.mapping[node.Name]; ok {
+		return b.WithProfile(profile)
+	}
+	for _, sel := range b.selectors {
+		if sel.matches(node) {
+			b.currentConfiguration = sel.config
 			break
 		}
 	}
 	return b
 }
 
+// WithProfile configures the Computing instance with the named profile.
+func (b *DefaultComputingBuilder) WithProfile(name string) ComputingBuilder {
+	if config, ok := b.profile(name); ok {
+		b.currentConfiguration = config
+	}
+	return b
+}
+
+// LoadMapping reads the profiles of nodes from a CSV file with the columns node name and profile name.
+// A header row and lines starting with # are skipped. Mapped nodes ignore the selectors of the profiles.
+func (b *DefaultComputingBuilder) LoadMapping(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		node, profile := strings.TrimSpace(record[0]), strings.TrimSpace(record[1])
+		if _, ok := b.profile(profile); !ok {
+			if line == 1 {
+				continue // header
+			}
+			return fmt.Errorf("mapping line %d: unknown hardware profile %q", line, profile)
+		}
+		b.mapping[node] = profile
+	}
+}
+
 // Build returns the configured Computing instance.
 func (b *DefaultComputingBuilder) Build() *Computing {
-	return NewComputing(float64(b.currentConfiguration.Cores), float64(b.currentConfiguration.Memory), b.currentConfiguration.Type)
+	config := b.currentConfiguration
+	c := NewComputing(float64(config.Cores), float64(config.Memory), config.Type)
+	c.Accelerators = config.Accelerators
+	c.Storage = config.Storage
+	c.Power = config.Power
+	c.Profile = config.Name
+	c.Scheduling = config.Scheduling
+	return c
+}
+
+// profile returns the profile with the name.
+func (b *DefaultComputingBuilder) profile(name string) (configs.ComputingConfig, bool) {
+	for _, config := range b.computingConfiguration {
+		if name != "" && config.Name == name {
+			return config, true
+		}
+	}
+	return configs.ComputingConfig{}, false
+}
+
+// assigned reports if the profile is assigned to nodes by labels or shell.
+func assigned(config configs.ComputingConfig) bool {
+	return config.NodeSelector != (configs.NodeSelectorConfig{}) || config.Shell != nil
+}
+
+// matches reports if the node matches all criteria of the selector.
+func (s profileSelector) matches(node NodeDescriptor) bool {
+	if (s.nodeType == "ground" && node.Satellite) || (s.nodeType == "satellite" && !node.Satellite) {
+		return false
+	}
+	if tag := s.config.NodeSelector.Tag; tag != "" {
+		found := false
+		for _, t := range node.Tags {
+			found = found || strings.EqualFold(t, tag)
+		}
+		if !found {
+			return false
+		}
+	}
+	if s.name != nil && !s.name.MatchString(node.Name) {
+		return false
+	}
+	if shell := s.config.Shell; shell != nil {
+		if !node.Satellite || !within(node.Inclination, shell.MinInclination, shell.MaxInclination) ||
+			!within(node.Altitude, shell.MinAltitude, shell.MaxAltitude) {
+			return false
+		}
+	}
+	return true
+}
+
+// within reports if the value is within the bounds, zero bounds are open.
+func within(value, lower, upper float64) bool {
+	return (lower == 0 || value >= lower) && (upper == 0 || value <= upper)
 }
@@ -0,0 +1,94 @@
+package computing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+func testProfiles() []configs.ComputingConfig {
+	return []configs.ComputingConfig{
+		{Name: "edge", Cores: 4, Memory: 8, Type: types.Edge},
+		{Name: "cloud", Cores: 64, Memory: 256, Type: types.Cloud},
+		{Name: "gpu", Cores: 16, Memory: 64, Accelerators: 2, Type: types.Edge,
+			NodeSelector: configs.NodeSelectorConfig{NodeType: "ground", Tag: "gpu"}},
+		{Name: "polar", Cores: 8, Memory: 16, Type: types.Edge,
+			Shell: &configs.ShellConfig{MinInclination: 80, MaxAltitude: 600}},
+		{Name: "relay", Cores: 2, Memory: 4, Type: types.Edge,
+			NodeSelector: configs.NodeSelectorConfig{NodeName: "^relay-"}},
+	}
+}
+
+func TestComputingBuilderProfiles(t *testing.T) {
+	tests := []struct {
+		name string
+		node NodeDescriptor
+		want string
+	}{
+		{"unassigned", NodeDescriptor{Name: "gs-1", Tags: []string{"gateway"}}, "edge"},
+		{"tag", NodeDescriptor{Name: "gs-2", Tags: []string{"GPU"}}, "gpu"},
+		{"tag on a satellite", NodeDescriptor{Name: "sat-1", Satellite: true, Tags: []string{"gpu"}}, "edge"},
+		{"shell", NodeDescriptor{Name: "sat-2", Satellite: true, Inclination: 87, Altitude: 550}, "polar"},
+		{"above the shell", NodeDescriptor{Name: "sat-3", Satellite: true, Inclination: 87, Altitude: 1200}, "edge"},
+		{"name", NodeDescriptor{Name: "relay-7", Satellite: true}, "relay"},
+		{"mapping", NodeDescriptor{Name: "gs-mapped", Tags: []string{"gpu"}}, "cloud"},
+	}
+	mapping := filepath.Join(t.TempDir(), "mapping.csv")
+	if err := os.WriteFile(mapping, []byte("node,profile\n# mapped nodes\ngs-mapped, cloud\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewComputingBuilder(testProfiles())
+			if err := b.LoadMapping(mapping); err != nil {
+				t.Fatal(err)
+			}
+			c := b.WithComputingType(types.Edge).WithNode(tt.node).Build()
+			if c.Profile != tt.want {
+				t.Errorf("profile = %s, want %s", c.Profile, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputingBuilderBuild(t *testing.T) {
+	b := NewComputingBuilder(testProfiles())
+	c := b.WithProfile("gpu").Build()
+	if c.Cpu != 16 || c.Memory != 64 || c.Accelerators != 2 || ProfileOf(c) != "gpu" {
+		t.Errorf("built %+v from the gpu profile", c)
+	}
+	if c := b.WithProfile("unknown").Build(); c.Profile != "gpu" {
+		t.Errorf("unknown profile replaced the current one with %s", c.Profile)
+	}
+	if c := b.WithComputingType(types.Cloud).Build(); c.Profile != "cloud" {
+		t.Errorf("profile of the cloud type = %s, want cloud", c.Profile)
+	}
+}
+
+func TestComputingBuilderErrors(t *testing.T) {
+	mapping := filepath.Join(t.TempDir(), "mapping.csv")
+	if err := os.WriteFile(mapping, []byte("gs-1,edge\ngs-2,unknown\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewComputingBuilder(testProfiles()).LoadMapping(mapping); err == nil {
+		t.Error("mapping to an unknown profile accepted")
+	}
+
+	for name, profiles := range map[string][]configs.ComputingConfig{
+		"duplicate name":    {{Name: "edge"}, {Name: "edge"}},
+		"invalid node type": {{Name: "edge", NodeSelector: configs.NodeSelectorConfig{NodeType: "plane"}}},
+		"invalid node name": {{Name: "edge", NodeSelector: configs.NodeSelectorConfig{NodeName: "("}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("profiles accepted")
+				}
+			}()
+			NewComputingBuilder(profiles)
+		})
+	}
+}
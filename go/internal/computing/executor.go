@@ -0,0 +1,200 @@
+package computing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// workEpsilon is the remaining work in core seconds below which a task is complete
+const workEpsilon = 1e-6
+
+// Scheduling disciplines of the task executor
+const (
+	ProcessorSharing = "processor-sharing" // all tasks share the cores, a task uses at most one core
+	Fifo             = "fifo"              // tasks run to completion on one core each in arrival order
+)
+
+// Task is a request carrying CPU work.
+type Task struct {
+	ID   string
+	Work float64 // CPU time in core seconds
+}
+
+// TaskExecution is the execution of a task on a node.
+type TaskExecution struct {
+	Task       Task
+	Arrival    time.Time // time the task reached the node
+	Start      time.Time // time the task got its first CPU share, zero while queued
+	Completion time.Time // zero until completed
+	remaining  float64   // work left in core seconds
+}
+
+// Done reports if the task completed.
+func (e *TaskExecution) Done() bool {
+	return !e.Completion.IsZero()
+}
+
+// QueueingDelay returns the time from the arrival until the task got its first CPU share.
+func (e *TaskExecution) QueueingDelay() time.Duration {
+	return e.Start.Sub(e.Arrival)
+}
+
+// ExecutionTime returns the time from the arrival until the completion of the task.
+func (e *TaskExecution) ExecutionTime() time.Duration {
+	return e.Completion.Sub(e.Arrival)
+}
+
+// Executor executes tasks on the cores of a computing unit in simulation time. Tasks arrive at a given time,
+// are scheduled by the discipline and complete once their work is done. Advance moves the executor to the
+// simulation time and returns the tasks completed since the previous advance.
+type Executor struct {
+	cores      float64
+	scheduling string
+
+	mu        sync.Mutex
+	now       time.Time
+	arrivals  []*TaskExecution // submitted tasks which did not arrive yet, ordered by arrival
+	queued    []*TaskExecution // arrived tasks waiting for a core (fifo)
+	running   []*TaskExecution
+	completed []*TaskExecution // completed since the previous advance
+}
+
+// NewExecutor creates an executor on the cores with the scheduling discipline, processor sharing if empty.
+func NewExecutor(cores float64, scheduling string) (*Executor, error) {
+	scheduling = strings.ToLower(scheduling)
+	switch scheduling {
+	case "":
+		scheduling = ProcessorSharing
+	case ProcessorSharing, Fifo:
+	default:
+		return nil, fmt.Errorf("unknown scheduling: %s", scheduling)
+	}
+	return &Executor{cores: cores, scheduling: scheduling}, nil
+}
+
+// Scheduling returns the scheduling discipline.
+func (e *Executor) Scheduling() string {
+	return e.scheduling
+}
+
+// Load returns the number of arrived tasks which did not complete.
+func (e *Executor) Load() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.queued) + len(e.running)
+}
+
+// Submit adds a task arriving at the given time, an arrival before the time of the executor arrives at once.
+func (e *Executor) Submit(task Task, arrival time.Time) (*TaskExecution, error) {
+	if task.Work < 0 {
+		return nil, fmt.Errorf("task %s has negative work", task.ID)
+	}
+	if e.cores <= 0 {
+		return nil, fmt.Errorf("task %s submitted to a node without cores", task.ID)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if arrival.Before(e.now) {
+		arrival = e.now
+	}
+	exec := &TaskExecution{Task: task, Arrival: arrival, remaining: task.Work}
+	i := sort.Search(len(e.arrivals), func(i int) bool { return e.arrivals[i].Arrival.After(arrival) })
+	e.arrivals = append(e.arrivals[:i], append([]*TaskExecution{exec}, e.arrivals[i:]...)...)
+	return exec, nil
+}
+
+// Advance executes the tasks until now and returns the tasks completed since the previous advance.
+func (e *Executor) Advance(now time.Time) []*TaskExecution {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for {
+		e.admit()
+		rate := e.rate()
+
+		// Next event: the first completion or arrival, at the latest now
+		next := now
+		for _, t := range e.running {
+			if at := e.now.Add(time.Duration(math.Ceil(t.remaining / rate * float64(time.Second)))); at.Before(next) {
+				next = at
+			}
+		}
+		if len(e.arrivals) > 0 && e.arrivals[0].Arrival.Before(next) {
+			next = e.arrivals[0].Arrival
+		}
+		if next.Before(e.now) {
+			next = e.now
+		}
+
+		elapsed := next.Sub(e.now).Seconds()
+		completed := false
+		running := e.running[:0]
+		for _, t := range e.running {
+			t.remaining -= elapsed * rate
+			if t.remaining <= workEpsilon {
+				t.remaining, t.Completion = 0, next
+				e.completed = append(e.completed, t)
+				completed = true
+				continue
+			}
+			running = append(running, t)
+		}
+		e.running = running
+		e.now = next
+
+		// At now only tasks which completed free cores or zero work tasks need another round
+		if !next.Before(now) && !completed && !e.due() {
+			break
+		}
+	}
+
+	completed := e.completed
+	e.completed = nil
+	return completed
+}
+
+// due reports if an arrived task waits for admission or a running task has no work left.
+func (e *Executor) due() bool {
+	if len(e.arrivals) > 0 && !e.arrivals[0].Arrival.After(e.now) {
+		return true
+	}
+	for _, t := range e.running {
+		if t.remaining <= workEpsilon {
+			return true
+		}
+	}
+	return false
+}
+
+// admit moves the arrived tasks to the running or queued ones and starts queued tasks on free cores.
+func (e *Executor) admit() {
+	for len(e.arrivals) > 0 && !e.arrivals[0].Arrival.After(e.now) {
+		t := e.arrivals[0]
+		e.arrivals = e.arrivals[1:]
+		if e.scheduling == Fifo {
+			e.queued = append(e.queued, t)
+		} else {
+			t.Start = e.now
+			e.running = append(e.running, t)
+		}
+	}
+	for len(e.queued) > 0 && float64(len(e.running)) < max(math.Floor(e.cores), 1) {
+		t := e.queued[0]
+		e.queued = e.queued[1:]
+		t.Start = e.now
+		e.running = append(e.running, t)
+	}
+}
+
+// rate returns the cores each running task gets.
+func (e *Executor) rate() float64 {
+	if len(e.running) == 0 {
+		return 1
+	}
+	return min(1, e.cores/float64(len(e.running)))
+}
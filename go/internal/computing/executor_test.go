@@ -0,0 +1,106 @@
+package computing
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestExecutor(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(s float64) time.Time { return start.Add(time.Duration(s * float64(time.Second))) }
+
+	// Four tasks of one core second arriving every 100 ms on two cores
+	arrivals := []float64{0, 0.1, 0.2, 0.3}
+	tests := []struct {
+		name        string
+		cores       float64
+		scheduling  string
+		completions []float64 // seconds after start
+		queueing    []float64 // seconds
+	}{
+		{
+			name:        "processor sharing",
+			cores:       2,
+			scheduling:  ProcessorSharing,
+			completions: []float64{1.7667, 1.9167, 2.0167, 2.0833},
+			queueing:    []float64{0, 0, 0, 0},
+		},
+		{
+			name:        "fifo",
+			cores:       2,
+			scheduling:  Fifo,
+			completions: []float64{1, 1.1, 2, 2.1},
+			queueing:    []float64{0, 0, 0.8, 0.8},
+		},
+		{
+			name:        "fifo on one core",
+			cores:       1,
+			scheduling:  Fifo,
+			completions: []float64{1, 2, 3, 4},
+			queueing:    []float64{0, 0.9, 1.8, 2.7},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewExecutor(tt.cores, tt.scheduling)
+			if err != nil {
+				t.Fatal(err)
+			}
+			executions := make([]*TaskExecution, len(arrivals))
+			for i, a := range arrivals {
+				if executions[i], err = e.Submit(Task{ID: fmt.Sprint(i), Work: 1}, at(a)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if done := e.Advance(at(0.5)); len(done) != 0 {
+				t.Errorf("%d tasks completed after 0.5 s, want none", len(done))
+			}
+			if load := e.Load(); load != len(arrivals) {
+				t.Errorf("load after 0.5 s = %d, want %d", load, len(arrivals))
+			}
+			if done := e.Advance(at(10)); len(done) != len(arrivals) {
+				t.Fatalf("%d tasks completed after 10 s, want %d", len(done), len(arrivals))
+			}
+			for i, exec := range executions {
+				if got := exec.Completion.Sub(start).Seconds(); !near(got, tt.completions[i]) {
+					t.Errorf("task %d completed after %.4f s, want %.4f s", i, got, tt.completions[i])
+				}
+				if got := exec.QueueingDelay().Seconds(); !near(got, tt.queueing[i]) {
+					t.Errorf("task %d queued %.4f s, want %.4f s", i, got, tt.queueing[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExecutorErrors(t *testing.T) {
+	if _, err := NewExecutor(1, "round-robin"); err == nil {
+		t.Error("unknown scheduling accepted")
+	}
+	tests := []struct {
+		name  string
+		cores float64
+		work  float64
+	}{
+		{"negative work", 1, -1},
+		{"no cores", 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewExecutor(tt.cores, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := e.Submit(Task{ID: "task", Work: tt.work}, time.Time{}); err == nil {
+				t.Error("task accepted")
+			}
+		})
+	}
+}
+
+// near reports if the seconds agree to the precision of the expected values.
+func near(got, want float64) bool {
+	return got > want-1e-4 && got < want+1e-4
+}
@@ -0,0 +1,70 @@
+package computing
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/keniack/stardustGo/internal/routing"
+	"github.com/keniack/stardustGo/pkg/helper"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// Offloading is a task a client sent to a node for execution, the result returns over the route back.
+type Offloading struct {
+	Client    types.Node
+	Target    types.Node
+	Issued    time.Time
+	Uplink    float64 // route latency in ms from the client to the target
+	Downlink  float64 // route latency in ms from the target back to the client
+	Execution *TaskExecution
+}
+
+// Done reports if the task completed on the target.
+func (o *Offloading) Done() bool {
+	return o.Execution.Done()
+}
+
+// ResponseTime returns the time from issuing the task until the result reached the client.
+func (o *Offloading) ResponseTime() time.Duration {
+	return o.Execution.Completion.Add(helper.Milliseconds(o.Downlink)).Sub(o.Issued)
+}
+
+// Offload sends the task issued by the client to the target, where it arrives after the route latency of the
+// current topology. A client offloading to itself executes the task locally without network latency.
+func Offload(client, target types.Node, task Task, issued time.Time) (*Offloading, error) {
+	executor, err := ExecutorOf(target)
+	if err != nil {
+		return nil, err
+	}
+	uplink := routing.RouteLatency(client, target)
+	if math.IsInf(uplink, 1) {
+		return nil, fmt.Errorf("%s is unreachable from %s", target.GetName(), client.GetName())
+	}
+	downlink := routing.RouteLatency(target, client)
+	if math.IsInf(downlink, 1) {
+		return nil, fmt.Errorf("%s is unreachable from %s", client.GetName(), target.GetName())
+	}
+
+	execution, err := executor.Submit(task, issued.Add(helper.Milliseconds(uplink)))
+	if err != nil {
+		return nil, err
+	}
+	return &Offloading{
+		Client:    client,
+		Target:    target,
+		Issued:    issued,
+		Uplink:    uplink,
+		Downlink:  downlink,
+		Execution: execution,
+	}, nil
+}
+
+// ExecutorOf returns the task executor of the node's computing unit.
+func ExecutorOf(n types.Node) (*Executor, error) {
+	c, ok := n.GetComputing().(*Computing)
+	if !ok {
+		return nil, fmt.Errorf("computing of %s cannot execute tasks", n.GetName())
+	}
+	return c.Executor()
+}
@@ -0,0 +1,272 @@
+package deployment
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/internal/routing"
+	"github.com/keniack/stardustGo/pkg/helper"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// Scaling actions of the autoscaler
+const (
+	ScaleUp   = "scale-up"
+	ScaleDown = "scale-down"
+)
+
+// SloSample is the SLO evaluation of a service at one simulation step.
+type SloSample struct {
+	Time           time.Time
+	Replicas       int
+	Latency        float64 // percentile of the client latencies in ms, infinite if a client is unreachable
+	Utilization    float64 // highest utilization of a replica
+	LatencyMet     bool
+	UtilizationMet bool
+	Action         string // scaling action taken after the evaluation, empty for none
+}
+
+// Compliant reports if both SLOs were met.
+func (s SloSample) Compliant() bool {
+	return s.LatencyMet && s.UtilizationMet
+}
+
+// Autoscaler adds and removes replicas of the attached deployments to keep the latency of their clients and
+// the utilization of their replicas within the SLOs. Clients are served by the replica their router routes
+// the service to and load it with their request rate.
+type Autoscaler struct {
+	orchestrator *DeploymentOrchestrator
+	services     []*scaledService
+	mu           sync.Mutex
+}
+
+// scaledService is a deployment attached to the autoscaler.
+type scaledService struct {
+	spec      *PlacementSpecification
+	policy    configs.AutoscalingConfig
+	lastScale time.Time
+	timeline  []SloSample
+}
+
+// NewAutoscaler creates an autoscaler scaling the deployments of the orchestrator.
+func NewAutoscaler(orchestrator *DeploymentOrchestrator) *Autoscaler {
+	return &Autoscaler{orchestrator: orchestrator}
+}
+
+// Attach scales the deployment with the policy, the users of the specification are its clients.
+func (a *Autoscaler) Attach(spec *PlacementSpecification, policy configs.AutoscalingConfig) error {
+	if len(spec.Users) == 0 {
+		return fmt.Errorf("autoscaling of %s requires users", spec.Service().GetServiceName())
+	}
+	if policy.MaxReplicas > 0 && policy.MaxReplicas < max(policy.MinReplicas, 1) {
+		return fmt.Errorf("autoscaling of %s: maximum replicas below minimum", spec.Service().GetServiceName())
+	}
+	if policy.LatencyPercentile <= 0 || policy.LatencyPercentile > 100 {
+		policy.LatencyPercentile = 95
+	}
+	if policy.UtilizationSLO <= 0 {
+		policy.UtilizationSLO = 0.8
+	}
+	if policy.ScaleDownUtilization <= 0 {
+		policy.ScaleDownUtilization = policy.UtilizationSLO / 2
+	}
+	policy.MinReplicas = max(policy.MinReplicas, 1)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, s := range a.services {
+		if s.spec == spec {
+			return fmt.Errorf("deployment of %s is already autoscaled", spec.Service().GetServiceName())
+		}
+	}
+	a.services = append(a.services, &scaledService{spec: spec, policy: policy})
+	return nil
+}
+
+// Timeline returns the SLO evaluations of the deployment, one per simulation step.
+func (a *Autoscaler) Timeline(spec *PlacementSpecification) []SloSample {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, s := range a.services {
+		if s.spec == spec {
+			return append([]SloSample(nil), s.timeline...)
+		}
+	}
+	return nil
+}
+
+// Latest returns the last SLO evaluation of the deployment, false if it was not evaluated yet.
+func (a *Autoscaler) Latest(spec *PlacementSpecification) (SloSample, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, s := range a.services {
+		if s.spec == spec && len(s.timeline) > 0 {
+			return s.timeline[len(s.timeline)-1], true
+		}
+	}
+	return SloSample{}, false
+}
+
+// Compliance returns the fraction of the evaluations of the deployment which met both SLOs.
+func (a *Autoscaler) Compliance(spec *PlacementSpecification) float64 {
+	timeline := a.Timeline(spec)
+	if len(timeline) == 0 {
+		return 0
+	}
+	compliant := 0
+	for _, s := range timeline {
+		if s.Compliant() {
+			compliant++
+		}
+	}
+	return float64(compliant) / float64(len(timeline))
+}
+
+// Evaluate checks the SLOs of all attached deployments which still exist and scales them.
+func (a *Autoscaler) Evaluate(now time.Time) error {
+	active := a.orchestrator.Specifications()
+	a.mu.Lock()
+	services := append([]*scaledService(nil), a.services...)
+	a.mu.Unlock()
+
+	var errs []error
+	for _, s := range services {
+		if !slices.Contains(active, types.DeploymentSpecification(s.spec)) {
+			continue
+		}
+		sample, err := a.evaluate(s, now)
+		errs = append(errs, err)
+		a.mu.Lock()
+		s.timeline = append(s.timeline, sample)
+		a.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// evaluate measures the SLOs of the service and scales it if needed.
+func (a *Autoscaler) evaluate(s *scaledService, now time.Time) (SloSample, error) {
+	policy := s.policy
+	replicas := a.orchestrator.Replicas(s.spec)
+	latencies, load := a.observe(s, replicas)
+
+	sample := SloSample{
+		Time:     now,
+		Replicas: len(replicas),
+		Latency:  helper.Percentile(latencies, policy.LatencyPercentile),
+	}
+	for _, l := range load {
+		sample.Utilization = max(sample.Utilization, l)
+	}
+	sample.LatencyMet = policy.LatencySLO <= 0 || sample.Latency <= policy.LatencySLO
+	sample.UtilizationMet = policy.ReplicaCapacity <= 0 || sample.Utilization <= policy.UtilizationSLO
+
+	cooled := func(cooldown float64) bool {
+		return s.lastScale.IsZero() || !now.Before(s.lastScale.Add(helper.Seconds(cooldown)))
+	}
+	switch {
+	case !sample.Compliant() && (policy.MaxReplicas <= 0 || len(replicas) < policy.MaxReplicas) && cooled(policy.ScaleUpCooldown):
+		s.spec.Replicas = len(replicas) + 1
+		sample.Action, s.lastScale = ScaleUp, now
+		return sample, a.orchestrator.CheckRescheduleAsync(s.spec)
+
+	case sample.Compliant() && len(replicas) > policy.MinReplicas && cooled(policy.ScaleDownCooldown):
+		if victim, ok := a.scaleDownCandidate(s, replicas, load); ok {
+			sample.Action, s.lastScale = ScaleDown, now
+			return sample, a.orchestrator.RemoveReplica(s.spec, victim)
+		}
+	}
+	return sample, nil
+}
+
+// observe returns the latency of every client to the replica serving it and the utilization of every replica.
+func (a *Autoscaler) observe(s *scaledService, replicas []types.Node) ([]float64, map[types.Node]float64) {
+	service := s.spec.Service().GetServiceName()
+	load := make(map[types.Node]float64, len(replicas))
+	for _, r := range replicas {
+		load[r] = 0
+	}
+
+	latencies := make([]float64, 0, len(s.spec.Users))
+	for _, client := range s.spec.Users {
+		replica, latency, ok := servingReplica(client, service, replicas)
+		if !ok {
+			latencies = append(latencies, math.Inf(1))
+			continue
+		}
+		latencies = append(latencies, latency)
+		if s.policy.ReplicaCapacity > 0 {
+			load[replica] += s.policy.RequestRate / s.policy.ReplicaCapacity
+		}
+	}
+	return latencies, load
+}
+
+// scaleDownCandidate returns the least loaded replica whose removal keeps both SLOs with headroom:
+// the remaining replicas stay below the scale down utilization and the clients within the latency SLO.
+func (a *Autoscaler) scaleDownCandidate(s *scaledService, replicas []types.Node, load map[types.Node]float64) (types.Node, bool) {
+	policy := s.policy
+	total := 0.0
+	for _, l := range load {
+		total += l
+	}
+	if policy.ReplicaCapacity > 0 && total/float64(len(replicas)-1) > policy.ScaleDownUtilization {
+		return nil, false
+	}
+
+	candidates := append([]types.Node(nil), replicas...)
+	sort.SliceStable(candidates, func(i, j int) bool { return load[candidates[i]] < load[candidates[j]] })
+	for _, victim := range candidates {
+		remaining := slices.DeleteFunc(append([]types.Node(nil), replicas...), func(n types.Node) bool { return n == victim })
+		latencies := make([]float64, 0, len(s.spec.Users))
+		for _, client := range s.spec.Users {
+			best := math.Inf(1)
+			for _, r := range remaining {
+				best = min(best, routing.RouteLatency(client, r))
+			}
+			latencies = append(latencies, best)
+		}
+		if policy.LatencySLO <= 0 || helper.Percentile(latencies, policy.LatencyPercentile) <= policy.LatencySLO {
+			return victim, true
+		}
+	}
+	return nil, false
+}
+
+// servingReplica returns the replica the router of the client routes the service to and its latency.
+// Only replicas of the deployment count, a route to another replica of the same name is taken as the
+// route to the closest replica of the deployment.
+func servingReplica(client types.Node, service string, replicas []types.Node) (types.Node, float64, bool) {
+	if len(replicas) == 0 {
+		return nil, 0, false
+	}
+	result, err := client.GetRouter().RouteToService(service, types.RouteProbe{})
+	if err != nil || result == nil || !result.Reachable() {
+		return nil, 0, false
+	}
+
+	origin, _ := routing.Destination(result)
+	latency := routing.ResultLatency(result)
+	if latency == 0 && slices.Contains(replicas, client) {
+		origin = client
+	}
+	if slices.Contains(replicas, origin) {
+		return origin, latency, true
+	}
+
+	best, bestLatency := types.Node(nil), math.Inf(1)
+	for _, r := range replicas {
+		if l := routing.RouteLatency(client, r); l < bestLatency {
+			best, bestLatency = r, l
+		}
+	}
+	if best == nil {
+		return nil, 0, false
+	}
+	return best, bestLatency, true
+}
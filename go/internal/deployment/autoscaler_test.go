@@ -0,0 +1,109 @@
+package deployment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+func TestAutoscaler(t *testing.T) {
+	// Every replica serves up to 100 requests per second
+	policy := configs.AutoscalingConfig{LatencySLO: 1.5, LatencyPercentile: 100, RequestRate: 10, ReplicaCapacity: 100}
+	tests := []struct {
+		name     string
+		policy   func(configs.AutoscalingConfig) configs.AutoscalingConfig
+		replicas []int
+		actions  []string
+	}{
+		{
+			name:     "latency",
+			policy:   func(p configs.AutoscalingConfig) configs.AutoscalingConfig { return p },
+			replicas: []int{1, 2, 3, 2, 2},
+			actions:  []string{ScaleUp, ScaleUp, ScaleDown, "", ""},
+		},
+		{
+			name: "maximum replicas",
+			policy: func(p configs.AutoscalingConfig) configs.AutoscalingConfig {
+				p.MaxReplicas = 2
+				return p
+			},
+			replicas: []int{1, 2, 2, 2, 2},
+			actions:  []string{ScaleUp, "", "", "", ""},
+		},
+		{
+			name: "cooldown",
+			policy: func(p configs.AutoscalingConfig) configs.AutoscalingConfig {
+				p.ScaleUpCooldown = 2
+				return p
+			},
+			replicas: []int{1, 2, 2, 3, 2},
+			actions:  []string{ScaleUp, "", ScaleUp, ScaleDown, ""},
+		},
+		{
+			name: "utilization",
+			policy: func(p configs.AutoscalingConfig) configs.AutoscalingConfig {
+				p.LatencySLO = 0
+				p.RequestRate = 45
+				return p
+			},
+			replicas: []int{1, 2, 2, 2, 2},
+			actions:  []string{ScaleUp, "", "", "", ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The clients A and E cannot host
+			nodes, sim := line(t, 0, 4, 4, 4, 0)
+			d := NewDeploymentOrchestrator()
+			d.Mount(sim)
+			service, _ := NewDeployableService("web", 1, 1)
+			spec := NewPlacementSpecification(FirstFitDeployment, service, 1)
+			spec.Users = []types.Node{nodes[0], nodes[4]}
+			if err := d.CreateDeploymentAsync(spec); err != nil {
+				t.Fatal(err)
+			}
+			if err := d.Autoscaler().Attach(spec, tt.policy(policy)); err != nil {
+				t.Fatal(err)
+			}
+
+			for i := range tt.replicas {
+				sim.Time = start.Add(time.Duration(i) * time.Second)
+				if err := d.CheckReschedule(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			timeline := d.Autoscaler().Timeline(spec)
+			if len(timeline) != len(tt.replicas) {
+				t.Fatalf("%d evaluations, want %d", len(timeline), len(tt.replicas))
+			}
+			for i, sample := range timeline {
+				if sample.Replicas != tt.replicas[i] || sample.Action != tt.actions[i] {
+					t.Errorf("evaluation %d with %d replicas scaled %q, want %d replicas scaled %q",
+						i, sample.Replicas, sample.Action, tt.replicas[i], tt.actions[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAutoscalerAttach(t *testing.T) {
+	nodes, _ := line(t, 1)
+	service, _ := NewDeployableService("web", 1, 1)
+	spec := NewPlacementSpecification(FirstFitDeployment, service, 1)
+	a := NewAutoscaler(NewDeploymentOrchestrator())
+	if err := a.Attach(spec, configs.AutoscalingConfig{}); err == nil {
+		t.Error("deployment without users attached")
+	}
+	spec.Users = []types.Node{nodes[0]}
+	if err := a.Attach(spec, configs.AutoscalingConfig{MinReplicas: 3, MaxReplicas: 2}); err == nil {
+		t.Error("maximum below minimum accepted")
+	}
+	if err := a.Attach(spec, configs.AutoscalingConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Attach(spec, configs.AutoscalingConfig{}); err == nil {
+		t.Error("deployment attached twice")
+	}
+}
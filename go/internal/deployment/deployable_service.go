@@ -3,13 +3,50 @@ package deployment
 import (
 	"errors"
 	"fmt"
+	"sync"
+	"time"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var _ types.DeployableService = (*DeployableService)(nil)
+var _ types.HardwareRequirements = (*DeployableService)(nil)
+
+// ServiceState is a state in the lifecycle of a service replica.
+type ServiceState string
+
+// Lifecycle of a replica: pending until the image pull starts, pulling the image from the registry,
+// starting, running until it is removed, terminating and finally removed from its node.
+const (
+	StatePending     ServiceState = "pending"
+	StatePulling     ServiceState = "pulling"
+	StateStarting    ServiceState = "starting"
+	StateRunning     ServiceState = "running"
+	StateTerminating ServiceState = "terminating"
+	StateRemoved     ServiceState = "removed"
 )
 
 // DeployableService represents a deployable service with CPU and memory requirements.
+// The orchestrator deploys one replica of the service per node, each replica is a DeployableService
+// moving through the lifecycle states in simulation time.
 type DeployableService struct {
-	ServiceName string  // The name of the service
-	Cpu         float64 // CPU required by the service
-	Memory      float64 // Memory required by the service
+	ServiceName  string  // The name of the service
+	Cpu          float64 // CPU required by the service
+	Memory       float64 // Memory required by the service
+	Accelerators int     // Accelerators required by the service
+	Storage      float64 // Storage in GB required by the service
+	ImageSize    float64 // Size of the image pulled from the registry in MB, 0 if none
+	StartupTime  float64 // Seconds from the pulled image until the service is running
+	ShutdownTime float64 // Seconds from terminating until the service is removed
+
+	mu       sync.Mutex
+	template *DeployableService   // service the replica was created from, nil for the service itself
+	replicas []*DeployableService // replicas created from the service
+	node     types.Node           // node of the replica
+	state    ServiceState
+	since    time.Time // time of the last state transition
+	until    time.Time // end of the current timed state
+	removing bool      // termination requested, the lifecycle terminates the replica at its next advance
 }
 
 // NewDeployableService creates a new instance of DeployableService with the specified parameters.
@@ -32,3 +69,146 @@ func NewDeployableService(serviceName string, cpu, memory float64) (*DeployableS
 		Memory:      memory,
 	}, nil
 }
+
+// GetServiceName returns the name of the service
+func (s *DeployableService) GetServiceName() string {
+	return s.ServiceName
+}
+
+// GetCpuUsage returns the CPU required by the service
+func (s *DeployableService) GetCpuUsage() float64 {
+	return s.Cpu
+}
+
+// GetMemoryUsage returns the memory required by the service
+func (s *DeployableService) GetMemoryUsage() float64 {
+	return s.Memory
+}
+
+// GetAcceleratorUsage returns the accelerators required by the service
+func (s *DeployableService) GetAcceleratorUsage() int {
+	return s.Accelerators
+}
+
+// GetStorageUsage returns the storage in GB required by the service
+func (s *DeployableService) GetStorageUsage() float64 {
+	return s.Storage
+}
+
+// Node returns the node of the replica, nil for the service itself.
+func (s *DeployableService) Node() types.Node {
+	return s.node
+}
+
+// Replicas returns the replicas created from the service.
+func (s *DeployableService) Replicas() []*DeployableService {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*DeployableService(nil), s.replicas...)
+}
+
+// State returns the lifecycle state of the replica. For the service itself it is the most advanced state of
+// its active replicas, removed once all replicas are removed and pending without replicas.
+func (s *DeployableService) State() ServiceState {
+	if s.template != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.state
+	}
+
+	replicas := s.Replicas()
+	if len(replicas) == 0 {
+		return StatePending
+	}
+	state := StateRemoved
+	for _, r := range replicas {
+		if stateRank[r.State()] > stateRank[state] {
+			state = r.State()
+		}
+	}
+	return state
+}
+
+// stateRank orders the states aggregated for the service, active states outrank ending ones.
+var stateRank = map[ServiceState]int{
+	StateRemoved:     0,
+	StateTerminating: 1,
+	StatePending:     2,
+	StatePulling:     3,
+	StateStarting:    4,
+	StateRunning:     5,
+}
+
+// Since returns the time of the last state transition of the replica.
+func (s *DeployableService) Since() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.since
+}
+
+// IsDeployed checks if the replica is running, for the service itself if any replica is running
+func (s *DeployableService) IsDeployed() bool {
+	return s.State() == StateRunning
+}
+
+// Deploy starts the lifecycle of a new replica in the pending state
+func (s *DeployableService) Deploy() error {
+	if s.template == nil {
+		return errors.New("only replicas of a service can be deployed, deploy the service through the orchestrator")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != "" {
+		return fmt.Errorf("replica of %s on %s is already %s", s.ServiceName, s.node.GetName(), s.state)
+	}
+	s.state = StatePending
+	return nil
+}
+
+// Remove requests the termination of the replica, for the service itself of all its replicas
+func (s *DeployableService) Remove() error {
+	if s.template == nil {
+		var errs []error
+		for _, r := range s.Replicas() {
+			if r.active() {
+				errs = append(errs, r.Remove())
+			}
+		}
+		return errors.Join(errs...)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.removing || s.state == StateTerminating || s.state == StateRemoved {
+		return fmt.Errorf("replica of %s on %s is already terminating", s.ServiceName, s.node.GetName())
+	}
+	s.removing = true
+	return nil
+}
+
+// active reports if the replica is neither terminating nor removed.
+func (s *DeployableService) active() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.removing && s.state != StateTerminating && s.state != StateRemoved
+}
+
+// newReplica creates a replica of the service for the node.
+func (s *DeployableService) newReplica(node types.Node) *DeployableService {
+	replica := &DeployableService{
+		ServiceName:  s.ServiceName,
+		Cpu:          s.Cpu,
+		Memory:       s.Memory,
+		Accelerators: s.Accelerators,
+		Storage:      s.Storage,
+		ImageSize:    s.ImageSize,
+		StartupTime:  s.StartupTime,
+		ShutdownTime: s.ShutdownTime,
+		template:     s,
+		node:         node,
+	}
+	s.mu.Lock()
+	s.replicas = append(s.replicas, replica)
+	s.mu.Unlock()
+	return replica
+}
@@ -0,0 +1,137 @@
+package deployment
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/internal/geo"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// NewSpecificationFromConfig creates the placement specification of a declared service.
+// Users located by coordinates are served by the ground station closest to them at the current simulation time.
+func NewSpecificationFromConfig(cfg configs.ServiceDeploymentConfig, simulation types.SimulationController, simStart time.Time) (*PlacementSpecification, error) {
+	service, err := NewDeployableService(cfg.Name, cfg.Cpu, cfg.Memory)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Accelerators < 0 || cfg.Storage < 0 {
+		return nil, fmt.Errorf("accelerators and storage of service %s must not be negative", cfg.Name)
+	}
+	service.Accelerators = cfg.Accelerators
+	service.Storage = cfg.Storage
+	service.ImageSize = cfg.ImageSize
+	service.StartupTime = cfg.StartupTime
+	service.ShutdownTime = cfg.ShutdownTime
+	selector, err := NewNodeSelector(cfg.NodeSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid node selector of service %s: %w", cfg.Name, err)
+	}
+	users, err := resolveUsers(cfg.Users, simulation, simStart)
+	if err != nil {
+		return nil, fmt.Errorf("invalid users of service %s: %w", cfg.Name, err)
+	}
+
+	spec := NewPlacementSpecification(DeploymentTypeOf(cfg), service, cfg.Replicas)
+	spec.ComputingType = cfg.ComputingType
+	spec.NodeSelector = selector
+	spec.AntiAffinity = cfg.AntiAffinity
+	spec.Users = users
+	spec.Migration = cfg.Migration
+	return spec, nil
+}
+
+// DeploymentTypeOf returns the deployment type of a declared service, min-latency if it has users, else first-fit.
+func DeploymentTypeOf(cfg configs.ServiceDeploymentConfig) string {
+	switch {
+	case cfg.DeploymentType != "":
+		return cfg.DeploymentType
+	case len(cfg.Users) > 0:
+		return MinLatencyDeployment
+	default:
+		return FirstFitDeployment
+	}
+}
+
+// NewNodeSelector returns a function matching the nodes selected by the config, nil if it selects all nodes.
+func NewNodeSelector(cfg configs.NodeSelectorConfig) (func(types.Node) bool, error) {
+	if cfg == (configs.NodeSelectorConfig{}) {
+		return nil, nil
+	}
+	nodeType := strings.ToLower(cfg.NodeType)
+	if nodeType != "" && nodeType != "ground" && nodeType != "satellite" {
+		return nil, fmt.Errorf("unknown node type: %s", cfg.NodeType)
+	}
+	var name *regexp.Regexp
+	if cfg.NodeName != "" {
+		var err error
+		if name, err = regexp.Compile(cfg.NodeName); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(n types.Node) bool {
+		gs, isGround := n.(types.GroundStation)
+		if (nodeType == "ground" && !isGround) || (nodeType == "satellite" && isGround) {
+			return false
+		}
+		if cfg.Tag != "" && (!isGround || !hasTag(gs, cfg.Tag)) {
+			return false
+		}
+		return name == nil || name.MatchString(n.GetName())
+	}, nil
+}
+
+// resolveUsers returns the ground stations of the user locations.
+func resolveUsers(locations []configs.UserLocationConfig, simulation types.SimulationController, simStart time.Time) ([]types.Node, error) {
+	stations := simulation.GetGroundStations()
+	elapsed := simulation.GetSimulationTime().Sub(simStart)
+
+	var users []types.Node
+	for _, location := range locations {
+		if location.GroundStation != "" {
+			station, ok := findGroundStation(stations, location.GroundStation)
+			if !ok {
+				return nil, fmt.Errorf("ground station %s not found", location.GroundStation)
+			}
+			users = append(users, station)
+			continue
+		}
+
+		target := geo.Coordinate{Latitude: location.Latitude, Longitude: location.Longitude}
+		var closest types.GroundStation
+		closestDistance := math.Inf(1)
+		for _, gs := range stations {
+			if d := geo.Distance(target, geo.SubPoint(gs.GetPosition(), elapsed)); d < closestDistance {
+				closest, closestDistance = gs, d
+			}
+		}
+		if closest == nil {
+			return nil, fmt.Errorf("no ground station for location %.4f, %.4f", location.Latitude, location.Longitude)
+		}
+		users = append(users, closest)
+	}
+	return users, nil
+}
+
+func findGroundStation(stations []types.GroundStation, name string) (types.GroundStation, bool) {
+	for _, gs := range stations {
+		if gs.GetName() == name {
+			return gs, true
+		}
+	}
+	return nil, false
+}
+
+func hasTag(gs types.GroundStation, tag string) bool {
+	for _, t := range gs.GetTags() {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package deployment
+
+import (
+	"testing"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/internal/simtest"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+func TestNodeSelector(t *testing.T) {
+	gateway := simtest.NewGroundStation("gs-vienna", "Gateway")
+	station := simtest.NewGroundStation("gs-graz")
+	satellite := simtest.NewNode("sat-1")
+	tests := []struct {
+		name string
+		cfg  configs.NodeSelectorConfig
+		want []bool // gateway, station, satellite
+	}{
+		{"all", configs.NodeSelectorConfig{}, []bool{true, true, true}},
+		{"ground", configs.NodeSelectorConfig{NodeType: "Ground"}, []bool{true, true, false}},
+		{"satellite", configs.NodeSelectorConfig{NodeType: "satellite"}, []bool{false, false, true}},
+		{"tag", configs.NodeSelectorConfig{Tag: "gateway"}, []bool{true, false, false}},
+		{"name", configs.NodeSelectorConfig{NodeName: "^gs-g"}, []bool{false, true, false}},
+		{"ground and name", configs.NodeSelectorConfig{NodeType: "ground", NodeName: "1$"}, []bool{false, false, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selector, err := NewNodeSelector(tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, n := range []types.Node{gateway, station, satellite} {
+				if got := selector == nil || selector(n); got != tt.want[i] {
+					t.Errorf("%s selected = %v, want %v", n.GetName(), got, tt.want[i])
+				}
+			}
+		})
+	}
+
+	for _, cfg := range []configs.NodeSelectorConfig{{NodeType: "plane"}, {NodeName: "gs-("}} {
+		if _, err := NewNodeSelector(cfg); err == nil {
+			t.Errorf("selector %+v accepted", cfg)
+		}
+	}
+}
+
+func TestSpecificationFromConfig(t *testing.T) {
+	// Ground stations on the equator at longitude 0 and 90 at the simulation start
+	prime := simtest.NewGroundStation("prime")
+	prime.Position = types.Vector{X: 6378137}
+	east := simtest.NewGroundStation("east")
+	east.Position = types.Vector{Y: 6378137}
+	sim := simtest.NewSimulation(start, prime, east)
+
+	cfg := configs.ServiceDeploymentConfig{
+		Name:     "web",
+		Cpu:      1,
+		Memory:   256,
+		Replicas: 2,
+		Users: []configs.UserLocationConfig{
+			{GroundStation: "east"},
+			{Latitude: 10, Longitude: 5},
+		},
+	}
+	spec, err := NewSpecificationFromConfig(cfg, sim, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.Type() != MinLatencyDeployment || spec.replicas() != 2 || spec.Service().GetServiceName() != "web" {
+		t.Errorf("specification of %s with %d replicas placed %s, want web with 2 placed %s",
+			spec.Service().GetServiceName(), spec.replicas(), spec.Type(), MinLatencyDeployment)
+	}
+	if got := names(spec.Users); got != "eastprime" {
+		t.Errorf("users at %s, want eastprime", got)
+	}
+
+	// The stations are fixed in the inertial simulation frame, after six hours east is above longitude 0
+	sim.StepBySeconds(6 * 3600)
+	if spec, err = NewSpecificationFromConfig(cfg, sim, start); err != nil {
+		t.Fatal(err)
+	}
+	if got := names(spec.Users); got != "easteast" {
+		t.Errorf("users after six hours at %s, want easteast", got)
+	}
+
+	for _, invalid := range []configs.ServiceDeploymentConfig{
+		{Name: "web", Cpu: 1},
+		{Name: "web", Cpu: 1, Memory: 1, Storage: -1},
+		{Name: "web", Cpu: 1, Memory: 1, NodeSelector: configs.NodeSelectorConfig{NodeType: "plane"}},
+		{Name: "web", Cpu: 1, Memory: 1, Users: []configs.UserLocationConfig{{GroundStation: "north"}}},
+	} {
+		if _, err := NewSpecificationFromConfig(invalid, sim, start); err == nil {
+			t.Errorf("config %+v accepted", invalid)
+		}
+	}
+}
+
+func TestDeploymentTypeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  configs.ServiceDeploymentConfig
+		want string
+	}{
+		{"default", configs.ServiceDeploymentConfig{}, FirstFitDeployment},
+		{"users", configs.ServiceDeploymentConfig{Users: []configs.UserLocationConfig{{GroundStation: "gs"}}}, MinLatencyDeployment},
+		{"explicit", configs.ServiceDeploymentConfig{DeploymentType: LoadBalancingDeployment}, LoadBalancingDeployment},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DeploymentTypeOf(tt.cfg); got != tt.want {
+				t.Errorf("DeploymentTypeOf = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,73 +2,157 @@ package deployment
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/keniack/stardustGo/pkg/types"
 )
 
+var _ types.DeploymentOrchestrator = (*DeploymentOrchestrator)(nil)
+
+// SimulationAware is implemented by orchestrators which need the simulation, e.g. to find nodes for placement.
+type SimulationAware interface {
+	// Mount passes the simulation to the orchestrator
+	Mount(simulation types.SimulationController)
+}
+
 // DeploymentOrchestrator orchestrates deployment actions based on specifications.
+// It delegates every specification to the orchestrator registered for its type.
 type DeploymentOrchestrator struct {
 	resolver       *DeploymentOrchestratorResolver
+	lifecycle      *Lifecycle
+	autoscaler     *Autoscaler
 	specifications []types.DeploymentSpecification
+	simulation     types.SimulationController
 	mu             sync.Mutex // Protects access to specifications
 }
 
-// NewDeploymentOrchestrator creates a new DeploymentOrchestrator.
+// NewDeploymentOrchestrator creates a new DeploymentOrchestrator with the built-in placement orchestrators,
+// which share the lifecycle of their replicas.
 func NewDeploymentOrchestrator() *DeploymentOrchestrator {
-	// Initialize the resolver but do not rely on simulation controller at this stage
-	resolver, _ := NewDeploymentOrchestratorResolver([]types.DeploymentOrchestrator{})
+	lifecycle := NewLifecycle()
+	resolver, _ := NewDeploymentOrchestratorResolver([]types.DeploymentOrchestrator{
+		NewPlacementOrchestrator(FirstFitDeployment, FirstFitStrategy{}, lifecycle),
+		NewPlacementOrchestrator(MinLatencyDeployment, MinLatencyStrategy{}, lifecycle),
+		NewPlacementOrchestrator(LoadBalancingDeployment, LoadBalancingStrategy{}, lifecycle),
+	})
 
-	// Just create the DeploymentOrchestrator without trying to inject satellites
-	return &DeploymentOrchestrator{
-		resolver: resolver,
+	d := &DeploymentOrchestrator{
+		resolver:  resolver,
+		lifecycle: lifecycle,
 	}
+	d.autoscaler = NewAutoscaler(d)
+	return d
 }
 
-// DeploymentTypes returns the supported deployment types.
-func (d *DeploymentOrchestrator) DeploymentTypes() ([]string, error) {
-	// This method needs to be implemented
-	return nil, errors.New("method not implemented")
+// Lifecycle returns the lifecycle of the replicas placed by the built-in orchestrators.
+func (d *DeploymentOrchestrator) Lifecycle() *Lifecycle {
+	return d.lifecycle
 }
 
-// CheckRescheduleAsync checks for any rescheduling needs for each specification.
-func (d *DeploymentOrchestrator) CheckRescheduleAsync(deployment types.DeploymentSpecification) error {
-	// Iterate over all specifications
+// Autoscaler returns the autoscaler which scales the attached deployments after every reschedule check.
+func (d *DeploymentOrchestrator) Autoscaler() *Autoscaler {
+	return d.autoscaler
+}
+
+// Register adds an orchestrator for further deployment types.
+func (d *DeploymentOrchestrator) Register(orchestrator types.DeploymentOrchestrator) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	var wg sync.WaitGroup
-	for _, spec := range d.specifications {
-		wg.Add(1)
-		go func(spec types.DeploymentSpecification) {
-			defer wg.Done()
-			orchestrator, _ := d.resolver.Resolve(spec)
-			_ = orchestrator.CheckRescheduleAsync(spec)
-		}(spec)
-	}
-	wg.Wait()
+	if err := d.resolver.Register(orchestrator); err != nil {
+		return err
+	}
+	if aware, ok := orchestrator.(SimulationAware); ok && d.simulation != nil {
+		aware.Mount(d.simulation)
+	}
 	return nil
 }
 
-// CreateDeploymentAsync adds a new deployment specification and creates the deployment.
-func (d *DeploymentOrchestrator) CreateDeploymentAsync(deployment types.DeploymentSpecification) error {
+// Mount passes the simulation to all registered orchestrators which need it.
+func (d *DeploymentOrchestrator) Mount(simulation types.SimulationController) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.simulation = simulation
+	for _, t := range d.resolver.Types() {
+		if aware, ok := d.resolver.orchestrators[t].(SimulationAware); ok {
+			aware.Mount(simulation)
+		}
+	}
+}
+
+// DeploymentTypes returns the supported deployment types.
+func (d *DeploymentOrchestrator) DeploymentTypes() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.resolver.Types()
+}
+
+// Specifications returns the active deployment specifications.
+func (d *DeploymentOrchestrator) Specifications() []types.DeploymentSpecification {
 	d.mu.Lock()
 	defer d.mu.Unlock()
+	return append([]types.DeploymentSpecification(nil), d.specifications...)
+}
+
+// CheckReschedule advances the lifecycle of the replicas to the simulation time, checks all
+// deployment specifications for rescheduling and scales the autoscaled ones, it is called after every simulation step.
+func (d *DeploymentOrchestrator) CheckReschedule() error {
+	var errs []error
+	d.mu.Lock()
+	simulation := d.simulation
+	d.mu.Unlock()
+	if simulation != nil {
+		if err := d.lifecycle.Advance(simulation.GetSimulationTime()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, spec := range d.Specifications() {
+		if err := d.CheckRescheduleAsync(spec); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if simulation != nil {
+		if err := d.autoscaler.Evaluate(simulation.GetSimulationTime()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CheckRescheduleAsync checks if the deployment needs to be rescheduled.
+func (d *DeploymentOrchestrator) CheckRescheduleAsync(deployment types.DeploymentSpecification) error {
+	orchestrator, err := d.resolve(deployment)
+	if err != nil {
+		return err
+	}
+	return orchestrator.CheckRescheduleAsync(deployment)
+}
+
+// CreateDeploymentAsync adds a new deployment specification and creates the deployment.
+func (d *DeploymentOrchestrator) CreateDeploymentAsync(deployment types.DeploymentSpecification) error {
+	orchestrator, err := d.resolve(deployment)
+	if err != nil {
+		return err
+	}
 
-	// Add deployment to specifications
+	d.mu.Lock()
 	d.specifications = append(d.specifications, deployment)
+	d.mu.Unlock()
 
-	// Resolve the orchestrator and create the deployment
-	orchestrator, _ := d.resolver.Resolve(deployment)
 	return orchestrator.CreateDeploymentAsync(deployment)
 }
 
 // DeleteDeploymentAsync removes a deployment specification and deletes it.
 func (d *DeploymentOrchestrator) DeleteDeploymentAsync(deployment types.DeploymentSpecification) error {
-	d.mu.Lock()
-	defer d.mu.Unlock()
+	orchestrator, err := d.resolve(deployment)
+	if err != nil {
+		return err
+	}
 
 	// Remove the deployment specification
+	d.mu.Lock()
 	var newSpecifications []types.DeploymentSpecification
 	for _, spec := range d.specifications {
 		if spec != deployment {
@@ -76,8 +160,51 @@ func (d *DeploymentOrchestrator) DeleteDeploymentAsync(deployment types.Deployme
 		}
 	}
 	d.specifications = newSpecifications
+	d.mu.Unlock()
 
-	// Resolve the orchestrator and delete the deployment
-	orchestrator, _ := d.resolver.Resolve(deployment)
 	return orchestrator.DeleteDeploymentAsync(deployment)
 }
+
+// Replicas returns the nodes hosting the replicas of the deployment, nil if its orchestrator does not report them.
+func (d *DeploymentOrchestrator) Replicas(deployment types.DeploymentSpecification) []types.Node {
+	orchestrator, err := d.resolve(deployment)
+	if err != nil {
+		return nil
+	}
+	if scaler, ok := orchestrator.(ReplicaScaler); ok {
+		return scaler.Replicas(deployment)
+	}
+	return nil
+}
+
+// RemoveReplica removes the replica of the deployment on the node.
+func (d *DeploymentOrchestrator) RemoveReplica(deployment types.DeploymentSpecification, node types.Node) error {
+	orchestrator, err := d.resolve(deployment)
+	if err != nil {
+		return err
+	}
+	scaler, ok := orchestrator.(ReplicaScaler)
+	if !ok {
+		return fmt.Errorf("deployment type %s cannot remove single replicas", deployment.Type())
+	}
+	return scaler.RemoveReplica(deployment, node)
+}
+
+// MigrationStats returns the migration statistics of the deployment, false if its orchestrator does not migrate it.
+func (d *DeploymentOrchestrator) MigrationStats(deployment types.DeploymentSpecification) (MigrationStats, bool) {
+	orchestrator, err := d.resolve(deployment)
+	if err != nil {
+		return MigrationStats{}, false
+	}
+	reporter, ok := orchestrator.(MigrationReporter)
+	if !ok {
+		return MigrationStats{}, false
+	}
+	return reporter.MigrationStats(deployment)
+}
+
+func (d *DeploymentOrchestrator) resolve(deployment types.DeploymentSpecification) (types.DeploymentOrchestrator, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.resolver.Resolve(deployment)
+}
@@ -3,6 +3,7 @@ package deployment
 import (
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/keniack/stardustGo/pkg/types"
 )
@@ -14,21 +15,40 @@ type DeploymentOrchestratorResolver struct {
 
 // NewDeploymentOrchestratorResolver creates a new DeploymentOrchestratorResolver.
 func NewDeploymentOrchestratorResolver(orchestrators []types.DeploymentOrchestrator) (*DeploymentOrchestratorResolver, error) {
-	orchestratorMap := make(map[string]types.DeploymentOrchestrator)
+	resolver := &DeploymentOrchestratorResolver{
+		orchestrators: make(map[string]types.DeploymentOrchestrator),
+	}
 
 	// Add orchestrators to the map
 	for _, orchestrator := range orchestrators {
-		for _, orchestratorType := range orchestrator.DeploymentTypes() {
-			if _, exists := orchestratorMap[orchestratorType]; exists {
-				return nil, fmt.Errorf("type %s is duplicated", orchestratorType)
-			}
-			orchestratorMap[orchestratorType] = orchestrator
+		if err := resolver.Register(orchestrator); err != nil {
+			return nil, err
+		}
+	}
+	return resolver, nil
+}
+
+// Register adds an orchestrator for all of its deployment types.
+func (r *DeploymentOrchestratorResolver) Register(orchestrator types.DeploymentOrchestrator) error {
+	for _, orchestratorType := range orchestrator.DeploymentTypes() {
+		if _, exists := r.orchestrators[orchestratorType]; exists {
+			return fmt.Errorf("type %s is duplicated", orchestratorType)
 		}
 	}
+	for _, orchestratorType := range orchestrator.DeploymentTypes() {
+		r.orchestrators[orchestratorType] = orchestrator
+	}
+	return nil
+}
 
-	return &DeploymentOrchestratorResolver{
-		orchestrators: orchestratorMap,
-	}, nil
+// Types returns the sorted deployment types of all registered orchestrators.
+func (r *DeploymentOrchestratorResolver) Types() []string {
+	deploymentTypes := make([]string, 0, len(r.orchestrators))
+	for t := range r.orchestrators {
+		deploymentTypes = append(deploymentTypes, t)
+	}
+	sort.Strings(deploymentTypes)
+	return deploymentTypes
 }
 
 // Resolve finds the correct IDeploymentOrchestrator based on the specification type.
@@ -0,0 +1,253 @@
+package deployment
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/keniack/stardustGo/internal/capacity"
+	"github.com/keniack/stardustGo/pkg/helper"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// Transition is a state change of a replica.
+type Transition struct {
+	Replica *DeployableService
+	Node    types.Node
+	From    ServiceState // empty for a new replica
+	To      ServiceState
+	Time    time.Time
+}
+
+// LifecycleObserver is notified about every state transition of a replica.
+// Simulation plugins implementing it are registered by Lifecycle.Observe.
+type LifecycleObserver interface {
+	OnTransition(transition Transition)
+}
+
+// Lifecycle moves the replicas of services through their states in simulation time.
+// A replica occupies the resources of its node from pending on, pulls its image from the registry
+// over the route of the registry's router, starts, and is advertised to the network while running.
+// A terminating replica is withdrawn at once and frees its resources when it is removed.
+type Lifecycle struct {
+	registry  types.Node
+	now       time.Time
+	replicas  []*DeployableService
+	observers []LifecycleObserver
+	mu        sync.Mutex
+}
+
+// NewLifecycle creates a lifecycle without registry, replicas then start without pulling their image.
+func NewLifecycle() *Lifecycle {
+	return &Lifecycle{}
+}
+
+// SetRegistry sets the node the images are pulled from.
+func (l *Lifecycle) SetRegistry(registry types.Node) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.registry = registry
+}
+
+// Observe registers an observer of the state transitions.
+func (l *Lifecycle) Observe(observer LifecycleObserver) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.observers = append(l.observers, observer)
+}
+
+// Replicas returns the replicas which are not removed.
+func (l *Lifecycle) Replicas() []*DeployableService {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]*DeployableService(nil), l.replicas...)
+}
+
+// Deploy places a pending replica of the service on the node, false if the node cannot place it.
+func (l *Lifecycle) Deploy(service *DeployableService, node types.Node) (*DeployableService, bool, error) {
+	if service.template != nil {
+		return nil, false, fmt.Errorf("%s is a replica, deploy the service it was created from", service.ServiceName)
+	}
+	if !node.GetComputing().CanPlace(service) {
+		return nil, false, nil
+	}
+
+	replica := service.newReplica(node)
+	if err := replica.Deploy(); err != nil {
+		return nil, false, err
+	}
+	placed, err := node.GetComputing().TryPlaceDeploymentAsync(replica)
+	if err != nil || !placed {
+		replica.mu.Lock()
+		replica.state = StateRemoved
+		replica.mu.Unlock()
+		return nil, false, err
+	}
+
+	l.mu.Lock()
+	replica.since = l.now
+	l.replicas = append(l.replicas, replica)
+	l.mu.Unlock()
+
+	l.notify([]Transition{{Replica: replica, Node: node, To: StatePending, Time: replica.since}})
+	return replica, true, nil
+}
+
+// Remove starts terminating the replica at the current simulation time.
+func (l *Lifecycle) Remove(replica *DeployableService) error {
+	if err := replica.Remove(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	transitions, err := l.advance(replica, l.now)
+	l.mu.Unlock()
+
+	l.notify(transitions)
+	return err
+}
+
+// Advance moves all replicas through the states they pass until the simulation time.
+func (l *Lifecycle) Advance(now time.Time) error {
+	l.mu.Lock()
+	var transitions []Transition
+	var errs []error
+	var remaining []*DeployableService
+	for _, replica := range l.replicas {
+		t, err := l.advance(replica, now)
+		transitions = append(transitions, t...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if replica.State() != StateRemoved {
+			remaining = append(remaining, replica)
+		}
+	}
+	l.replicas = remaining
+	l.now = now
+	l.mu.Unlock()
+
+	l.notify(transitions)
+	return errors.Join(errs...)
+}
+
+// advance moves the replica through its states until now.
+// The router and the computing unit are called once the replica is unlocked, as they read its state.
+func (l *Lifecycle) advance(r *DeployableService, now time.Time) ([]Transition, error) {
+	transitions, actions, err := l.advanceLocked(r, now)
+	errs := []error{err}
+	for _, action := range actions {
+		errs = append(errs, action())
+	}
+	return transitions, errors.Join(errs...)
+}
+
+// advanceLocked moves the replica through its states until now under its lock,
+// and returns the calls announcing the new states to its node.
+func (l *Lifecycle) advanceLocked(r *DeployableService, now time.Time) ([]Transition, []func() error, error) {
+	var transitions []Transition
+	var actions []func() error
+	move := func(to ServiceState, at time.Time) {
+		transitions = append(transitions, Transition{Replica: r, Node: r.node, From: r.state, To: to, Time: at})
+		r.state, r.since = to, at
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.since.IsZero() {
+		r.since = now
+	}
+
+	// Replicas taken off their node are gone, replicas to remove start terminating
+	if r.state != StateRemoved && !r.node.GetComputing().HostsService(r.ServiceName) {
+		move(StateRemoved, now)
+		return transitions, nil, nil
+	}
+	if r.removing {
+		r.removing = false
+		wasRunning := r.state == StateRunning
+		move(StateTerminating, now)
+		r.until = now.Add(helper.Seconds(r.ShutdownTime))
+		if wasRunning {
+			actions = append(actions, func() error { return r.node.GetRouter().WithdrawServiceAsync(r.ServiceName) })
+		}
+	}
+
+	for {
+		switch r.state {
+		case StatePending:
+			pull, ok, err := l.pullDuration(r, now)
+			if err != nil || !ok {
+				// Registry not reachable, the pull is tried again at the next advance
+				return transitions, actions, err
+			}
+			move(StatePulling, r.since)
+			r.until = r.since.Add(pull)
+		case StatePulling:
+			if r.until.After(now) {
+				return transitions, actions, nil
+			}
+			move(StateStarting, r.until)
+			r.until = r.since.Add(helper.Seconds(r.StartupTime))
+		case StateStarting:
+			if r.until.After(now) {
+				return transitions, actions, nil
+			}
+			move(StateRunning, r.until)
+			actions = append(actions, func() error { return r.node.GetRouter().AdvertiseNewServiceAsync(r.ServiceName) })
+		case StateTerminating:
+			if r.until.After(now) {
+				return transitions, actions, nil
+			}
+			move(StateRemoved, r.until)
+			actions = append(actions, func() error { return r.node.GetComputing().RemoveDeploymentAsync(r) })
+		default:
+			return transitions, actions, nil
+		}
+	}
+}
+
+// pullDuration returns the time to transfer the image of the replica from the registry to its node.
+// False if there is no route.
+func (l *Lifecycle) pullDuration(r *DeployableService, now time.Time) (time.Duration, bool, error) {
+	if r.ImageSize <= 0 || l.registry == nil {
+		return 0, true, nil
+	}
+	return TransferDuration(l.registry, r.node, r.ImageSize, now)
+}
+
+// TransferDuration returns the time to transfer size MB from one node to another, the route latency plus
+// the size at the bandwidth of the bottleneck link of the route. False if there is no route.
+func TransferDuration(from, to types.Node, size float64, now time.Time) (time.Duration, bool, error) {
+	if from == to {
+		return 0, true, nil
+	}
+	links, ok, err := capacity.Route(from, to, nil, now)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+
+	latency, bandwidth := 0.0, math.Inf(1)
+	for _, link := range links {
+		latency += link.Link.Latency()
+		bandwidth = min(bandwidth, link.Link.Bandwidth())
+	}
+	return helper.Seconds(latency/1000 + size*8e6/bandwidth), true, nil
+}
+
+// notify passes the transitions to all observers.
+func (l *Lifecycle) notify(transitions []Transition) {
+	if len(transitions) == 0 {
+		return
+	}
+	l.mu.Lock()
+	observers := append([]LifecycleObserver(nil), l.observers...)
+	l.mu.Unlock()
+	for _, t := range transitions {
+		for _, o := range observers {
+			o.OnTransition(t)
+		}
+	}
+}
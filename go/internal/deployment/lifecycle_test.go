@@ -0,0 +1,121 @@
+package deployment
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+// transitionLog records the transitions as "from>to@seconds after start".
+type transitionLog []string
+
+func (l *transitionLog) OnTransition(t Transition) {
+	*l = append(*l, fmt.Sprintf("%s>%s@%v", t.From, t.To, t.Time.Sub(start).Seconds()))
+}
+
+func TestLifecycle(t *testing.T) {
+	nodes, _ := line(t, 4, 4, 4)
+	registry, host := nodes[0], nodes[2]
+	service, err := NewDeployableService("web", 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 125 MB over 1 Gbit/s take 1 s, plus 2 ms latency over two links
+	service.ImageSize = 125
+	service.StartupTime = 2
+	service.ShutdownTime = 1
+
+	lifecycle := NewLifecycle()
+	lifecycle.SetRegistry(registry)
+	var log transitionLog
+	lifecycle.Observe(&log)
+	if err := lifecycle.Advance(start); err != nil {
+		t.Fatal(err)
+	}
+	replica, ok, err := lifecycle.Deploy(service, host)
+	if err != nil || !ok {
+		t.Fatalf("replica not deployed: %v", err)
+	}
+	if _, _, err := lifecycle.Deploy(replica, host); err == nil {
+		t.Error("replica of a replica deployed")
+	}
+
+	reachable := func() bool {
+		result, err := registry.Router.RouteToService("web", nil)
+		return err == nil && result.Reachable()
+	}
+	steps := []struct {
+		at        float64
+		remove    bool
+		state     ServiceState
+		reachable bool
+	}{
+		{0, false, StatePulling, false},
+		{1.5, false, StateStarting, false},
+		{5, false, StateRunning, true},
+		{10, true, StateTerminating, false},
+		{10.5, false, StateTerminating, false},
+		{11, false, StateRemoved, false},
+	}
+	for _, step := range steps {
+		now := start.Add(time.Duration(step.at * float64(time.Second)))
+		if err := lifecycle.Advance(now); err != nil {
+			t.Fatal(err)
+		}
+		if step.remove {
+			if err := lifecycle.Remove(replica); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if replica.State() != step.state || service.State() != step.state {
+			t.Errorf("state after %v s = %s and %s of the service, want %s", step.at, replica.State(), service.State(), step.state)
+		}
+		if reachable() != step.reachable {
+			t.Errorf("service reachable after %v s = %v, want %v", step.at, !step.reachable, step.reachable)
+		}
+	}
+
+	want := []string{
+		">pending@0",
+		"pending>pulling@0",
+		"pulling>starting@1.002",
+		"starting>running@3.002",
+		"running>terminating@10",
+		"terminating>removed@11",
+	}
+	if !slices.Equal(log, want) {
+		t.Errorf("transitions = %v, want %v", log, want)
+	}
+	if host.Computing.HostsService("web") || len(lifecycle.Replicas()) != 0 {
+		t.Error("removed replica still on its node")
+	}
+	if err := lifecycle.Remove(replica); err == nil {
+		t.Error("removed replica removed again")
+	}
+}
+
+func TestTransferDuration(t *testing.T) {
+	nodes, _ := line(t, 1, 1, 1)
+	tests := []struct {
+		name     string
+		from, to int
+		size     float64
+		want     time.Duration
+	}{
+		{"same node", 0, 0, 100, 0},
+		{"one hop", 0, 1, 125, time.Second + time.Millisecond},
+		{"two hops", 2, 0, 0, 2 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := TransferDuration(nodes[tt.from], nodes[tt.to], tt.size, start)
+			if err != nil || !ok {
+				t.Fatalf("no transfer: %v", err)
+			}
+			if got < tt.want-time.Microsecond || got > tt.want+time.Microsecond {
+				t.Errorf("transfer of %v MB takes %v, want %v", tt.size, got, tt.want)
+			}
+		})
+	}
+}
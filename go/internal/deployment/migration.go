@@ -0,0 +1,260 @@
+package deployment
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/internal/geo"
+	"github.com/keniack/stardustGo/internal/routing"
+	"github.com/keniack/stardustGo/pkg/helper"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// MigrationReporter is implemented by orchestrators which migrate replicas.
+type MigrationReporter interface {
+	// MigrationStats returns the migration statistics of the deployment, false if it does not migrate
+	MigrationStats(deployment types.DeploymentSpecification) (MigrationStats, bool)
+}
+
+// MigrationStats records the migrations of a deployment.
+type MigrationStats struct {
+	Migrations    int           // completed migrations
+	Failed        int           // migrations aborted as the new replica ended before the cutover
+	Downtime      time.Duration // time no replica served the migrating users during cutovers
+	ViolationTime time.Duration // time the mean latency of the users of any replica exceeded the threshold
+	Latency       float64       // mean latency in ms from the users to their closest replica at the last check
+}
+
+// migration moves a replica to a new host. The new replica starts while the old one keeps serving,
+// once it runs the state is transferred and the old replica is removed at the cutover.
+type migration struct {
+	from        placement
+	to          placement
+	transferEnd time.Time // zero until the state transfer started
+	stalled     bool      // the hosts were disconnected when the new replica was running
+}
+
+// migrations tracks the migrations of one deployment.
+type migrations struct {
+	active    []*migration
+	stats     MigrationStats
+	lastCheck time.Time
+}
+
+// take removes and returns the active migration away from the node, nil if there is none.
+func (m *migrations) take(n types.Node) *migration {
+	for i, mig := range m.active {
+		if mig.from.node == n {
+			m.active = append(m.active[:i], m.active[i+1:]...)
+			return mig
+		}
+	}
+	return nil
+}
+
+// MigrationStats returns the migration statistics of the deployment, false if it has no migration policy.
+func (o *PlacementOrchestrator) MigrationStats(deployment types.DeploymentSpecification) (MigrationStats, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	m, ok := o.migrations[deployment]
+	if !ok {
+		return MigrationStats{}, false
+	}
+	return m.stats, true
+}
+
+// migrate progresses the active migrations of the deployment and starts migrations of replicas
+// whose users suffer a latency above the threshold or which are about to leave the coverage of their users.
+func (o *PlacementOrchestrator) migrate(spec *PlacementSpecification, now time.Time) error {
+	policy := spec.Migration
+	if policy == nil || len(spec.Users) == 0 || o.lifecycle == nil {
+		return nil
+	}
+	state := o.migrationsOf(spec)
+	var elapsed time.Duration
+	if !state.lastCheck.IsZero() {
+		elapsed = now.Sub(state.lastCheck)
+	}
+	state.lastCheck = now
+
+	var errs []error
+	var active []*migration
+	for _, m := range state.active {
+		done, err := o.progress(spec, state, m, now)
+		errs = append(errs, err)
+		if !done {
+			active = append(active, m)
+		}
+	}
+	state.active = active
+
+	// Every user is served by its closest replica
+	placements := o.replicas[spec]
+	users := make([][]types.Node, len(placements))
+	total, unreached := 0.0, 0
+	for _, u := range spec.Users {
+		best, bestLatency := -1, math.Inf(1)
+		for i, p := range placements {
+			if l := routing.RouteLatency(u, p.node); l < bestLatency {
+				best, bestLatency = i, l
+			}
+		}
+		if best < 0 {
+			unreached++
+			continue
+		}
+		users[best] = append(users[best], u)
+		total += bestLatency
+	}
+	state.stats.Latency = math.Inf(1)
+	if unreached == 0 {
+		state.stats.Latency = total / float64(len(spec.Users))
+	}
+
+	migrating := make(map[types.Node]bool)
+	for _, m := range state.active {
+		migrating[m.from.node] = true
+	}
+	violated := unreached > 0 && policy.LatencyThreshold > 0
+	for i, p := range placements {
+		if len(users[i]) == 0 {
+			continue
+		}
+		latency := meanLatency(users[i], p.node)
+		exceeds := policy.LatencyThreshold > 0 && latency > policy.LatencyThreshold
+		violated = violated || exceeds
+		if p.replica == nil || migrating[p.node] || p.replica.State() != StateRunning {
+			continue
+		}
+		leaving := policy.CoverageHorizon > 0 && leavingCoverage(p.node, users[i], policy, now)
+		if !exceeds && !leaving {
+			continue
+		}
+
+		target, ok := o.migrationTarget(spec, users[i], latency, leaving, now)
+		if !ok {
+			continue
+		}
+		replica, placed, err := o.lifecycle.Deploy(p.replica.template, target)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if placed {
+			state.active = append(state.active, &migration{from: p, to: placement{node: target, replica: replica}})
+		}
+	}
+	if violated {
+		state.stats.ViolationTime += elapsed
+	}
+	return errors.Join(errs...)
+}
+
+// progress moves the migration on until now, true once it completed or failed.
+func (o *PlacementOrchestrator) progress(spec *PlacementSpecification, state *migrations, m *migration, now time.Time) (bool, error) {
+	if !m.to.replica.active() {
+		state.stats.Failed++
+		return true, nil
+	}
+	if m.to.replica.State() != StateRunning {
+		return false, nil
+	}
+	if m.transferEnd.IsZero() {
+		transfer, ok, err := TransferDuration(m.from.node, m.to.node, spec.Migration.StateSize, now)
+		if err != nil || !ok {
+			// Old and new host are disconnected, the transfer is tried again at the next check
+			m.stalled = true
+			return false, err
+		}
+		start := m.to.replica.Since()
+		if m.stalled {
+			start = now
+		}
+		m.transferEnd = start.Add(transfer)
+	}
+	if m.transferEnd.Add(helper.Seconds(spec.Migration.CutoverTime)).After(now) {
+		return false, nil
+	}
+
+	// Cutover, the new replica takes the place of the old one
+	for i, p := range o.replicas[spec] {
+		if p.node == m.from.node {
+			o.replicas[spec][i] = m.to
+		}
+	}
+	state.stats.Migrations++
+	state.stats.Downtime += helper.Seconds(spec.Migration.CutoverTime)
+	return true, o.lifecycle.Remove(m.from.replica)
+}
+
+// migrationTarget returns the candidate with the lowest mean latency to the users, if it improves the latency
+// or, for a host leaving coverage, keeps it below the threshold. Satellites leaving coverage are no targets.
+func (o *PlacementOrchestrator) migrationTarget(spec *PlacementSpecification, users []types.Node, latency float64, leaving bool, now time.Time) (types.Node, bool) {
+	policy := spec.Migration
+	var target types.Node
+	best := math.Inf(1)
+	for _, n := range o.simulation.GetAllNodes() {
+		if !spec.accepts(n) || !n.GetComputing().CanPlace(spec.Service()) {
+			continue
+		}
+		if policy.CoverageHorizon > 0 && leavingCoverage(n, users, policy, now) {
+			continue
+		}
+		if l := meanLatency(users, n); l < best {
+			target, best = n, l
+		}
+	}
+	if target == nil || math.IsInf(best, 1) {
+		return nil, false
+	}
+	return target, best < latency || (leaving && (policy.LatencyThreshold <= 0 || best <= policy.LatencyThreshold))
+}
+
+// leavingCoverage reports if a satellite will be below the minimum elevation of all users after the coverage horizon.
+// The positions at the end of the horizon come from the orbit or the precomputed states, ground nodes never leave coverage.
+func leavingCoverage(n types.Node, users []types.Node, policy *configs.MigrationConfig, now time.Time) bool {
+	if _, isGround := n.(types.GroundStation); isGround {
+		return false
+	}
+	horizon := now.Add(helper.Seconds(policy.CoverageHorizon))
+	host, ok := positionAt(n, horizon)
+	if !ok {
+		return false
+	}
+	for _, u := range users {
+		if user, _ := positionAt(u, horizon); geo.Elevation(user, host) >= policy.MinElevation {
+			return false
+		}
+	}
+	return true
+}
+
+// positionAt returns the position of the node at the simulation time, or its current position and false if it cannot be propagated.
+func positionAt(n types.Node, simTime time.Time) (types.Vector, bool) {
+	p, ok := n.(types.PositionPredictor)
+	if !ok {
+		return n.GetPosition(), false
+	}
+	return p.PositionAt(simTime), true
+}
+
+// migrationsOf returns the migrations of the deployment.
+func (o *PlacementOrchestrator) migrationsOf(spec *PlacementSpecification) *migrations {
+	m, ok := o.migrations[spec]
+	if !ok {
+		m = &migrations{}
+		o.migrations[spec] = m
+	}
+	return m
+}
+
+// meanLatency returns the mean route latency in ms from the users to the node.
+func meanLatency(users []types.Node, n types.Node) float64 {
+	total := 0.0
+	for _, u := range users {
+		total += routing.RouteLatency(u, n)
+	}
+	return total / float64(len(users))
+}
@@ -0,0 +1,105 @@
+package deployment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/internal/simtest"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+func TestMigrationToUsers(t *testing.T) {
+	// The user D cannot host, its closest candidate is C
+	nodes, sim := line(t, 4, 4, 4, 0)
+	lifecycle := NewLifecycle()
+	o := NewPlacementOrchestrator(FirstFitDeployment, FirstFitStrategy{}, lifecycle)
+	o.Mount(sim)
+	service, _ := NewDeployableService("web", 1, 1)
+	spec := NewPlacementSpecification(FirstFitDeployment, service, 1)
+	spec.Users = []types.Node{nodes[3]}
+	spec.Migration = &configs.MigrationConfig{LatencyThreshold: 1.5, CutoverTime: 1}
+	if err := o.CreateDeploymentAsync(spec); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		replicas string
+		latency  float64
+	}{
+		{"A", 3}, // the migration to C starts
+		{"A", 3}, // C runs, the cutover takes a second
+		{"C", 1},
+		{"C", 1},
+	}
+	for i, step := range steps {
+		sim.Time = start.Add(time.Duration(i) * time.Second)
+		if err := lifecycle.Advance(sim.Time); err != nil {
+			t.Fatal(err)
+		}
+		if err := o.CheckRescheduleAsync(spec); err != nil {
+			t.Fatal(err)
+		}
+		stats, _ := o.MigrationStats(spec)
+		if got := names(o.Replicas(spec)); got != step.replicas || stats.Latency != step.latency {
+			t.Errorf("replicas after %d s on %s with %v ms latency, want %s with %v ms", i, got, stats.Latency, step.replicas, step.latency)
+		}
+	}
+
+	stats, ok := o.MigrationStats(spec)
+	want := MigrationStats{Migrations: 1, Downtime: time.Second, ViolationTime: time.Second, Latency: 1}
+	if !ok || stats != want {
+		t.Errorf("stats = %+v, want %+v", stats, want)
+	}
+	if nodes[0].Computing.HostsService("web") {
+		t.Error("old replica still on A")
+	}
+}
+
+// orbitingNode is a node whose future positions are known.
+type orbitingNode struct {
+	*simtest.Node
+	positionAt func(simTime time.Time) types.Vector
+}
+
+func (n *orbitingNode) PositionAt(simTime time.Time) types.Vector {
+	return n.positionAt(simTime)
+}
+
+func TestLeavingCoverage(t *testing.T) {
+	const earthRadius = 6371000.0
+	user := simtest.NewGroundStation("user")
+	user.Position = types.Vector{X: earthRadius}
+	// The satellite is above the user now and above the other side of the Earth after a minute
+	orbiting := &orbitingNode{Node: simtest.NewNode("orbiting"), positionAt: func(simTime time.Time) types.Vector {
+		if simTime.After(start) {
+			return types.Vector{X: -2 * earthRadius}
+		}
+		return types.Vector{X: 2 * earthRadius}
+	}}
+	fixed := simtest.NewNode("fixed")
+	fixed.Position = types.Vector{X: -2 * earthRadius}
+	ground := simtest.NewGroundStation("ground")
+	ground.Position = types.Vector{X: -earthRadius}
+
+	policy := &configs.MigrationConfig{MinElevation: 10, CoverageHorizon: 60}
+	tests := []struct {
+		name string
+		node types.Node
+		want bool
+	}{
+		{"satellite leaving", orbiting, true},
+		{"position unknown", fixed, false},
+		{"ground station", ground, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := leavingCoverage(tt.node, []types.Node{user}, policy, start); got != tt.want {
+				t.Errorf("leaving = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if leavingCoverage(orbiting, []types.Node{user}, &configs.MigrationConfig{MinElevation: 10, CoverageHorizon: 0}, start) {
+		t.Error("satellite leaving without horizon")
+	}
+}
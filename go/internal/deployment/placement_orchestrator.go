@@ -0,0 +1,269 @@
+package deployment
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var (
+	_ types.DeploymentOrchestrator = (*PlacementOrchestrator)(nil)
+	_ SimulationAware              = (*PlacementOrchestrator)(nil)
+	_ MigrationReporter            = (*PlacementOrchestrator)(nil)
+	_ ReplicaScaler                = (*PlacementOrchestrator)(nil)
+)
+
+// ReplicaScaler is implemented by orchestrators whose deployments can be scaled in by removing a chosen replica.
+type ReplicaScaler interface {
+	// Replicas returns the nodes hosting the replicas of the deployment
+	Replicas(deployment types.DeploymentSpecification) []types.Node
+
+	// RemoveReplica removes the replica on the node and lowers the requested replicas of the deployment
+	RemoveReplica(deployment types.DeploymentSpecification, node types.Node) error
+}
+
+// PlacementOrchestrator places the replicas of PlacementSpecifications of one deployment type with its strategy.
+// Candidates are all nodes whose computing unit can place the service. Replicas of a DeployableService pass
+// through the lifecycle, other services are placed and deployed at once. Replicas of a specification with
+// migration policy follow their users.
+type PlacementOrchestrator struct {
+	deploymentType string
+	strategy       PlacementStrategy
+	lifecycle      *Lifecycle
+	simulation     types.SimulationController
+	replicas       map[types.DeploymentSpecification][]placement
+	migrations     map[types.DeploymentSpecification]*migrations
+	mu             sync.Mutex
+}
+
+// placement is a replica of a deployment on a node.
+type placement struct {
+	node    types.Node
+	replica *DeployableService // nil if the service has no lifecycle
+}
+
+// NewPlacementOrchestrator creates an orchestrator for the deployment type placing with the strategy.
+func NewPlacementOrchestrator(deploymentType string, strategy PlacementStrategy, lifecycle *Lifecycle) *PlacementOrchestrator {
+	return &PlacementOrchestrator{
+		deploymentType: deploymentType,
+		strategy:       strategy,
+		lifecycle:      lifecycle,
+		replicas:       make(map[types.DeploymentSpecification][]placement),
+		migrations:     make(map[types.DeploymentSpecification]*migrations),
+	}
+}
+
+// Mount sets the simulation whose nodes are the placement candidates
+func (o *PlacementOrchestrator) Mount(simulation types.SimulationController) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.simulation = simulation
+}
+
+// DeploymentTypes returns the deployment type of the orchestrator
+func (o *PlacementOrchestrator) DeploymentTypes() []string {
+	return []string{o.deploymentType}
+}
+
+// Replicas returns the nodes hosting the replicas of the deployment.
+func (o *PlacementOrchestrator) Replicas(deployment types.DeploymentSpecification) []types.Node {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var nodes []types.Node
+	for _, p := range o.replicas[deployment] {
+		nodes = append(nodes, p.node)
+	}
+	return nodes
+}
+
+// RemoveReplica terminates the replica on the node and lowers the requested replicas of the deployment
+func (o *PlacementOrchestrator) RemoveReplica(deployment types.DeploymentSpecification, node types.Node) error {
+	spec, err := o.specification(deployment)
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	placements := o.replicas[deployment]
+	for i, p := range placements {
+		if p.node != node {
+			continue
+		}
+		o.replicas[deployment] = append(placements[:i:i], placements[i+1:]...)
+		spec.Replicas = spec.replicas() - 1
+
+		var errs []error
+		if m, ok := o.migrations[deployment]; ok {
+			if mig := m.take(node); mig != nil {
+				errs = append(errs, o.lifecycle.Remove(mig.to.replica))
+			}
+		}
+		if p.replica != nil {
+			errs = append(errs, o.lifecycle.Remove(p.replica))
+		} else {
+			errs = append(errs, node.GetComputing().RemoveDeploymentAsync(spec.Service()))
+		}
+		return errors.Join(errs...)
+	}
+	return fmt.Errorf("no replica of %s on %s", spec.Service().GetServiceName(), node.GetName())
+}
+
+// CreateDeploymentAsync places the requested replicas
+func (o *PlacementOrchestrator) CreateDeploymentAsync(deployment types.DeploymentSpecification) error {
+	spec, err := o.specification(deployment)
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, exists := o.replicas[deployment]; exists {
+		return fmt.Errorf("deployment of %s already exists", spec.Service().GetServiceName())
+	}
+	o.replicas[deployment] = nil
+	return o.reconcile(spec)
+}
+
+// DeleteDeploymentAsync terminates all replicas
+func (o *PlacementOrchestrator) DeleteDeploymentAsync(deployment types.DeploymentSpecification) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	placements, exists := o.replicas[deployment]
+	if !exists {
+		return fmt.Errorf("deployment of %s not found", deployment.Service().GetServiceName())
+	}
+	delete(o.replicas, deployment)
+
+	var errs []error
+	if m, ok := o.migrations[deployment]; ok {
+		for _, mig := range m.active {
+			errs = append(errs, o.lifecycle.Remove(mig.to.replica))
+		}
+		delete(o.migrations, deployment)
+	}
+	for _, p := range placements {
+		if p.replica != nil {
+			errs = append(errs, o.lifecycle.Remove(p.replica))
+			continue
+		}
+		errs = append(errs, p.node.GetComputing().RemoveDeploymentAsync(deployment.Service()))
+	}
+	if _, ok := deployment.Service().(*DeployableService); !ok {
+		errs = append(errs, deployment.Service().Remove())
+	}
+	return errors.Join(errs...)
+}
+
+// CheckRescheduleAsync forgets replicas which ended or are no longer hosted by their node, places the missing ones
+// and migrates replicas away from their users
+func (o *PlacementOrchestrator) CheckRescheduleAsync(deployment types.DeploymentSpecification) error {
+	spec, err := o.specification(deployment)
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, exists := o.replicas[deployment]; !exists {
+		return fmt.Errorf("deployment of %s not found", spec.Service().GetServiceName())
+	}
+
+	var alive []placement
+	for _, p := range o.replicas[deployment] {
+		if (p.replica == nil || p.replica.active()) && p.node.GetComputing().HostsService(spec.Service().GetServiceName()) {
+			alive = append(alive, p)
+			continue
+		}
+		// A migrating replica which ended is replaced by its new replica at once
+		if m, ok := o.migrations[deployment]; ok {
+			if mig := m.take(p.node); mig != nil && mig.to.replica.active() {
+				alive = append(alive, mig.to)
+				m.stats.Migrations++
+			}
+		}
+	}
+	o.replicas[deployment] = alive
+	err = o.reconcile(spec)
+	if o.simulation == nil {
+		return err
+	}
+	return errors.Join(err, o.migrate(spec, o.simulation.GetSimulationTime()))
+}
+
+// reconcile places replicas until the requested number is reached.
+func (o *PlacementOrchestrator) reconcile(spec *PlacementSpecification) error {
+	missing := spec.replicas() - len(o.replicas[spec])
+	if missing <= 0 {
+		return nil
+	}
+	if o.simulation == nil {
+		return errors.New("orchestrator is not mounted to a simulation")
+	}
+
+	service := spec.Service()
+	isReplica := make(map[types.Node]bool)
+	var placed []types.Node
+	for _, p := range o.replicas[spec] {
+		isReplica[p.node] = true
+		placed = append(placed, p.node)
+	}
+	var candidates []types.Node
+	for _, n := range o.simulation.GetAllNodes() {
+		if !isReplica[n] && spec.accepts(n) && n.GetComputing().CanPlace(service) {
+			candidates = append(candidates, n)
+		}
+	}
+
+	for _, n := range o.strategy.Place(spec, candidates, placed, missing) {
+		p, ok, err := o.place(service, n)
+		if err != nil {
+			return err
+		}
+		if ok {
+			o.replicas[spec] = append(o.replicas[spec], p)
+		}
+	}
+	if placed := len(o.replicas[spec]); placed < spec.replicas() {
+		return fmt.Errorf("placed %d of %d replicas of %s", placed, spec.replicas(), service.GetServiceName())
+	}
+	return nil
+}
+
+// place deploys a replica of the service on the node.
+func (o *PlacementOrchestrator) place(service types.DeployableService, n types.Node) (placement, bool, error) {
+	if s, ok := service.(*DeployableService); ok && o.lifecycle != nil {
+		replica, ok, err := o.lifecycle.Deploy(s, n)
+		return placement{node: n, replica: replica}, ok, err
+	}
+
+	ok, err := n.GetComputing().TryPlaceDeploymentAsync(service)
+	if err != nil || !ok {
+		return placement{}, false, err
+	}
+	if !service.IsDeployed() {
+		if err := service.Deploy(); err != nil {
+			return placement{}, false, err
+		}
+		// Placement skipped the advertisement of the service which was not deployed yet
+		if err := n.GetRouter().AdvertiseNewServiceAsync(service.GetServiceName()); err != nil {
+			return placement{}, false, err
+		}
+	}
+	return placement{node: n}, true, nil
+}
+
+// specification returns the deployment as PlacementSpecification of the orchestrator's type.
+func (o *PlacementOrchestrator) specification(deployment types.DeploymentSpecification) (*PlacementSpecification, error) {
+	spec, ok := deployment.(*PlacementSpecification)
+	if !ok {
+		return nil, fmt.Errorf("unsupported deployment specification %T", deployment)
+	}
+	if spec.Type() != o.deploymentType {
+		return nil, fmt.Errorf("deployment type %s is not handled by %s", spec.Type(), o.deploymentType)
+	}
+	return spec, nil
+}
@@ -0,0 +1,152 @@
+package deployment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keniack/stardustGo/internal/computing"
+	"github.com/keniack/stardustGo/internal/routing"
+	"github.com/keniack/stardustGo/internal/simtest"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var start = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// line creates nodes A, B, C, ... with the given CPUs in a line of 1 ms links with 1 Gbit/s,
+// mounted with QoS routers, and a simulation of them at the start time.
+func line(t *testing.T, cpus ...float64) ([]*simtest.Node, *simtest.Simulation) {
+	t.Helper()
+	engine := routing.NewRoutingEngine(nil)
+	adverts := routing.NewServiceAdvertisementPlane()
+	sim := simtest.NewSimulation(start)
+	var nodes []*simtest.Node
+	for i, cpu := range cpus {
+		n := simtest.NewNode(string(rune('A' + i)))
+		router, err := routing.NewQosRouter(engine, adverts, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := n.Mount(router, computing.NewComputing(cpu, 1024, types.Edge)); err != nil {
+			t.Fatal(err)
+		}
+		if i > 0 {
+			simtest.Connect(nodes[i-1], n, 1, 1e9)
+		}
+		nodes = append(nodes, n)
+		sim.Nodes = append(sim.Nodes, n)
+	}
+	return nodes, sim
+}
+
+func names(nodes []types.Node) string {
+	var s string
+	for _, n := range nodes {
+		s += n.GetName()
+	}
+	return s
+}
+
+func TestPlacementStrategies(t *testing.T) {
+	// D is a user and cannot host a replica
+	nodes, _ := line(t, 2, 8, 4, 0)
+	candidates := []types.Node{nodes[0], nodes[1], nodes[2]}
+	users := &PlacementSpecification{Users: []types.Node{nodes[3]}}
+	twoUsers := &PlacementSpecification{Users: []types.Node{nodes[0], nodes[3]}}
+	tests := []struct {
+		name     string
+		strategy PlacementStrategy
+		spec     *PlacementSpecification
+		placed   []types.Node
+		count    int
+		want     string
+	}{
+		{"first-fit", FirstFitStrategy{}, users, nil, 2, "AB"},
+		{"first-fit beyond the candidates", FirstFitStrategy{}, users, nil, 5, "ABC"},
+		{"load-balancing", LoadBalancingStrategy{}, users, nil, 2, "BC"},
+		{"min-latency", MinLatencyStrategy{}, users, nil, 1, "C"},
+		{"min-latency for two users", MinLatencyStrategy{}, twoUsers, nil, 2, "AC"},
+		{"min-latency next to a placed replica", MinLatencyStrategy{}, twoUsers, []types.Node{nodes[2]}, 1, "A"},
+		{"min-latency without users", MinLatencyStrategy{}, &PlacementSpecification{}, nil, 1, "A"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(tt.strategy.Place(tt.spec, candidates, tt.placed, tt.count)); got != tt.want {
+				t.Errorf("placed on %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlacementReconcile(t *testing.T) {
+	nodes, sim := line(t, 4, 4, 4, 4)
+	o := NewPlacementOrchestrator(FirstFitDeployment, FirstFitStrategy{}, NewLifecycle())
+	service, err := NewDeployableService("web", 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := NewPlacementSpecification(FirstFitDeployment, service, 2)
+	excluded := map[types.Node]bool{nodes[3]: true}
+	spec.NodeSelector = func(n types.Node) bool { return !excluded[n] }
+
+	if err := o.CreateDeploymentAsync(spec); err == nil {
+		t.Error("deployment created without simulation")
+	}
+	o = NewPlacementOrchestrator(FirstFitDeployment, FirstFitStrategy{}, NewLifecycle())
+	o.Mount(sim)
+	if err := o.CreateDeploymentAsync(spec); err != nil {
+		t.Fatal(err)
+	}
+	if got := names(o.Replicas(spec)); got != "AB" {
+		t.Fatalf("replicas on %s, want AB", got)
+	}
+
+	// A loses its replica and is excluded, the replica moves to the next node
+	excluded[nodes[0]] = true
+	if err := nodes[0].Computing.RemoveDeploymentAsync(service.Replicas()[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.CheckRescheduleAsync(spec); err != nil {
+		t.Fatal(err)
+	}
+	if got := names(o.Replicas(spec)); got != "BC" {
+		t.Errorf("replicas after losing A on %s, want BC", got)
+	}
+
+	// Scaling out beyond the accepted nodes places as many replicas as possible
+	spec.Replicas = 3
+	if err := o.CheckRescheduleAsync(spec); err == nil {
+		t.Error("missing replica not reported")
+	}
+	if err := o.RemoveReplica(spec, nodes[1]); err != nil {
+		t.Fatal(err)
+	}
+	if got := names(o.Replicas(spec)); got != "C" || spec.Replicas != 2 {
+		t.Errorf("replicas after removing B on %s with %d requested, want C with 2", got, spec.Replicas)
+	}
+	if err := o.DeleteDeploymentAsync(spec); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.CheckRescheduleAsync(spec); err == nil {
+		t.Error("deleted deployment rescheduled")
+	}
+}
+
+func TestPlacementAntiAffinity(t *testing.T) {
+	nodes, sim := line(t, 4, 4)
+	lifecycle := NewLifecycle()
+	o := NewPlacementOrchestrator(FirstFitDeployment, FirstFitStrategy{}, lifecycle)
+	o.Mount(sim)
+	db, _ := NewDeployableService("db", 1, 1)
+	web, _ := NewDeployableService("web", 1, 1)
+	if err := o.CreateDeploymentAsync(NewPlacementSpecification(FirstFitDeployment, db, 1)); err != nil {
+		t.Fatal(err)
+	}
+	spec := NewPlacementSpecification(FirstFitDeployment, web, 1)
+	spec.AntiAffinity = []string{"db"}
+	if err := o.CreateDeploymentAsync(spec); err != nil {
+		t.Fatal(err)
+	}
+	if got := names(o.Replicas(spec)); got != "B" {
+		t.Errorf("web placed on %s next to db on %s, want B", got, nodes[0].Name)
+	}
+}
@@ -0,0 +1,60 @@
+package deployment
+
+import (
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var _ types.DeploymentSpecification = (*PlacementSpecification)(nil)
+
+// PlacementSpecification requests replicas of a service placed by the strategy of its deployment type.
+type PlacementSpecification struct {
+	DeploymentType    string
+	DeployableService types.DeployableService
+	Replicas          int                      // number of replicas, 1 if not set
+	ComputingType     types.ComputingType      // only nodes of this computing type, None or Any for all
+	NodeSelector      func(types.Node) bool    // only nodes it returns true for, nil for all
+	AntiAffinity      []string                 // services whose replicas must not share a node
+	Users             []types.Node             // nodes accessing the service, the min-latency strategy places close to them
+	Migration         *configs.MigrationConfig // migration of replicas following their users, nil to keep them in place
+}
+
+// NewPlacementSpecification creates a specification for replicas of the service.
+func NewPlacementSpecification(deploymentType string, service types.DeployableService, replicas int) *PlacementSpecification {
+	return &PlacementSpecification{
+		DeploymentType:    deploymentType,
+		DeployableService: service,
+		Replicas:          replicas,
+	}
+}
+
+// Type returns the deployment type, which selects the orchestrator
+func (s *PlacementSpecification) Type() string {
+	return s.DeploymentType
+}
+
+// Service returns the deployed service
+func (s *PlacementSpecification) Service() types.DeployableService {
+	return s.DeployableService
+}
+
+// replicas returns the requested number of replicas.
+func (s *PlacementSpecification) replicas() int {
+	return max(s.Replicas, 1)
+}
+
+// accepts reports if the node is allowed to host a replica.
+func (s *PlacementSpecification) accepts(n types.Node) bool {
+	if s.ComputingType != types.None && s.ComputingType != types.Any && n.GetComputing().GetComputingType() != s.ComputingType {
+		return false
+	}
+	if s.NodeSelector != nil && !s.NodeSelector(n) {
+		return false
+	}
+	for _, service := range s.AntiAffinity {
+		if n.GetComputing().HostsService(service) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,105 @@
+package deployment
+
+import (
+	"math"
+	"sort"
+
+	"github.com/keniack/stardustGo/internal/routing"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// Built-in deployment types, each placing with its strategy
+const (
+	FirstFitDeployment      = "first-fit"
+	MinLatencyDeployment    = "min-latency"
+	LoadBalancingDeployment = "load-balancing"
+)
+
+// PlacementStrategy chooses the nodes hosting the replicas of a deployment.
+type PlacementStrategy interface {
+	// Place selects up to count nodes out of the candidates, which all can place the service.
+	// The nodes already hosting replicas are passed as placed.
+	Place(spec *PlacementSpecification, candidates []types.Node, placed []types.Node, count int) []types.Node
+}
+
+// FirstFitStrategy places on the first candidates in simulation order.
+type FirstFitStrategy struct{}
+
+func (FirstFitStrategy) Place(_ *PlacementSpecification, candidates []types.Node, _ []types.Node, count int) []types.Node {
+	return candidates[:min(count, len(candidates))]
+}
+
+// LoadBalancingStrategy places on the candidates with the most free CPU, then the most free memory.
+type LoadBalancingStrategy struct{}
+
+func (LoadBalancingStrategy) Place(_ *PlacementSpecification, candidates []types.Node, _ []types.Node, count int) []types.Node {
+	sorted := append([]types.Node(nil), candidates...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ci, cj := sorted[i].GetComputing(), sorted[j].GetComputing()
+		if ci.CpuAvailable() != cj.CpuAvailable() {
+			return ci.CpuAvailable() > cj.CpuAvailable()
+		}
+		return ci.MemoryAvailable() > cj.MemoryAvailable()
+	})
+	return sorted[:min(count, len(sorted))]
+}
+
+// MinLatencyStrategy places the replicas so the users reach their closest replica with the lowest total latency.
+// Replicas are added greedily, each minimizing first the number of users without reachable replica, then the
+// summed latency. Without users it places like first-fit.
+type MinLatencyStrategy struct{}
+
+func (MinLatencyStrategy) Place(spec *PlacementSpecification, candidates []types.Node, placed []types.Node, count int) []types.Node {
+	if len(spec.Users) == 0 {
+		return FirstFitStrategy{}.Place(spec, candidates, placed, count)
+	}
+
+	// Latency of every user to its closest replica so far
+	best := make([]float64, len(spec.Users))
+	for u, user := range spec.Users {
+		best[u] = math.Inf(1)
+		for _, n := range placed {
+			best[u] = min(best[u], routing.RouteLatency(user, n))
+		}
+	}
+	latencies := make([][]float64, len(candidates))
+	for c, candidate := range candidates {
+		latencies[c] = make([]float64, len(spec.Users))
+		for u, user := range spec.Users {
+			latencies[c][u] = routing.RouteLatency(user, candidate)
+		}
+	}
+
+	var selected []types.Node
+	used := make([]bool, len(candidates))
+	for len(selected) < count {
+		choice := -1
+		bestUnreached, bestSum := math.MaxInt, math.Inf(1)
+		for c := range candidates {
+			if used[c] {
+				continue
+			}
+			unreached, sum := 0, 0.0
+			for u := range spec.Users {
+				l := min(best[u], latencies[c][u])
+				if math.IsInf(l, 1) {
+					unreached++
+				} else {
+					sum += l
+				}
+			}
+			if unreached < bestUnreached || (unreached == bestUnreached && sum < bestSum) {
+				choice, bestUnreached, bestSum = c, unreached, sum
+			}
+		}
+		if choice < 0 {
+			break
+		}
+		used[choice] = true
+		selected = append(selected, candidates[choice])
+		for u := range spec.Users {
+			best[u] = min(best[u], latencies[choice][u])
+		}
+	}
+	return selected
+}
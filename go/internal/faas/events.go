@@ -0,0 +1,49 @@
+package faas
+
+import (
+	"time"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+type eventKind int
+
+const (
+	arrivalEvent    eventKind = iota // an invocation reaches its node
+	completionEvent                  // an instance completes an invocation
+	expiryEvent                      // the keep-alive of an idle instance ends
+)
+
+// event is a scheduled change of the platform.
+type event struct {
+	at         time.Time
+	seq        int // order of events at the same time
+	kind       eventKind
+	invocation *Invocation
+	instance   *instance
+	node       types.Node
+}
+
+// eventQueue orders the events by time, then by scheduling order.
+type eventQueue []*event
+
+func (q eventQueue) Len() int { return len(q) }
+
+func (q eventQueue) Less(i, j int) bool {
+	if !q[i].at.Equal(q[j].at) {
+		return q[i].at.Before(q[j].at)
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q eventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *eventQueue) Push(x any) { *q = append(*q, x.(*event)) }
+
+func (q *eventQueue) Pop() any {
+	old := *q
+	ev := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return ev
+}
@@ -0,0 +1,159 @@
+package faas
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/internal/deployment"
+	"github.com/keniack/stardustGo/pkg/helper"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// Keep-alive policies of idle instances
+const (
+	FixedKeepAlive    = "fixed"    // idle instances are kept warm for KeepAlive seconds
+	AdaptiveKeepAlive = "adaptive" // idle instances are kept warm slightly longer than the 99th percentile of the observed idle times
+	NoKeepAlive       = "none"     // instances stop after every invocation
+)
+
+const (
+	adaptiveSamples = 10  // idle times observed before the adaptive policy replaces the fixed keep-alive
+	adaptiveMargin  = 1.1 // factor on the idle time percentile, so invocations at the usual interval find a warm instance
+)
+
+// Invocation is a call of a function by a client, executed on an instance of the function on a node.
+type Invocation struct {
+	Function   string
+	Client     types.Node
+	Node       types.Node
+	Issued     time.Time // time the client invoked the function
+	Arrival    time.Time // time the invocation reached the node
+	Dispatched time.Time // time an instance was assigned, zero while queued
+	Start      time.Time // time the execution started after a cold start
+	Completion time.Time // zero until completed
+	Cold       bool      // the instance was started for the invocation
+	Uplink     float64   // route latency in ms from the client to the node
+	Downlink   float64   // route latency in ms from the node back to the client
+}
+
+// Done reports if the execution completed.
+func (i *Invocation) Done() bool {
+	return !i.Completion.IsZero()
+}
+
+// QueueingDelay returns the time from the arrival until an instance was assigned.
+func (i *Invocation) QueueingDelay() time.Duration {
+	return i.Dispatched.Sub(i.Arrival)
+}
+
+// ColdStartDelay returns the time from the assignment until the instance was started, 0 for a warm start.
+func (i *Invocation) ColdStartDelay() time.Duration {
+	return i.Start.Sub(i.Dispatched)
+}
+
+// Latency returns the time from the invocation until the result reached the client.
+func (i *Invocation) Latency() time.Duration {
+	return i.Completion.Add(helper.Milliseconds(i.Downlink)).Sub(i.Issued)
+}
+
+// FunctionStats are the invocations of a function since the start of the simulation.
+type FunctionStats struct {
+	Invocations int       // accepted invocations
+	Completed   int       // completed invocations
+	ColdStarts  int       // completed invocations with a cold start
+	Rejected    int       // invocations without reachable node
+	Evictions   int       // idle instances stopped to free memory for another instance
+	Expirations int       // idle instances stopped at the end of their keep-alive
+	Latencies   []float64 // latencies in ms of the completed invocations
+}
+
+// ColdStartRatio returns the fraction of the completed invocations with a cold start.
+func (s FunctionStats) ColdStartRatio() float64 {
+	if s.Completed == 0 {
+		return 0
+	}
+	return float64(s.ColdStarts) / float64(s.Completed)
+}
+
+// Percentile returns the p-th percentile of the latencies in ms.
+func (s FunctionStats) Percentile(p float64) float64 {
+	return helper.Percentile(s.Latencies, p)
+}
+
+// MeanLatency returns the mean latency in ms.
+func (s FunctionStats) MeanLatency() float64 {
+	if len(s.Latencies) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, l := range s.Latencies {
+		sum += l
+	}
+	return sum / float64(len(s.Latencies))
+}
+
+// function is a registered function with the nodes it may run on.
+type function struct {
+	config    configs.FunctionConfig
+	policy    string
+	nodes     []types.Node
+	idleTimes []float64 // seconds idle instances waited for their next invocation
+	stats     FunctionStats
+}
+
+// newFunction validates the config and selects the nodes with enough memory for an instance.
+func newFunction(cfg configs.FunctionConfig, nodes []types.Node) (*function, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("function name cannot be empty")
+	}
+	if cfg.Memory <= 0 || cfg.ColdStart < 0 || cfg.Execution < 0 || cfg.KeepAlive < 0 || cfg.MaxInstances < 0 {
+		return nil, fmt.Errorf("function %s needs a positive memory and non-negative times and instances", cfg.Name)
+	}
+	policy := strings.ToLower(cfg.KeepAlivePolicy)
+	switch policy {
+	case "":
+		policy = FixedKeepAlive
+	case FixedKeepAlive, AdaptiveKeepAlive, NoKeepAlive:
+	default:
+		return nil, fmt.Errorf("unknown keep-alive policy of function %s: %s", cfg.Name, cfg.KeepAlivePolicy)
+	}
+	selector, err := deployment.NewNodeSelector(cfg.NodeSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid node selector of function %s: %w", cfg.Name, err)
+	}
+
+	f := &function{config: cfg, policy: policy}
+	for _, n := range nodes {
+		c, ok := computingOf(n)
+		if !ok || c.Memory < cfg.Memory {
+			continue
+		}
+		if ct := cfg.ComputingType; ct != types.None && ct != types.Any && c.GetComputingType() != ct {
+			continue
+		}
+		if selector != nil && !selector(n) {
+			continue
+		}
+		f.nodes = append(f.nodes, n)
+	}
+	if len(f.nodes) == 0 {
+		return nil, fmt.Errorf("no node can run function %s", cfg.Name)
+	}
+	return f, nil
+}
+
+// keepAlive returns how long an instance becoming idle is kept warm.
+func (f *function) keepAlive() time.Duration {
+	switch f.policy {
+	case NoKeepAlive:
+		return 0
+	case AdaptiveKeepAlive:
+		if len(f.idleTimes) >= adaptiveSamples {
+			return helper.Seconds(min(helper.Percentile(f.idleTimes, 99)*adaptiveMargin, f.config.KeepAlive))
+		}
+	}
+	return helper.Seconds(f.config.KeepAlive)
+}
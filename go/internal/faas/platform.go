@@ -0,0 +1,421 @@
+package faas
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/internal/computing"
+	"github.com/keniack/stardustGo/internal/routing"
+	"github.com/keniack/stardustGo/pkg/helper"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// Placement policies selecting the node of an invocation among the nodes of the function
+const (
+	ClosestPlacement     = "closest"      // lowest route latency from the client
+	WarmFirstPlacement   = "warm-first"   // closest node with an idle instance, else the closest node
+	LeastLoadedPlacement = "least-loaded" // fewest busy instances and queued invocations, then lowest latency
+)
+
+// Platform executes functions on the memory of the nodes' computing units in simulation time. An invocation is
+// routed to a node selected by the placement policy and served by an idle instance of the function (warm start)
+// or a new instance (cold start). Starting an instance evicts the least recently used idle instances of the node
+// if its memory is exhausted. Invocations wait in the queue of the node while the function has MaxInstances
+// busy instances there or no memory can be freed. Idle instances stop at the end of their keep-alive.
+type Platform struct {
+	placement string
+
+	mu        sync.Mutex
+	now       time.Time
+	functions map[string]*function
+	nodes     map[types.Node]*nodeState
+	events    eventQueue
+	seq       int
+	latencies map[[2]types.Node]float64 // route latencies of the current topology
+	completed []*Invocation             // completed since the previous advance
+}
+
+// nodeState are the instances and queued invocations of a node.
+type nodeState struct {
+	node      types.Node
+	computing *computing.Computing
+	instances []*instance
+	queue     []*Invocation // arrived invocations waiting for an instance, in arrival order
+}
+
+// instance is a started instance of a function on a node.
+type instance struct {
+	function *function
+	busy     bool
+	stopped  bool
+	lastUsed time.Time // time the instance became idle
+	expires  time.Time // end of the keep-alive of an idle instance
+}
+
+// NewPlatform creates a platform with the placement policy, closest if empty.
+func NewPlatform(placement string) (*Platform, error) {
+	placement = strings.ToLower(placement)
+	switch placement {
+	case "":
+		placement = ClosestPlacement
+	case ClosestPlacement, WarmFirstPlacement, LeastLoadedPlacement:
+	default:
+		return nil, fmt.Errorf("unknown function placement: %s", placement)
+	}
+	return &Platform{
+		placement: placement,
+		functions: make(map[string]*function),
+		nodes:     make(map[types.Node]*nodeState),
+		latencies: make(map[[2]types.Node]float64),
+	}, nil
+}
+
+// Placement returns the placement policy.
+func (p *Platform) Placement() string {
+	return p.placement
+}
+
+// Register adds a function which runs on the nodes matching its config.
+func (p *Platform) Register(cfg configs.FunctionConfig, nodes []types.Node) error {
+	f, err := newFunction(cfg, nodes)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.functions[cfg.Name]; ok {
+		return fmt.Errorf("function %s already registered", cfg.Name)
+	}
+	p.functions[cfg.Name] = f
+	return nil
+}
+
+// Functions returns the sorted names of the registered functions.
+func (p *Platform) Functions() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	names := make([]string, 0, len(p.functions))
+	for name := range p.functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Stats returns the invocations of the function.
+func (p *Platform) Stats(name string) FunctionStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	f, ok := p.functions[name]
+	if !ok {
+		return FunctionStats{}
+	}
+	stats := f.stats
+	stats.Latencies = append([]float64(nil), f.stats.Latencies...)
+	return stats
+}
+
+// Instances returns the busy and idle instances of the function on the node.
+func (p *Platform) Instances(n types.Node, name string) (busy, idle int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if state, ok := p.nodes[n]; ok {
+		for _, inst := range state.instances {
+			if inst.function.config.Name != name {
+				continue
+			}
+			if inst.busy {
+				busy++
+			} else {
+				idle++
+			}
+		}
+	}
+	return busy, idle
+}
+
+// Refresh starts a new simulation step, invocations are routed over the new topology.
+func (p *Platform) Refresh() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	clear(p.latencies)
+}
+
+// Invoke routes an invocation of the function issued by the client to a node, where it arrives after the route
+// latency. An invocation issued before the time of the platform arrives at this time at the earliest.
+func (p *Platform) Invoke(client types.Node, name string, issued time.Time) (*Invocation, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	f, ok := p.functions[name]
+	if !ok {
+		return nil, fmt.Errorf("function %s not registered", name)
+	}
+
+	target, uplink := p.place(f, client)
+	if target == nil {
+		f.stats.Rejected++
+		return nil, fmt.Errorf("no node of function %s reachable from %s", name, client.GetName())
+	}
+	downlink := p.latency(target, client)
+	if math.IsInf(downlink, 1) {
+		f.stats.Rejected++
+		return nil, fmt.Errorf("%s is unreachable from %s", client.GetName(), target.GetName())
+	}
+
+	inv := &Invocation{
+		Function: name,
+		Client:   client,
+		Node:     target,
+		Issued:   issued,
+		Arrival:  issued.Add(helper.Milliseconds(uplink)),
+		Uplink:   uplink,
+		Downlink: downlink,
+	}
+	if inv.Arrival.Before(p.now) {
+		inv.Arrival = p.now
+	}
+	f.stats.Invocations++
+	p.push(&event{at: inv.Arrival, kind: arrivalEvent, invocation: inv})
+	return inv, nil
+}
+
+// Advance executes the invocations until now and returns the invocations completed since the previous advance.
+func (p *Platform) Advance(now time.Time) []*Invocation {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for len(p.events) > 0 && !p.events[0].at.After(now) {
+		ev := heap.Pop(&p.events).(*event)
+		p.now = ev.at
+		switch ev.kind {
+		case arrivalEvent:
+			state := p.state(ev.invocation.Node)
+			state.queue = append(state.queue, ev.invocation)
+			p.dispatch(state)
+		case completionEvent:
+			p.complete(ev)
+		case expiryEvent:
+			if inst := ev.instance; !inst.stopped && !inst.busy && inst.expires.Equal(ev.at) {
+				inst.function.stats.Expirations++
+				p.stop(p.state(ev.node), inst)
+			}
+		}
+	}
+	if now.After(p.now) {
+		p.now = now
+	}
+
+	completed := p.completed
+	p.completed = nil
+	return completed
+}
+
+// place returns the node of an invocation of the function by the client and its route latency.
+func (p *Platform) place(f *function, client types.Node) (types.Node, float64) {
+	var best types.Node
+	bestLatency, bestLoad, bestWarm := math.Inf(1), math.MaxInt, false
+	for _, n := range f.nodes {
+		l := p.latency(client, n)
+		if math.IsInf(l, 1) {
+			continue
+		}
+		switch p.placement {
+		case WarmFirstPlacement:
+			warm := p.idle(n, f) != nil
+			if (warm && !bestWarm) || (warm == bestWarm && l < bestLatency) {
+				best, bestLatency, bestWarm = n, l, warm
+			}
+		case LeastLoadedPlacement:
+			load := p.load(n)
+			if load < bestLoad || (load == bestLoad && l < bestLatency) {
+				best, bestLatency, bestLoad = n, l, load
+			}
+		default:
+			if l < bestLatency {
+				best, bestLatency = n, l
+			}
+		}
+	}
+	return best, bestLatency
+}
+
+// latency returns the route latency in ms between the nodes in the current topology, cached for the step.
+func (p *Platform) latency(from, to types.Node) float64 {
+	key := [2]types.Node{from, to}
+	if l, ok := p.latencies[key]; ok {
+		return l
+	}
+	l := routing.RouteLatency(from, to)
+	p.latencies[key] = l
+	return l
+}
+
+// load returns the busy instances and queued invocations of the node.
+func (p *Platform) load(n types.Node) int {
+	state, ok := p.nodes[n]
+	if !ok {
+		return 0
+	}
+	load := len(state.queue)
+	for _, inst := range state.instances {
+		if inst.busy {
+			load++
+		}
+	}
+	return load
+}
+
+// idle returns the most recently used idle instance of the function on the node, nil if none.
+func (p *Platform) idle(n types.Node, f *function) *instance {
+	state, ok := p.nodes[n]
+	if !ok {
+		return nil
+	}
+	var best *instance
+	for _, inst := range state.instances {
+		if inst.function == f && !inst.busy && (best == nil || inst.lastUsed.After(best.lastUsed)) {
+			best = inst
+		}
+	}
+	return best
+}
+
+// state returns the state of the node, created on first use.
+func (p *Platform) state(n types.Node) *nodeState {
+	state, ok := p.nodes[n]
+	if !ok {
+		c, _ := computingOf(n)
+		state = &nodeState{node: n, computing: c}
+		p.nodes[n] = state
+	}
+	return state
+}
+
+// dispatch assigns instances to the queued invocations of the node in arrival order.
+func (p *Platform) dispatch(state *nodeState) {
+	queue := state.queue[:0]
+	for _, inv := range state.queue {
+		if !p.serve(state, inv) {
+			queue = append(queue, inv)
+		}
+	}
+	clear(state.queue[len(queue):])
+	state.queue = queue
+}
+
+// serve assigns an idle instance or starts a new one for the invocation, false if it has to wait.
+func (p *Platform) serve(state *nodeState, inv *Invocation) bool {
+	f := p.functions[inv.Function]
+	inst := p.idle(state.node, f)
+	if inst != nil {
+		f.idleTimes = append(f.idleTimes, p.now.Sub(inst.lastUsed).Seconds())
+		inv.Start = p.now
+	} else {
+		if f.config.MaxInstances > 0 && p.count(state, f) >= f.config.MaxInstances {
+			return false
+		}
+		// Idle instances are only evicted if this frees enough memory for the new instance
+		idleMemory := 0.0
+		for _, i := range state.instances {
+			if !i.busy {
+				idleMemory += i.function.config.Memory
+			}
+		}
+		if f.config.Memory > state.computing.MemoryAvailable()+idleMemory {
+			return false
+		}
+		for !state.computing.ReserveMemory(f.config.Memory) {
+			victim := p.leastRecentlyUsed(state)
+			if victim == nil {
+				return false
+			}
+			victim.function.stats.Evictions++
+			p.stop(state, victim)
+		}
+		inst = &instance{function: f}
+		state.instances = append(state.instances, inst)
+		inv.Cold = true
+		inv.Start = p.now.Add(helper.Seconds(f.config.ColdStart))
+	}
+
+	inst.busy = true
+	inv.Dispatched = p.now
+	p.push(&event{at: inv.Start.Add(helper.Seconds(f.config.Execution)), kind: completionEvent, invocation: inv, instance: inst, node: state.node})
+	return true
+}
+
+// complete finishes the invocation, its instance serves the next queued invocation or stays idle for its keep-alive.
+func (p *Platform) complete(ev *event) {
+	inv, inst := ev.invocation, ev.instance
+	f := inst.function
+	inv.Completion = p.now
+	f.stats.Completed++
+	if inv.Cold {
+		f.stats.ColdStarts++
+	}
+	f.stats.Latencies = append(f.stats.Latencies, helper.ToMilliseconds(inv.Latency()))
+	p.completed = append(p.completed, inv)
+
+	state := p.state(ev.node)
+	inst.busy = false
+	inst.lastUsed = p.now
+	p.dispatch(state)
+	if inst.busy {
+		return
+	}
+	keepAlive := f.keepAlive()
+	if keepAlive <= 0 {
+		p.stop(state, inst)
+		p.dispatch(state)
+		return
+	}
+	inst.expires = p.now.Add(keepAlive)
+	p.push(&event{at: inst.expires, kind: expiryEvent, instance: inst, node: state.node})
+}
+
+// stop removes the instance from the node and releases its memory.
+func (p *Platform) stop(state *nodeState, inst *instance) {
+	inst.stopped = true
+	state.instances = slices.DeleteFunc(state.instances, func(i *instance) bool { return i == inst })
+	state.computing.ReleaseMemory(inst.function.config.Memory)
+}
+
+// count returns the instances of the function on the node.
+func (p *Platform) count(state *nodeState, f *function) int {
+	count := 0
+	for _, inst := range state.instances {
+		if inst.function == f {
+			count++
+		}
+	}
+	return count
+}
+
+// leastRecentlyUsed returns the idle instance of the node which has been idle the longest, nil if none.
+func (p *Platform) leastRecentlyUsed(state *nodeState) *instance {
+	var victim *instance
+	for _, inst := range state.instances {
+		if !inst.busy && (victim == nil || inst.lastUsed.Before(victim.lastUsed)) {
+			victim = inst
+		}
+	}
+	return victim
+}
+
+func (p *Platform) push(ev *event) {
+	ev.seq = p.seq
+	p.seq++
+	heap.Push(&p.events, ev)
+}
+
+// computingOf returns the computing unit of the node if function instances can reserve its memory.
+func computingOf(n types.Node) (*computing.Computing, bool) {
+	c, ok := n.GetComputing().(*computing.Computing)
+	return c, ok
+}
@@ -0,0 +1,178 @@
+package faas
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/internal/computing"
+	"github.com/keniack/stardustGo/internal/routing"
+	"github.com/keniack/stardustGo/internal/simtest"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var start = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// pair connects the client A without computing unit to the node B with the given memory by a link of 1 ms.
+func pair(t *testing.T, memory float64) (client, node *simtest.Node) {
+	t.Helper()
+	engine := routing.NewRoutingEngine(nil)
+	adverts := routing.NewServiceAdvertisementPlane()
+	client, node = simtest.NewNode("A"), simtest.NewNode("B")
+	for _, n := range []*simtest.Node{client, node} {
+		router, err := routing.NewQosRouter(engine, adverts, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var c types.Computing
+		if n == node {
+			c = computing.NewComputing(8, memory, types.Edge)
+		}
+		if err := n.Mount(router, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	simtest.Connect(client, node, 1, 1e9)
+	return client, node
+}
+
+// platform registers the functions on the node.
+func platform(t *testing.T, node types.Node, functions ...configs.FunctionConfig) *Platform {
+	t.Helper()
+	p, err := NewPlatform("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range functions {
+		if err := p.Register(f, []types.Node{node}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return p
+}
+
+func invoke(t *testing.T, p *Platform, client types.Node, name string, issued time.Time) *Invocation {
+	t.Helper()
+	inv, err := p.Invoke(client, name, issued)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return inv
+}
+
+func TestPlatformColdAndWarmStart(t *testing.T) {
+	client, node := pair(t, 1024)
+	p := platform(t, node, configs.FunctionConfig{Name: "f", Memory: 128, ColdStart: 1, Execution: 1, KeepAlive: 10})
+
+	cold := invoke(t, p, client, "f", start)
+	p.Advance(start.Add(5 * time.Second))
+	warm := invoke(t, p, client, "f", start.Add(5*time.Second))
+	p.Advance(start.Add(10 * time.Second))
+
+	tests := []struct {
+		name       string
+		invocation *Invocation
+		cold       bool
+		coldStart  time.Duration
+		latency    time.Duration
+	}{
+		{"cold start", cold, true, time.Second, 2002 * time.Millisecond},
+		{"warm start", warm, false, 0, 1002 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv := tt.invocation
+			if !inv.Done() || inv.Cold != tt.cold || inv.ColdStartDelay() != tt.coldStart || inv.Latency() != tt.latency {
+				t.Errorf("done %v, cold %v with delay %v and latency %v, want cold %v with delay %v and latency %v",
+					inv.Done(), inv.Cold, inv.ColdStartDelay(), inv.Latency(), tt.cold, tt.coldStart, tt.latency)
+			}
+		})
+	}
+	if busy, idle := p.Instances(node, "f"); busy != 0 || idle != 1 {
+		t.Errorf("%d busy and %d idle instances, want 1 idle", busy, idle)
+	}
+
+	// The idle instance expires 10 s after the completion of the warm start at 6.001 s
+	p.Advance(start.Add(17 * time.Second))
+	if busy, idle := p.Instances(node, "f"); busy != 0 || idle != 0 {
+		t.Errorf("%d busy and %d idle instances after the keep-alive, want none", busy, idle)
+	}
+	stats := p.Stats("f")
+	if stats.Completed != 2 || stats.ColdStarts != 1 || stats.Expirations != 1 || stats.ColdStartRatio() != 0.5 {
+		t.Errorf("stats = %+v, want 2 completed with 1 cold start and 1 expiration", stats)
+	}
+}
+
+func TestPlatformEviction(t *testing.T) {
+	client, node := pair(t, 200)
+	p := platform(t, node,
+		configs.FunctionConfig{Name: "f", Memory: 128, Execution: 1, KeepAlive: 60},
+		configs.FunctionConfig{Name: "g", Memory: 128, Execution: 1, KeepAlive: 60})
+
+	invoke(t, p, client, "f", start)
+	p.Advance(start.Add(2 * time.Second))
+	// g needs the memory of the idle instance of f
+	inv := invoke(t, p, client, "g", start.Add(2*time.Second))
+	p.Advance(start.Add(4 * time.Second))
+
+	if !inv.Done() || !inv.Cold {
+		t.Errorf("invocation of g done %v and cold %v, want a completed cold start", inv.Done(), inv.Cold)
+	}
+	if _, idle := p.Instances(node, "f"); idle != 0 {
+		t.Errorf("%d idle instances of f, want it evicted", idle)
+	}
+	if evictions := p.Stats("f").Evictions; evictions != 1 {
+		t.Errorf("%d evictions of f, want 1", evictions)
+	}
+}
+
+func TestPlatformMaxInstances(t *testing.T) {
+	client, node := pair(t, 1024)
+	p := platform(t, node, configs.FunctionConfig{Name: "f", Memory: 128, ColdStart: 1, Execution: 1, KeepAlive: 60, MaxInstances: 1})
+
+	var invocations []*Invocation
+	for range 3 {
+		invocations = append(invocations, invoke(t, p, client, "f", start))
+	}
+	p.Advance(start.Add(time.Second))
+	if busy, idle := p.Instances(node, "f"); busy != 1 || idle != 0 {
+		t.Errorf("%d busy and %d idle instances, want 1 busy", busy, idle)
+	}
+	p.Advance(start.Add(10 * time.Second))
+
+	// The queued invocations are served by the instance in arrival order
+	for i, want := range []time.Duration{0, 2 * time.Second, 3 * time.Second} {
+		inv := invocations[i]
+		if !inv.Done() || inv.QueueingDelay() != want || inv.Cold != (i == 0) {
+			t.Errorf("invocation %d done %v, cold %v and queued %v, want queued %v", i, inv.Done(), inv.Cold, inv.QueueingDelay(), want)
+		}
+	}
+}
+
+func TestPlatformErrors(t *testing.T) {
+	client, node := pair(t, 100)
+	if _, err := NewPlatform("random"); err == nil {
+		t.Error("unknown placement accepted")
+	}
+	p := platform(t, node, configs.FunctionConfig{Name: "f", Memory: 64})
+	tests := []struct {
+		name string
+		cfg  configs.FunctionConfig
+	}{
+		{"no name", configs.FunctionConfig{Memory: 64}},
+		{"duplicate", configs.FunctionConfig{Name: "f", Memory: 64}},
+		{"no memory", configs.FunctionConfig{Name: "g"}},
+		{"unknown keep-alive policy", configs.FunctionConfig{Name: "g", Memory: 64, KeepAlivePolicy: "random"}},
+		{"no node with enough memory", configs.FunctionConfig{Name: "g", Memory: 128}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := p.Register(tt.cfg, []types.Node{node}); err == nil {
+				t.Error("function registered")
+			}
+		})
+	}
+	if _, err := p.Invoke(client, "g", start); err == nil {
+		t.Error("unregistered function invoked")
+	}
+}
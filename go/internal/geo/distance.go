@@ -0,0 +1,16 @@
+package geo
+
+import "math"
+
+// meanEarthRadius is the radius of the sphere approximating the Earth for great-circle distances
+const meanEarthRadius = 6371008.8 // in meters
+
+// Distance returns the great-circle distance between two coordinates in meters.
+func Distance(a, b Coordinate) float64 {
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * meanEarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
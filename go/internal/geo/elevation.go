@@ -0,0 +1,19 @@
+package geo
+
+import (
+	"math"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// Elevation returns the angle in degrees of the target above the horizon of an observer on the Earth's surface.
+// The horizon is approximated by the plane normal to the observer's position vector.
+func Elevation(observer, target types.Vector) float64 {
+	los := observer.Subtract(target)
+	distance := los.Abs()
+	if distance == 0 {
+		return 90
+	}
+	sin := los.Dot(observer.Normalize()) / distance
+	return math.Asin(math.Max(-1, math.Min(1, sin))) * 180 / math.Pi
+}
@@ -0,0 +1,30 @@
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+func TestElevation(t *testing.T) {
+	observer := types.Vector{X: 6371000}
+	tests := []struct {
+		name   string
+		target types.Vector
+		want   float64
+	}{
+		{"zenith", types.Vector{X: 7000000}, 90},
+		{"horizon", types.Vector{X: 6371000, Y: 1000000}, 0},
+		{"45 degrees", types.Vector{X: 7371000, Y: 1000000}, 45},
+		{"other side of the Earth", types.Vector{X: -7000000}, -90},
+		{"same position", observer, 90},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Elevation(observer, tt.target); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Elevation = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
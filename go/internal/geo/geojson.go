@@ -0,0 +1,147 @@
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// geoJSON covers the GeoJSON objects needed for regions: feature collections, features and (multi) polygons.
+type geoJSON struct {
+	Type        string                 `json:"type"`
+	Features    []geoJSON              `json:"features"`
+	Geometry    *geoJSON               `json:"geometry"`
+	Geometries  []geoJSON              `json:"geometries"`
+	Properties  map[string]interface{} `json:"properties"`
+	ID          interface{}            `json:"id"`
+	Coordinates json.RawMessage        `json:"coordinates"`
+}
+
+// LoadGeoJSON loads the regions from a GeoJSON file.
+// See ParseGeoJSON for how the regions are named.
+func LoadGeoJSON(path string) (*Regions, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	regions, err := ParseGeoJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return regions, nil
+}
+
+// ParseGeoJSON parses regions from a GeoJSON feature collection or feature.
+// Every feature with a Polygon or MultiPolygon geometry becomes a region named after its "name" property,
+// or its id if it has no name. Features with the same name are merged into one region.
+func ParseGeoJSON(data []byte) (*Regions, error) {
+	var root geoJSON
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+
+	regions := NewRegions()
+	switch root.Type {
+	case "FeatureCollection":
+		for i, f := range root.Features {
+			if err := addFeature(regions, f); err != nil {
+				return nil, fmt.Errorf("feature %d: %w", i, err)
+			}
+		}
+	case "Feature":
+		if err := addFeature(regions, root); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported GeoJSON type %q, expected FeatureCollection or Feature", root.Type)
+	}
+	return regions, nil
+}
+
+func addFeature(regions *Regions, f geoJSON) error {
+	name := featureName(f)
+	if name == "" {
+		return fmt.Errorf("feature has neither a name property nor an id")
+	}
+	if f.Geometry == nil {
+		return fmt.Errorf("feature %s has no geometry", name)
+	}
+	polygons, err := geometryPolygons(*f.Geometry)
+	if err != nil {
+		return fmt.Errorf("feature %s: %w", name, err)
+	}
+	regions.add(name, polygons...)
+	return nil
+}
+
+func featureName(f geoJSON) string {
+	if name, ok := f.Properties["name"].(string); ok && name != "" {
+		return name
+	}
+	switch id := f.ID.(type) {
+	case string:
+		return id
+	case float64:
+		return fmt.Sprint(id)
+	}
+	return ""
+}
+
+func geometryPolygons(g geoJSON) ([]polygon, error) {
+	switch g.Type {
+	case "Polygon":
+		var rings [][][]float64
+		if err := json.Unmarshal(g.Coordinates, &rings); err != nil {
+			return nil, err
+		}
+		p, err := toPolygon(rings)
+		if err != nil {
+			return nil, err
+		}
+		return []polygon{p}, nil
+	case "MultiPolygon":
+		var multi [][][][]float64
+		if err := json.Unmarshal(g.Coordinates, &multi); err != nil {
+			return nil, err
+		}
+		polygons := make([]polygon, 0, len(multi))
+		for _, rings := range multi {
+			p, err := toPolygon(rings)
+			if err != nil {
+				return nil, err
+			}
+			polygons = append(polygons, p)
+		}
+		return polygons, nil
+	case "GeometryCollection":
+		var polygons []polygon
+		for _, member := range g.Geometries {
+			p, err := geometryPolygons(member)
+			if err != nil {
+				return nil, err
+			}
+			polygons = append(polygons, p...)
+		}
+		return polygons, nil
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %q, expected Polygon or MultiPolygon", g.Type)
+	}
+}
+
+// toPolygon converts GeoJSON rings of [longitude, latitude] positions.
+func toPolygon(rings [][][]float64) (polygon, error) {
+	p := make(polygon, len(rings))
+	for i, ring := range rings {
+		if len(ring) < 4 {
+			return nil, fmt.Errorf("linear ring needs at least 4 positions, got %d", len(ring))
+		}
+		p[i] = make([]Coordinate, len(ring))
+		for j, pos := range ring {
+			if len(pos) < 2 {
+				return nil, fmt.Errorf("position needs longitude and latitude")
+			}
+			p[i][j] = Coordinate{Latitude: pos[1], Longitude: pos[0]}
+		}
+	}
+	return p, nil
+}
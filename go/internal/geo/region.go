@@ -0,0 +1,99 @@
+package geo
+
+import "sort"
+
+// Coordinate is a geographic position in degrees.
+type Coordinate struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// polygon is an outer ring followed by its holes, each ring a list of coordinates as in GeoJSON.
+type polygon [][]Coordinate
+
+// Region is a named area on the Earth's surface made of one or more polygons.
+type Region struct {
+	Name     string
+	polygons []polygon
+}
+
+// Contains checks if the coordinate lies inside one of the polygons of the region.
+// Polygons must not cross the antimeridian, GeoJSON requires such polygons to be split anyway.
+func (r *Region) Contains(c Coordinate) bool {
+	for _, p := range r.polygons {
+		if len(p) == 0 || !ringContains(p[0], c) {
+			continue
+		}
+		inHole := false
+		for _, hole := range p[1:] {
+			if ringContains(hole, c) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// ringContains runs the even-odd ray casting test in the longitude/latitude plane.
+func ringContains(ring []Coordinate, c Coordinate) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Latitude > c.Latitude) != (b.Latitude > c.Latitude) {
+			lon := a.Longitude + (c.Latitude-a.Latitude)/(b.Latitude-a.Latitude)*(b.Longitude-a.Longitude)
+			if c.Longitude < lon {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// Regions is a set of regions addressed by name.
+type Regions struct {
+	byName map[string]*Region
+	names  []string
+}
+
+// NewRegions creates an empty region set.
+func NewRegions() *Regions {
+	return &Regions{byName: make(map[string]*Region)}
+}
+
+// add appends the polygons to the region with the given name, creating it if needed.
+func (rs *Regions) add(name string, polygons ...polygon) {
+	r, ok := rs.byName[name]
+	if !ok {
+		r = &Region{Name: name}
+		rs.byName[name] = r
+		rs.names = append(rs.names, name)
+		sort.Strings(rs.names)
+	}
+	r.polygons = append(r.polygons, polygons...)
+}
+
+// Get returns the region with the given name.
+func (rs *Regions) Get(name string) (*Region, bool) {
+	r, ok := rs.byName[name]
+	return r, ok
+}
+
+// Names returns the sorted names of all regions.
+func (rs *Regions) Names() []string {
+	return rs.names
+}
+
+// Containing returns the sorted names of all regions containing the coordinate.
+func (rs *Regions) Containing(c Coordinate) []string {
+	var names []string
+	for _, name := range rs.names {
+		if rs.byName[name].Contains(c) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
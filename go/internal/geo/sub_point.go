@@ -0,0 +1,51 @@
+package geo
+
+import (
+	"math"
+	"time"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// WGS84 ellipsoid and Earth rotation as used for the ground station positions
+const (
+	semiMajorAxis      = 6378137.0      // in meters
+	semiMinorAxis      = 6356752.314245 // in meters
+	EarthRotationSpeed = 7.2921150e-5   // in rad/s
+)
+
+// SubPoint returns the geodetic coordinate of the point on the Earth's surface below the position.
+// Positions are given in the simulation frame, which matches the Earth-fixed frame at the simulation start
+// and stays inertial, so elapsed is the simulation time passed since then.
+func SubPoint(position types.Vector, elapsed time.Duration) Coordinate {
+	const e2 = 1 - (semiMinorAxis*semiMinorAxis)/(semiMajorAxis*semiMajorAxis)
+
+	// Undo the Earth rotation since the simulation start
+	theta := -EarthRotationSpeed * elapsed.Seconds()
+	x := position.X*math.Cos(theta) - position.Y*math.Sin(theta)
+	y := position.X*math.Sin(theta) + position.Y*math.Cos(theta)
+	z := position.Z
+
+	p := math.Hypot(x, y)
+	lat := math.Atan2(z, p*(1-e2))
+	for i := 0; i < 5; i++ {
+		sin := math.Sin(lat)
+		n := semiMajorAxis / math.Sqrt(1-e2*sin*sin)
+		h := p/math.Cos(lat) - n
+		lat = math.Atan2(z, p*(1-e2*n/(n+h)))
+	}
+
+	return Coordinate{
+		Latitude:  lat * 180 / math.Pi,
+		Longitude: normalizeLongitude(math.Atan2(y, x) * 180 / math.Pi),
+	}
+}
+
+// normalizeLongitude maps a longitude in degrees to [-180, 180).
+func normalizeLongitude(lon float64) float64 {
+	lon = math.Mod(lon+180, 360)
+	if lon < 0 {
+		lon += 360
+	}
+	return lon - 180
+}
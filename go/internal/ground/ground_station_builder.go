@@ -17,6 +17,9 @@ type GroundStationBuilder struct {
 	latitude  float64
 	longitude float64
 	altitude  float64
+	router    string
+	tags      []string
+	weight    float64
 
 	simStartTime     time.Time
 	protocolBuilder  *links.GroundProtocolBuilder
@@ -58,6 +61,25 @@ func (b *GroundStationBuilder) SetAltitude(value float64) *GroundStationBuilder
 	return b
 }
 
+// SetRouter sets the router protocol of the ground station, "default" or empty for the configured one,
+// and returns the builder for chaining.
+func (b *GroundStationBuilder) SetRouter(value string) *GroundStationBuilder {
+	b.router = value
+	return b
+}
+
+// SetTags sets the tags of the ground station, e.g. to select it for anycast groups, and returns the builder for chaining.
+func (b *GroundStationBuilder) SetTags(tags []string) *GroundStationBuilder {
+	b.tags = tags
+	return b
+}
+
+// SetWeight sets the weight of the ground station in traffic models, e.g. its population, and returns the builder for chaining.
+func (b *GroundStationBuilder) SetWeight(value float64) *GroundStationBuilder {
+	b.weight = value
+	return b
+}
+
 // SetComputingType sets the computing type for the ground station and returns the builder for chaining.
 func (b *GroundStationBuilder) SetComputingType(value string) *GroundStationBuilder {
 	ctype, _ := types.ToComputingType(value)
@@ -74,17 +96,20 @@ func (b *GroundStationBuilder) ConfigureGroundLinkProtocol(fn func(*links.Ground
 // Build constructs and returns a new GroundStation using the configured properties.
 // It panics if the router cannot be built.
 func (b *GroundStationBuilder) Build() types.GroundStation {
-	router, err := b.routerBuilder.Build()
+	router, err := b.routerBuilder.BuildProtocol(b.router)
 	if err != nil {
 		panic(err)
 	}
 
-	return node.NewGroundStation(
+	station := node.NewGroundStation(
 		b.name,
 		b.latitude,
 		b.longitude,
 		b.protocolBuilder.Build(),
 		b.simStartTime,
 		router,
-		b.computingBuilder.Build())
+		b.computingBuilder.WithNode(computing.NodeDescriptor{Name: b.name, Tags: b.tags}).Build())
+	station.Tags = b.tags
+	station.Weight = b.weight
+	return station
 }
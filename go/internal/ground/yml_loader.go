@@ -11,12 +11,14 @@ import (
 )
 
 type rawGroundStation struct {
-	Name          string  `yaml:"Name"`
-	Lat           float64 `yaml:"Lat"`
-	Lon           float64 `yaml:"Lon"`
-	Protocol      string  `yaml:"Protocol"`
-	Router        string  `yaml:"Router"`
-	ComputingType string  `yaml:"ComputingType"`
+	Name          string   `yaml:"Name"`
+	Lat           float64  `yaml:"Lat"`
+	Lon           float64  `yaml:"Lon"`
+	Protocol      string   `yaml:"Protocol"`
+	Router        string   `yaml:"Router"`
+	ComputingType string   `yaml:"ComputingType"`
+	Tags          []string `yaml:"Tags"`
+	Weight        float64  `yaml:"Weight"`
 }
 
 // GroundStationYmlLoader is responsible for loading ground station configurations from a YAML file.
@@ -58,6 +60,9 @@ func (l *GroundStationYmlLoader) Load(path string, satellites []types.Satellite)
 			SetName(gs.Name).
 			SetLatitude(gs.Lat).
 			SetLongitude(gs.Lon).
+			SetRouter(gs.Router).
+			SetTags(gs.Tags).
+			SetWeight(gs.Weight).
 			SetComputingType(gs.ComputingType).
 			ConfigureGroundLinkProtocol(func(p *links.GroundProtocolBuilder) *links.GroundProtocolBuilder {
 				return p.
@@ -26,7 +26,7 @@ func (gl *GroundLink) Distance() float64 {
 
 // Latency returns the one-way latency in milliseconds.
 func (gl *GroundLink) Latency() float64 {
-	return gl.Distance() / linkSpeed * 1000
+	return PropagationLatency(gl.Distance())
 }
 
 // Bandwidth returns the link bandwidth in bits per second.
@@ -9,6 +9,11 @@ var _ types.Link = (*IslLink)(nil)
 
 const linkSpeed = configs.SpeedOfLight * 0.99 // 99% of light speed
 
+// PropagationLatency returns the one-way latency in milliseconds over the given distance in meters.
+func PropagationLatency(distance float64) float64 {
+	return distance / linkSpeed * 1000
+}
+
 // IslLink represents an inter-satellite laser link.
 type IslLink struct {
 	Node1 types.Node
@@ -30,7 +35,7 @@ func (l *IslLink) Distance() float64 {
 
 // Latency returns the communication latency in milliseconds.
 func (l *IslLink) Latency() float64 {
-	return l.Distance() / linkSpeed * 1000
+	return PropagationLatency(l.Distance())
 }
 
 // Bandwidth returns the bandwidth in bits per second.
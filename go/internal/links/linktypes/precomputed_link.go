@@ -26,7 +26,7 @@ func (l *PrecomputedLink) Distance() float64 {
 }
 
 func (l *PrecomputedLink) Latency() float64 {
-	return l.Distance() / linkSpeed * 1000
+	return PropagationLatency(l.Distance())
 }
 
 func (l *PrecomputedLink) Bandwidth() float64 {
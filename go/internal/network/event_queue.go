@@ -0,0 +1,54 @@
+package network
+
+import (
+	"container/heap"
+	"time"
+)
+
+// event is an action scheduled at a point in simulation time.
+type event struct {
+	at  time.Time
+	seq uint64 // insertion order, keeps events at the same time FIFO
+	fn  func()
+}
+
+// eventQueue is a min-heap of events ordered by time.
+type eventQueue struct {
+	events []event
+	seq    uint64
+}
+
+func (q *eventQueue) Len() int { return len(q.events) }
+
+func (q *eventQueue) Less(i, j int) bool {
+	if q.events[i].at.Equal(q.events[j].at) {
+		return q.events[i].seq < q.events[j].seq
+	}
+	return q.events[i].at.Before(q.events[j].at)
+}
+
+func (q *eventQueue) Swap(i, j int) { q.events[i], q.events[j] = q.events[j], q.events[i] }
+
+func (q *eventQueue) Push(x any) { q.events = append(q.events, x.(event)) }
+
+func (q *eventQueue) Pop() any {
+	last := len(q.events) - 1
+	e := q.events[last]
+	q.events[last] = event{}
+	q.events = q.events[:last]
+	return e
+}
+
+// schedule adds an action at the given time.
+func (q *eventQueue) schedule(at time.Time, fn func()) {
+	q.seq++
+	heap.Push(q, event{at: at, seq: q.seq, fn: fn})
+}
+
+// next removes and returns the earliest event if it is not after the horizon.
+func (q *eventQueue) next(horizon time.Time) (event, bool) {
+	if len(q.events) == 0 || q.events[0].at.After(horizon) {
+		return event{}, false
+	}
+	return heap.Pop(q).(event), true
+}
@@ -0,0 +1,127 @@
+package network
+
+import (
+	"time"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// Flow is a stream of packets from a source to a target node.
+// With a positive Rate the network sends packets at a constant bit rate between Start and Stop,
+// further packets can be sent with Network.Send.
+type Flow struct {
+	ID         string
+	Source     types.Node
+	Target     types.Node
+	PacketSize int           // bytes per packet of the constant bit rate
+	Rate       float64       // constant bit rate in bits per second, 0 to only send packets explicitly
+	Start      time.Time     // first packet of the constant bit rate
+	Stop       time.Time     // end of the constant bit rate, zero to send until the simulation ends
+	Payload    types.Payload // passed to the routers instead of the packet if set, e.g. with QoS requirements
+
+	stats     FlowStats
+	sumDelay  time.Duration
+	sumJitter time.Duration
+	lastDelay time.Duration
+}
+
+// FlowStats are the end-to-end statistics of a flow.
+type FlowStats struct {
+	Sent           int
+	Delivered      int
+	Dropped        map[string]int // dropped packets by reason
+	DeliveredBytes int64
+	MinDelay       time.Duration
+	MaxDelay       time.Duration
+	MeanDelay      time.Duration
+	Jitter         time.Duration // mean absolute difference between the delays of consecutive packets
+	FirstSent      time.Time
+	LastDelivered  time.Time
+}
+
+// Stats returns the statistics of the flow so far.
+func (f *Flow) Stats() FlowStats {
+	stats := f.stats
+	stats.Dropped = make(map[string]int, len(f.stats.Dropped))
+	for reason, count := range f.stats.Dropped {
+		stats.Dropped[reason] = count
+	}
+	if stats.Delivered > 0 {
+		stats.MeanDelay = f.sumDelay / time.Duration(stats.Delivered)
+	}
+	if stats.Delivered > 1 {
+		stats.Jitter = f.sumJitter / time.Duration(stats.Delivered-1)
+	}
+	return stats
+}
+
+// DroppedTotal returns the number of dropped packets.
+func (s FlowStats) DroppedTotal() int {
+	total := 0
+	for _, count := range s.Dropped {
+		total += count
+	}
+	return total
+}
+
+// InFlight returns the number of packets neither delivered nor dropped yet.
+func (s FlowStats) InFlight() int {
+	return s.Sent - s.Delivered - s.DroppedTotal()
+}
+
+// Loss returns the share of dropped packets among the packets which left the network, e.g. 0.01 for 1%.
+func (s FlowStats) Loss() float64 {
+	done := s.Delivered + s.DroppedTotal()
+	if done == 0 {
+		return 0
+	}
+	return float64(s.DroppedTotal()) / float64(done)
+}
+
+// Throughput returns the delivered bits per second between the first packet sent and the last one delivered.
+func (s FlowStats) Throughput() float64 {
+	duration := s.LastDelivered.Sub(s.FirstSent).Seconds()
+	if duration <= 0 {
+		return 0
+	}
+	return float64(s.DeliveredBytes*8) / duration
+}
+
+// sent records a packet leaving the source.
+func (f *Flow) sent(p *Packet) {
+	if f.stats.Sent == 0 {
+		f.stats.FirstSent = p.Created
+	}
+	f.stats.Sent++
+}
+
+// delivered records a packet arriving at the target.
+func (f *Flow) delivered(p *Packet, at time.Time) {
+	delay := at.Sub(p.Created)
+	if f.stats.Delivered == 0 || delay < f.stats.MinDelay {
+		f.stats.MinDelay = delay
+	}
+	if delay > f.stats.MaxDelay {
+		f.stats.MaxDelay = delay
+	}
+	if f.stats.Delivered > 0 {
+		diff := delay - f.lastDelay
+		if diff < 0 {
+			diff = -diff
+		}
+		f.sumJitter += diff
+	}
+	f.lastDelay = delay
+	f.sumDelay += delay
+	f.stats.Delivered++
+	f.stats.DeliveredBytes += int64(p.Size)
+	f.stats.LastDelivered = at
+}
+
+// dropped records a packet lost in the network.
+func (f *Flow) dropped(reason string) {
+	if f.stats.Dropped == nil {
+		f.stats.Dropped = make(map[string]int)
+	}
+	f.stats.Dropped[reason]++
+}
@@ -0,0 +1,318 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/keniack/stardustGo/internal/routing"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var _ types.SimulationPlugin = (*Network)(nil)
+
+// Defaults of the packet network
+const (
+	DefaultBufferSize = 1_000_000 // bytes queued per link direction
+	DefaultMaxHops    = 64
+)
+
+// Reasons of dropped packets
+const (
+	DropNoRoute        = "no-route"        // the router found no route to the target
+	DropBufferOverflow = "buffer-overflow" // the queue of the outgoing link was full
+	DropLinkDown       = "link-down"       // the link was torn down during the transmission
+	DropHopLimit       = "hop-limit"       // the packet exceeded the maximum hop count, e.g. in a routing loop
+)
+
+// linkQueue is the FIFO output queue of a link in one direction.
+type linkQueue struct {
+	busyUntil time.Time // end of the last scheduled transmission
+	bytes     int       // bytes waiting or being transmitted
+}
+
+// queueKey identifies the direction of a link by its sending node.
+type queueKey struct {
+	link types.Link
+	from types.Node
+}
+
+// Network is a packet-level discrete-event simulation on top of the simulated topology.
+// Packets are queued per link direction, transmitted with the link bandwidth and
+// propagated with the link latency, while the node routers choose the next hops.
+//
+// Events are processed on the topology of the simulation at the time they are processed:
+// RunUntil(t) processes all events up to t on the current topology, so drive the network
+// between simulation steps, or register it as simulation plugin to run each step's events
+// on the topology after the step.
+type Network struct {
+	bufferSize int
+	maxHops    int
+
+	now      time.Time
+	events   eventQueue
+	queues   map[queueKey]*linkQueue
+	flows    []*Flow
+	flowIDs  map[string]*Flow
+	packetID uint64
+}
+
+// NewNetwork creates a packet network with the given buffer size in bytes per link direction
+// and the maximum hops of a packet. Values <= 0 use the defaults.
+func NewNetwork(bufferSize, maxHops int) *Network {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	if maxHops <= 0 {
+		maxHops = DefaultMaxHops
+	}
+	return &Network{
+		bufferSize: bufferSize,
+		maxHops:    maxHops,
+		queues:     make(map[queueKey]*linkQueue),
+		flowIDs:    make(map[string]*Flow),
+	}
+}
+
+func (n *Network) Name() string {
+	return "PacketNetwork"
+}
+
+// PostSimulationStep processes all events up to the simulation time
+func (n *Network) PostSimulationStep(simulation types.SimulationController) error {
+	n.RunUntil(simulation.GetSimulationTime())
+	return nil
+}
+
+// Now returns the time of the network, i.e. the time all events have been processed to.
+func (n *Network) Now() time.Time {
+	return n.now
+}
+
+// Flows returns the flows added to the network.
+func (n *Network) Flows() []*Flow {
+	return n.flows
+}
+
+// Flow returns the flow with the given ID.
+func (n *Network) Flow(id string) (*Flow, bool) {
+	f, ok := n.flowIDs[id]
+	return f, ok
+}
+
+// AddFlow adds a flow and schedules its constant bit rate, if any.
+func (n *Network) AddFlow(f *Flow) error {
+	if f.ID == "" {
+		return errors.New("flow requires an ID")
+	}
+	if _, exists := n.flowIDs[f.ID]; exists {
+		return fmt.Errorf("duplicate flow: %s", f.ID)
+	}
+	if f.Source == nil || f.Target == nil {
+		return fmt.Errorf("flow %s requires a source and a target", f.ID)
+	}
+	if f.Rate < 0 {
+		return fmt.Errorf("rate of flow %s must not be negative", f.ID)
+	}
+	if f.Rate > 0 && f.PacketSize <= 0 {
+		return fmt.Errorf("flow %s with a rate requires a packet size", f.ID)
+	}
+
+	n.flows = append(n.flows, f)
+	n.flowIDs[f.ID] = f
+	if f.Rate > 0 {
+		start := f.Start
+		if start.Before(n.now) {
+			start = n.now
+		}
+		n.events.schedule(start, func() { n.emit(f, start) })
+	}
+	return nil
+}
+
+// Send schedules a single packet of the flow with the given size at the given time,
+// or immediately if the time has already passed.
+func (n *Network) Send(f *Flow, size int, at time.Time) error {
+	if n.flowIDs[f.ID] != f {
+		return fmt.Errorf("flow %s has not been added to the network", f.ID)
+	}
+	if size <= 0 {
+		return fmt.Errorf("packet size must be positive, got %d", size)
+	}
+	if at.Before(n.now) {
+		at = n.now
+	}
+	n.events.schedule(at, func() { n.send(f, size) })
+	return nil
+}
+
+// RunUntil processes all events up to the given time.
+func (n *Network) RunUntil(t time.Time) {
+	for {
+		e, ok := n.events.next(t)
+		if !ok {
+			break
+		}
+		if e.at.After(n.now) {
+			n.now = e.at
+		}
+		e.fn()
+	}
+	if t.After(n.now) {
+		n.now = t
+	}
+}
+
+// emit sends a packet of a constant bit rate flow and schedules the next one.
+func (n *Network) emit(f *Flow, at time.Time) {
+	n.send(f, f.PacketSize)
+
+	interval := time.Duration(float64(f.PacketSize*8) / f.Rate * float64(time.Second))
+	next := at.Add(max(interval, time.Nanosecond))
+	if f.Stop.IsZero() || next.Before(f.Stop) {
+		n.events.schedule(next, func() { n.emit(f, next) })
+	}
+}
+
+// send creates a packet at the flow source.
+func (n *Network) send(f *Flow, size int) {
+	n.packetID++
+	p := &Packet{
+		ID:      n.packetID,
+		Flow:    f,
+		Size:    size,
+		Created: n.now,
+	}
+	f.sent(p)
+	n.forward(p, f.Source)
+}
+
+// forward passes the packet on at the given node.
+func (n *Network) forward(p *Packet, at types.Node) {
+	if at == p.Flow.Target {
+		p.Flow.delivered(p, n.now)
+		return
+	}
+	if p.Hops >= n.maxHops {
+		p.Flow.dropped(DropHopLimit)
+		return
+	}
+
+	link, wait, err := n.nextHop(p, at)
+	if err != nil || link == nil {
+		p.Flow.dropped(DropNoRoute)
+		return
+	}
+	if wait.After(n.now) {
+		// Store the packet until the contact starts and decide again then
+		n.events.schedule(wait, func() { n.forward(p, at) })
+		return
+	}
+	n.enqueue(p, link, at)
+}
+
+// nextHop returns the outgoing link of the packet at the node.
+// Store-and-forward routes return the start of the contact if it lies in the future.
+func (n *Network) nextHop(p *Packet, at types.Node) (types.Link, time.Time, error) {
+	// Follow the remaining source route as long as its links are established
+	if len(p.route) > 0 {
+		link := p.route[0]
+		if isEstablished(at, link) {
+			p.route = p.route[1:]
+			return link, time.Time{}, nil
+		}
+		p.route = nil
+	}
+
+	router := at.GetRouter()
+	if timed, ok := router.(types.TimedRouter); ok {
+		timed.AdvanceTo(n.now)
+	}
+	target := p.Flow.Target
+	payload := p.routingPayload()
+	if fwd, ok := router.(types.ForwardingRouter); ok {
+		link, err := fwd.NextHop(target, payload)
+		return link, time.Time{}, err
+	}
+
+	result, err := router.RouteToNode(target, payload)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	switch r := result.(type) {
+	case *routing.PathRouteResult:
+		links := r.Path().Links
+		if len(links) == 0 {
+			return nil, time.Time{}, nil
+		}
+		p.route = links[1:]
+		return links[0], time.Time{}, nil
+	case *routing.ContactRouteResult:
+		contacts := r.Contacts()
+		if len(contacts) == 0 {
+			return nil, time.Time{}, nil
+		}
+		return contacts[0].Link, contacts[0].Start, nil
+	}
+	if !result.Reachable() {
+		return nil, time.Time{}, nil
+	}
+	return nil, time.Time{}, fmt.Errorf("router of %s reports no next hop", at.GetName())
+}
+
+// enqueue appends the packet to the output queue of the link and schedules its transmission.
+func (n *Network) enqueue(p *Packet, link types.Link, from types.Node) {
+	key := queueKey{link: link, from: from}
+	q, ok := n.queues[key]
+	if !ok {
+		q = &linkQueue{}
+		n.queues[key] = q
+	}
+	if q.bytes+p.Size > n.bufferSize {
+		p.Flow.dropped(DropBufferOverflow)
+		return
+	}
+	q.bytes += p.Size
+
+	start := q.busyUntil
+	if start.Before(n.now) {
+		start = n.now
+	}
+	q.busyUntil = start.Add(transmissionDelay(p.Size, link.Bandwidth()))
+	n.events.schedule(q.busyUntil, func() { n.transmitted(p, link, from, key, q) })
+}
+
+// transmitted propagates the packet to the other node once it is fully sent.
+func (n *Network) transmitted(p *Packet, link types.Link, from types.Node, key queueKey, q *linkQueue) {
+	q.bytes -= p.Size
+	if q.bytes == 0 {
+		delete(n.queues, key)
+	}
+	if !isEstablished(from, link) {
+		p.Flow.dropped(DropLinkDown)
+		return
+	}
+
+	to := link.GetOther(from)
+	p.Hops++
+	propagation := time.Duration(link.Latency() * float64(time.Millisecond))
+	n.events.schedule(n.now.Add(propagation), func() { n.forward(p, to) })
+}
+
+// transmissionDelay returns the time to put the bytes on a link with the given bandwidth in bits per second.
+func transmissionDelay(size int, bandwidth float64) time.Duration {
+	if bandwidth <= 0 {
+		return 0
+	}
+	return time.Duration(float64(size*8) / bandwidth * float64(time.Second))
+}
+
+// isEstablished reports if the link is currently established at the node.
+func isEstablished(node types.Node, link types.Link) bool {
+	for _, l := range node.GetLinkNodeProtocol().Established() {
+		if l == link {
+			return true
+		}
+	}
+	return false
+}
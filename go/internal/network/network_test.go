@@ -0,0 +1,133 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keniack/stardustGo/internal/routing"
+	"github.com/keniack/stardustGo/internal/simtest"
+)
+
+var start = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// line connects A-B-C with links of 1 ms and 8 Mbit/s, so a packet of 1000 bytes takes 2 ms per hop.
+// D has no links.
+func line(t *testing.T) (nodes []*simtest.Node, links []*simtest.Link) {
+	t.Helper()
+	engine := routing.NewRoutingEngine(nil)
+	adverts := routing.NewServiceAdvertisementPlane()
+	for _, name := range []string{"A", "B", "C", "D"} {
+		n := simtest.NewNode(name)
+		router, err := routing.NewQosRouter(engine, adverts, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := n.Mount(router, nil); err != nil {
+			t.Fatal(err)
+		}
+		nodes = append(nodes, n)
+	}
+	links = []*simtest.Link{
+		simtest.Connect(nodes[0], nodes[1], 1, 8e6),
+		simtest.Connect(nodes[1], nodes[2], 1, 8e6),
+	}
+	return nodes, links
+}
+
+func TestNetwork(t *testing.T) {
+	tests := []struct {
+		name       string
+		bufferSize int
+		target     int
+		packets    int
+		linkDown   bool // the second link goes down during the transmission of the packets
+		delivered  int
+		dropped    map[string]int
+	}{
+		{"delivered", 0, 2, 2, false, 2, map[string]int{}},
+		{"no route", 0, 3, 1, false, 0, map[string]int{DropNoRoute: 1}},
+		{"buffer overflow", 1500, 2, 3, false, 1, map[string]int{DropBufferOverflow: 2}},
+		{"link down", 0, 2, 1, true, 0, map[string]int{DropLinkDown: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, links := line(t)
+			n := NewNetwork(tt.bufferSize, 0)
+			f := &Flow{ID: "flow", Source: nodes[0], Target: nodes[tt.target]}
+			if err := n.AddFlow(f); err != nil {
+				t.Fatal(err)
+			}
+			for range tt.packets {
+				if err := n.Send(f, 1000, start); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			n.RunUntil(start.Add(2500 * time.Microsecond))
+			links[1].Down = tt.linkDown
+			n.RunUntil(start.Add(time.Second))
+
+			stats := f.Stats()
+			if stats.Sent != tt.packets || stats.Delivered != tt.delivered {
+				t.Errorf("sent %d and delivered %d packets, want %d and %d", stats.Sent, stats.Delivered, tt.packets, tt.delivered)
+			}
+			for _, reason := range []string{DropNoRoute, DropBufferOverflow, DropLinkDown, DropHopLimit} {
+				if stats.Dropped[reason] != tt.dropped[reason] {
+					t.Errorf("dropped %d packets for %s, want %d", stats.Dropped[reason], reason, tt.dropped[reason])
+				}
+			}
+			if stats.InFlight() != 0 {
+				t.Errorf("%d packets still in flight", stats.InFlight())
+			}
+		})
+	}
+}
+
+func TestNetworkDelay(t *testing.T) {
+	nodes, _ := line(t)
+	n := NewNetwork(0, 0)
+	f := &Flow{ID: "flow", Source: nodes[0], Target: nodes[2]}
+	if err := n.AddFlow(f); err != nil {
+		t.Fatal(err)
+	}
+	// The second packet waits for the transmission of the first one on the first link only
+	for range 2 {
+		if err := n.Send(f, 1000, start); err != nil {
+			t.Fatal(err)
+		}
+	}
+	n.RunUntil(start.Add(time.Second))
+
+	stats := f.Stats()
+	if stats.MinDelay != 4*time.Millisecond || stats.MaxDelay != 5*time.Millisecond {
+		t.Errorf("delays between %v and %v, want 4ms and 5ms", stats.MinDelay, stats.MaxDelay)
+	}
+	if stats.Jitter != time.Millisecond {
+		t.Errorf("jitter = %v, want 1ms", stats.Jitter)
+	}
+}
+
+func TestAddFlowErrors(t *testing.T) {
+	nodes, _ := line(t)
+	tests := []struct {
+		name string
+		flow *Flow
+	}{
+		{"no ID", &Flow{Source: nodes[0], Target: nodes[1]}},
+		{"duplicate", &Flow{ID: "flow", Source: nodes[0], Target: nodes[1]}},
+		{"no target", &Flow{ID: "no target", Source: nodes[0]}},
+		{"negative rate", &Flow{ID: "negative", Source: nodes[0], Target: nodes[1], Rate: -1}},
+		{"rate without packet size", &Flow{ID: "rate", Source: nodes[0], Target: nodes[1], Rate: 1000}},
+	}
+	n := NewNetwork(0, 0)
+	if err := n.AddFlow(&Flow{ID: "flow", Source: nodes[0], Target: nodes[1]}); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := n.AddFlow(tt.flow); err == nil {
+				t.Error("flow accepted")
+			}
+		})
+	}
+}
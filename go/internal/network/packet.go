@@ -0,0 +1,33 @@
+package network
+
+import (
+	"time"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var _ types.Flow = (*Packet)(nil)
+
+// Packet is a unit of data sent through the simulated network.
+type Packet struct {
+	ID      uint64
+	Flow    *Flow
+	Size    int       // size in bytes
+	Created time.Time // time the packet was sent at the source
+	Hops    int       // links traversed so far
+
+	route []types.Link // remaining source route if the router calculated a whole path
+}
+
+// FlowID returns the ID of the packet's flow, so multipath routers keep the flow on one path
+func (p *Packet) FlowID() string {
+	return p.Flow.ID
+}
+
+// routingPayload returns the payload passed to the routers: the flow's payload if set, the packet otherwise.
+func (p *Packet) routingPayload() types.Payload {
+	if p.Flow.Payload != nil {
+		return p.Flow.Payload
+	}
+	return p
+}
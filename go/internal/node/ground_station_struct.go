@@ -10,6 +10,7 @@ import (
 )
 
 var _ types.GroundStation = (*GroundStationStruct)(nil)
+var _ types.PositionPredictor = (*GroundStationStruct)(nil)
 
 // GroundStationStruct represents an Earth-based node that links to satellites
 // It updates its position over time and tracks the nearest satellites
@@ -20,6 +21,8 @@ type GroundStationStruct struct {
 	Longitude                   float64
 	SimulationStartTime         time.Time
 	GroundSatelliteLinkProtocol types.GroundSatelliteLinkProtocol
+	Tags                        []string
+	Weight                      float64
 
 	mu sync.Mutex
 }
@@ -39,7 +42,8 @@ func NewGroundStation(name string, lat float64, lon float64, protocol types.Grou
 	}
 	protocol.Mount(gs)
 	router.Mount(gs)
-	gs.updatePositionFromElapsed(0)
+	computing.Mount(gs)
+	gs.Position = gs.positionFromElapsed(0)
 	return gs
 }
 
@@ -48,12 +52,16 @@ func (gs *GroundStationStruct) UpdatePosition(simTime time.Time) {
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
 
-	timeElapsed := simTime.Sub(gs.SimulationStartTime).Seconds()
-	gs.updatePositionFromElapsed(timeElapsed)
+	gs.Position = gs.PositionAt(simTime)
 }
 
-// updatePositionFromElapsed calculates Earth-centered coordinates using geodetic formula
-func (gs *GroundStationStruct) updatePositionFromElapsed(timeElapsed float64) {
+// PositionAt returns the position of the ground station at the simulation time without moving it
+func (gs *GroundStationStruct) PositionAt(simTime time.Time) types.Vector {
+	return gs.positionFromElapsed(simTime.Sub(gs.SimulationStartTime).Seconds())
+}
+
+// positionFromElapsed calculates Earth-centered coordinates using geodetic formula
+func (gs *GroundStationStruct) positionFromElapsed(timeElapsed float64) types.Vector {
 	const (
 		a             = 6378137.0       // semi-major axis in meters
 		b             = 6356752.314245  // semi-minor axis in meters
@@ -76,13 +84,23 @@ func (gs *GroundStationStruct) updatePositionFromElapsed(timeElapsed float64) {
 	yRot := x*math.Sin(theta) + y*math.Cos(theta)
 	zRot := z
 
-	gs.Position = types.Vector{X: xRot, Y: yRot, Z: zRot}
+	return types.Vector{X: xRot, Y: yRot, Z: zRot}
 }
 
 func (gs *GroundStationStruct) GetLinkNodeProtocol() types.LinkNodeProtocol {
 	return gs.GroundSatelliteLinkProtocol
 }
 
+// GetTags returns the tags of the ground station
+func (gs *GroundStationStruct) GetTags() []string {
+	return gs.Tags
+}
+
+// GetWeight returns the weight of the ground station in traffic models
+func (gs *GroundStationStruct) GetWeight() float64 {
+	return gs.Weight
+}
+
 // FindNearestSatellite returns the closest satellite in a given list
 func (gs *GroundStationStruct) FindNearestSatellite(sats []types.Satellite) (types.Satellite, error) {
 	if len(sats) == 0 {
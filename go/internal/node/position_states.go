@@ -0,0 +1,47 @@
+package node
+
+import (
+	"sort"
+	"time"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// positionStates are the precomputed positions of a node ordered by simulation time.
+type positionStates struct {
+	times     []time.Time
+	positions []types.Vector
+}
+
+// add stores the position of the node at the simulation time, replacing a position stored for the same time.
+func (p *positionStates) add(simTime time.Time, position types.Vector) {
+	i := sort.Search(len(p.times), func(i int) bool { return !p.times[i].Before(simTime) })
+	if i < len(p.times) && p.times[i].Equal(simTime) {
+		p.positions[i] = position
+		return
+	}
+	p.times = append(p.times[:i], append([]time.Time{simTime}, p.times[i:]...)...)
+	p.positions = append(p.positions[:i], append([]types.Vector{position}, p.positions[i:]...)...)
+}
+
+// at returns the position at the simulation time, interpolated linearly between the two surrounding states.
+// Times before the first or after the last state take the position of that state.
+func (p *positionStates) at(simTime time.Time) types.Vector {
+	if len(p.times) == 0 {
+		return types.Vector{}
+	}
+	i := sort.Search(len(p.times), func(i int) bool { return !p.times[i].Before(simTime) })
+	switch {
+	case i == len(p.times):
+		return p.positions[i-1]
+	case i == 0 || p.times[i].Equal(simTime):
+		return p.positions[i]
+	}
+	f := simTime.Sub(p.times[i-1]).Seconds() / p.times[i].Sub(p.times[i-1]).Seconds()
+	from, to := p.positions[i-1], p.positions[i]
+	return types.Vector{
+		X: from.X + (to.X-from.X)*f,
+		Y: from.Y + (to.Y-from.Y)*f,
+		Z: from.Z + (to.Z-from.Z)*f,
+	}
+}
@@ -0,0 +1,43 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+func TestPositionStates(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(s float64) time.Time { return start.Add(time.Duration(s * float64(time.Second))) }
+
+	var states positionStates
+	if got := states.at(start); got != (types.Vector{}) {
+		t.Errorf("position without states = %v", got)
+	}
+	// Added out of order, the state at 10 s is replaced
+	states.add(at(10), types.Vector{X: 5})
+	states.add(at(0), types.Vector{X: 0, Y: 10})
+	states.add(at(10), types.Vector{X: 10})
+	states.add(at(20), types.Vector{X: 10, Z: 10})
+
+	tests := []struct {
+		at   float64
+		want types.Vector
+	}{
+		{-5, types.Vector{Y: 10}},
+		{0, types.Vector{Y: 10}},
+		{2.5, types.Vector{X: 2.5, Y: 7.5}},
+		{10, types.Vector{X: 10}},
+		{15, types.Vector{X: 10, Z: 5}},
+		{30, types.Vector{X: 10, Z: 10}},
+	}
+	for _, tt := range tests {
+		if got := states.at(at(tt.at)); got != tt.want {
+			t.Errorf("position at %v s = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+	if len(states.times) != 3 {
+		t.Errorf("%d states, want 3", len(states.times))
+	}
+}
@@ -8,6 +8,7 @@ import (
 )
 
 var _ types.Satellite = (*SatelliteStruct)(nil) // Ensure SatelliteStruct implements Satellite
+var _ types.PositionPredictor = (*SatelliteStruct)(nil)
 
 // SatelliteStruct represents a single satellite node in the simulation.
 type SatelliteStruct struct {
@@ -52,12 +53,18 @@ func NewSatellite(name string, inclination, raan, ecc, argPerigee, meanAnomaly,
 
 	isl.Mount(s)
 	router.Mount(s)
+	computing.Mount(s)
 	s.UpdatePosition(simTime)
 	return s
 }
 
 // UpdatePosition calculates the satellite's position in the ECI frame based on orbital elements and simulation time
 func (s *SatelliteStruct) UpdatePosition(simTime time.Time) {
+	s.Position = s.PositionAt(simTime)
+}
+
+// PositionAt propagates the orbit of the satellite to the simulation time without moving it
+func (s *SatelliteStruct) PositionAt(simTime time.Time) types.Vector {
 	deltaT := simTime.Sub(s.epoch).Seconds() // Time since epoch in seconds
 	meanMotionRadPerSec := s.meanMotion * 2.0 * math.Pi / (24 * 3600)
 	meanAnomalyCurrent := s.meanAnomaly + meanMotionRadPerSec*deltaT
@@ -71,7 +78,7 @@ func (s *SatelliteStruct) UpdatePosition(simTime time.Time) {
 	yp := distance * math.Sin(trueAnomaly)
 	zp := 0.0
 
-	s.Position = applyOrbitalTransformations(xp, yp, zp, s.inclinationRad, s.argumentOfPerigeeRad, s.rightAscensionRad)
+	return applyOrbitalTransformations(xp, yp, zp, s.inclinationRad, s.argumentOfPerigeeRad, s.rightAscensionRad)
 }
 
 func (s *SatelliteStruct) GetLinkNodeProtocol() types.LinkNodeProtocol {
@@ -7,19 +7,21 @@ import (
 )
 
 var _ types.GroundStation = (*PrecomputedGroundStation)(nil)
+var _ types.PositionPredictor = (*PrecomputedGroundStation)(nil)
 
 type PrecomputedGroundStation struct {
 	BaseNode
 
 	LinkProtocol types.LinkNodeProtocol
-	positions    map[time.Time]types.Vector
+	Tags         []string
+	Weight       float64
+	positions    positionStates
 }
 
 func NewSimulatedGroundStation(name string, router types.Router, computing types.Computing, linkProtocol types.LinkNodeProtocol) *PrecomputedGroundStation {
 	groundStation := &PrecomputedGroundStation{
 		BaseNode:     BaseNode{Name: name, Router: router, Computing: computing},
 		LinkProtocol: linkProtocol,
-		positions:    make(map[time.Time]types.Vector),
 	}
 
 	router.Mount(groundStation)
@@ -29,13 +31,26 @@ func NewSimulatedGroundStation(name string, router types.Router, computing types
 }
 
 func (s *PrecomputedGroundStation) UpdatePosition(time time.Time) {
-	s.Position = s.positions[time]
+	s.Position = s.positions.at(time)
+}
+
+// PositionAt interpolates the position at the simulation time from the precomputed states
+func (s *PrecomputedGroundStation) PositionAt(simTime time.Time) types.Vector {
+	return s.positions.at(simTime)
 }
 
 func (s *PrecomputedGroundStation) GetLinkNodeProtocol() types.LinkNodeProtocol {
 	return s.LinkProtocol
 }
 
+func (s *PrecomputedGroundStation) GetTags() []string {
+	return s.Tags
+}
+
+func (s *PrecomputedGroundStation) GetWeight() float64 {
+	return s.Weight
+}
+
 func (s *PrecomputedGroundStation) AddPositionState(time time.Time, position types.Vector) {
-	s.positions[time] = position
+	s.positions.add(time, position)
 }
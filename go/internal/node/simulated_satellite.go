@@ -7,19 +7,19 @@ import (
 )
 
 var _ types.Satellite = (*PrecomputedSatellite)(nil)
+var _ types.PositionPredictor = (*PrecomputedSatellite)(nil)
 
 type PrecomputedSatellite struct {
 	BaseNode
 
 	ISLProtocol types.InterSatelliteLinkProtocol
-	positions   map[time.Time]types.Vector
+	positions   positionStates
 }
 
 func NewSimulatedSatellite(name string, router types.Router, computing types.Computing, isl types.InterSatelliteLinkProtocol) *PrecomputedSatellite {
 	satellite := &PrecomputedSatellite{
 		BaseNode:    BaseNode{Name: name, Router: router, Computing: computing},
 		ISLProtocol: isl,
-		positions:   make(map[time.Time]types.Vector),
 	}
 
 	isl.Mount(satellite)
@@ -29,7 +29,12 @@ func NewSimulatedSatellite(name string, router types.Router, computing types.Com
 }
 
 func (s *PrecomputedSatellite) UpdatePosition(simTime time.Time) {
-	s.Position = s.positions[simTime]
+	s.Position = s.positions.at(simTime)
+}
+
+// PositionAt interpolates the position at the simulation time from the precomputed states
+func (s *PrecomputedSatellite) PositionAt(simTime time.Time) types.Vector {
+	return s.positions.at(simTime)
 }
 
 func (s *PrecomputedSatellite) GetLinkNodeProtocol() types.LinkNodeProtocol {
@@ -41,5 +46,5 @@ func (s *PrecomputedSatellite) GetISLProtocol() types.InterSatelliteLinkProtocol
 }
 
 func (s *PrecomputedSatellite) AddPositionState(time time.Time, position types.Vector) {
-	s.positions[time] = position
+	s.positions.add(time, position)
 }
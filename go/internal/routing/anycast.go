@@ -0,0 +1,244 @@
+package routing
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// Node types of anycast group selectors
+const (
+	AnycastGroundStations = "ground"
+	AnycastSatellites     = "satellite"
+)
+
+// AnycastRoute is the route from a node to one member of an anycast group.
+type AnycastRoute struct {
+	Member types.Node
+	Cost   float64 // link cost minimized by the routing engine
+	Path   types.Path
+}
+
+// Latency returns the latency to the member in ms.
+func (r AnycastRoute) Latency() float64 {
+	return r.Path.Latency
+}
+
+// AnycastGroups holds named groups of nodes, e.g. all Internet gateways, and routes to their best member.
+// Members are selected by the configured criteria or join explicitly. Routes minimize the link cost of the
+// shared routing engine, so the best member is the closest one in the network, not in straight-line distance.
+type AnycastGroups struct {
+	engine *RoutingEngine
+
+	mu       sync.Mutex
+	groups   map[string]*anycastGroup
+	snapshot *TopologySnapshot // snapshot the members were resolved on
+	members  map[string][]int  // dense node IDs of the members by group name
+}
+
+// anycastGroup selects its members from the registered nodes.
+type anycastGroup struct {
+	config   configs.AnycastGroupConfig
+	name     *regexp.Regexp // nil if any name matches
+	selects  bool           // false if the group only has explicit members
+	explicit map[types.Node]bool
+}
+
+// NewAnycastGroups creates the configured anycast groups on the given routing engine.
+func NewAnycastGroups(engine *RoutingEngine, groups []configs.AnycastGroupConfig) (*AnycastGroups, error) {
+	a := &AnycastGroups{
+		engine: engine,
+		groups: make(map[string]*anycastGroup),
+	}
+	for _, cfg := range groups {
+		if cfg.Name == "" {
+			return nil, fmt.Errorf("anycast group without name")
+		}
+		if _, ok := a.groups[cfg.Name]; ok {
+			return nil, fmt.Errorf("duplicate anycast group: %s", cfg.Name)
+		}
+		switch strings.ToLower(cfg.NodeType) {
+		case "", AnycastGroundStations, AnycastSatellites:
+		default:
+			return nil, fmt.Errorf("unknown node type of anycast group %s: %s", cfg.Name, cfg.NodeType)
+		}
+
+		g := &anycastGroup{config: cfg, selects: true, explicit: make(map[types.Node]bool)}
+		if cfg.NodeName != "" {
+			name, err := regexp.Compile(cfg.NodeName)
+			if err != nil {
+				return nil, fmt.Errorf("invalid node name of anycast group %s: %w", cfg.Name, err)
+			}
+			g.name = name
+		}
+		a.groups[cfg.Name] = g
+	}
+	return a, nil
+}
+
+// Join adds the node to the group, creating the group if it does not exist.
+func (a *AnycastGroups) Join(group string, n types.Node) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	g, ok := a.groups[group]
+	if !ok {
+		g = &anycastGroup{config: configs.AnycastGroupConfig{Name: group}, explicit: make(map[types.Node]bool)}
+		a.groups[group] = g
+	}
+	g.explicit[n] = true
+	a.snapshot = nil
+}
+
+// Leave removes a node which joined the group explicitly.
+func (a *AnycastGroups) Leave(group string, n types.Node) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if g, ok := a.groups[group]; ok {
+		delete(g.explicit, n)
+		a.snapshot = nil
+	}
+}
+
+// Groups returns the sorted names of all groups.
+func (a *AnycastGroups) Groups() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	names := make([]string, 0, len(a.groups))
+	for name := range a.groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Members returns the members of the group among the nodes of the current topology.
+func (a *AnycastGroups) Members(group string) ([]types.Node, error) {
+	s := a.engine.Snapshot()
+	ids, err := a.resolve(s, group)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]types.Node, len(ids))
+	for i, id := range ids {
+		members[i] = s.Node(id)
+	}
+	return members, nil
+}
+
+// Route returns the routes from the node to the k best reachable members of the group, best first.
+// A non-positive k returns the routes to all reachable members. A member of the group routes to itself.
+func (a *AnycastGroups) Route(from types.Node, group string, k int) ([]AnycastRoute, error) {
+	tree, err := a.engine.TreeFrom(from)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := a.resolve(tree.snapshot, group)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !math.IsInf(tree.dist[id], 1) {
+			candidates = append(candidates, id)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		ci, cj := candidates[i], candidates[j]
+		if tree.dist[ci] != tree.dist[cj] {
+			return tree.dist[ci] < tree.dist[cj]
+		}
+		return tree.latency[ci] < tree.latency[cj]
+	})
+	if k > 0 && len(candidates) > k {
+		candidates = candidates[:k]
+	}
+
+	routes := make([]AnycastRoute, len(candidates))
+	for i, id := range candidates {
+		member := tree.snapshot.Node(id)
+		nodes, links := tree.PathTo(member)
+		routes[i] = AnycastRoute{
+			Member: member,
+			Cost:   tree.dist[id],
+			Path:   types.Path{Nodes: nodes, Links: links, Latency: tree.latency[id]},
+		}
+	}
+	return routes, nil
+}
+
+// BestMember returns the route to the best reachable member of the group, false if no member is reachable.
+func (a *AnycastGroups) BestMember(from types.Node, group string) (AnycastRoute, bool, error) {
+	routes, err := a.Route(from, group, 1)
+	if err != nil || len(routes) == 0 {
+		return AnycastRoute{}, false, err
+	}
+	return routes[0], true, nil
+}
+
+// resolve returns the dense IDs of the members of the group in the snapshot.
+func (a *AnycastGroups) resolve(s *TopologySnapshot, group string) ([]int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	g, ok := a.groups[group]
+	if !ok {
+		return nil, fmt.Errorf("unknown anycast group: %s", group)
+	}
+	if a.snapshot != s {
+		a.snapshot = s
+		a.members = make(map[string][]int)
+	}
+	if ids, ok := a.members[group]; ok {
+		return ids, nil
+	}
+
+	var ids []int
+	for id, n := range s.Nodes() {
+		if g.explicit[n] || (g.selects && g.matches(n)) {
+			ids = append(ids, id)
+		}
+	}
+	a.members[group] = ids
+	return ids, nil
+}
+
+// matches checks the node against all criteria of the group.
+func (g *anycastGroup) matches(n types.Node) bool {
+	cfg := g.config
+	gs, isGround := n.(types.GroundStation)
+	switch strings.ToLower(cfg.NodeType) {
+	case AnycastGroundStations:
+		if !isGround {
+			return false
+		}
+	case AnycastSatellites:
+		if isGround {
+			return false
+		}
+	}
+	if cfg.ComputingType != types.None && n.GetComputing().GetComputingType() != cfg.ComputingType {
+		return false
+	}
+	if cfg.Tag != "" && (!isGround || !hasTag(gs, cfg.Tag)) {
+		return false
+	}
+	if g.name != nil && !g.name.MatchString(n.GetName()) {
+		return false
+	}
+	return true
+}
+
+func hasTag(gs types.GroundStation, tag string) bool {
+	for _, t := range gs.GetTags() {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -4,21 +4,29 @@ package routing
 import (
 	"errors"
 	"math"
-	"sort"
 
-	"github.com/keniack/stardustGo/configs"
 	"github.com/keniack/stardustGo/pkg/types"
 )
 
+var _ types.ForwardingRouter = (*AStarRouter)(nil)
+
 // AStarRouter implements the A* pathfinding algorithm between nodes.
+// The search runs on the shared topology snapshot of the routing engine and minimizes its link cost,
+// guided by the cost estimate of the link cost.
+// Service routes are learned from the advertisements propagated by the other routers.
 type AStarRouter struct {
-	self  types.Node   // the node this router is mounted to
-	nodes []types.Node // cached list of all reachable nodes
+	serviceAdvertiser
+
+	self   types.Node     // the node this router is mounted to
+	engine *RoutingEngine // shared engine providing the topology snapshot
 }
 
 // NewAStarRouter creates a new AStarRouter instance.
-func NewAStarRouter() *AStarRouter {
-	return &AStarRouter{}
+func NewAStarRouter(engine *RoutingEngine, adverts *ServiceAdvertisementPlane) *AStarRouter {
+	return &AStarRouter{
+		serviceAdvertiser: newServiceAdvertiser(adverts),
+		engine:            engine,
+	}
 }
 
 // Mount binds the router to a node. This method satisfies the IRouter interface.
@@ -27,6 +35,8 @@ func (r *AStarRouter) Mount(n types.Node) error {
 		return errors.New("router already mounted")
 	}
 	r.self = n
+	r.engine.Register(n)
+	r.mount(n)
 	return nil
 }
 
@@ -42,39 +52,21 @@ func (r *AStarRouter) CalculateRoutingTable() error {
 	return nil
 }
 
-// AdvertiseNewServiceAsync advertises a new service. A* doesn't use this, but it's implemented to satisfy the IRouter interface.
-func (r *AStarRouter) AdvertiseNewServiceAsync(serviceName string) error {
-	// Placeholder for service advertisement logic (future use)
-	return nil
-}
-
-// ReceiveServiceAdvertismentsAsync updates the service routes. A* doesn't use this, but it's implemented to satisfy the IRouter interface.
-func (r *AStarRouter) ReceiveServiceAdvertismentsAsync(serviceName string, outlink types.Link, route types.RouteResult) error {
-	// Placeholder for receiving service advertisement (future use)
-	return nil
-}
-
-// RouteToService finds the nearest node that hosts the service and routes to it.
+// RouteToService routes to the closest replica of the service advertised to this router.
 func (r *AStarRouter) RouteToService(serviceName string, payload types.Payload) (types.RouteResult, error) {
 	if r.self == nil {
 		return nil, errors.New("router not mounted")
 	}
-
-	var candidates []types.Node
-	for _, n := range r.getNeighbourhood() {
-		if n.GetComputing().HostsService(serviceName) {
-			candidates = append(candidates, n)
-		}
+	if r.self.GetComputing().HostsService(serviceName) {
+		return NewOnRouteResult(0, 0), nil
 	}
 
-	if len(candidates) == 0 {
+	route, ok := r.bestServiceRoute(serviceName, payload)
+	if !ok {
 		return UnreachableRouteResultInstance, nil
 	}
-
-	sort.Slice(candidates, func(i, j int) bool {
-		return r.self.DistanceTo(candidates[i]) < r.self.DistanceTo(candidates[j])
-	})
-	return r.RouteTo(candidates[0], payload)
+	result, err := r.RouteTo(route.Origin, payload)
+	return replicaResult(result, err, route.Origin)
 }
 
 // RouteToNode is used to route to a specific node. This method satisfies the IRouter interface.
@@ -87,70 +79,77 @@ func (r *AStarRouter) RouteToNode(target types.Node, payload types.Payload) (typ
 
 // RouteTo executes A* from the mounted node to the given target.
 func (r *AStarRouter) RouteTo(target types.Node, payload types.Payload) (types.RouteResult, error) {
-	if r.self == nil {
-		return nil, errors.New("router not mounted")
+	latency, _, ok, err := r.search(target)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return UnreachableRouteResultInstance, nil
 	}
+	return NewOnRouteResult(int(latency), 0), nil
+}
 
-	openset := make(map[types.Node]float64)
-	gScore := map[types.Node]float64{r.self: 0}
-	fScore := map[types.Node]float64{r.self: heuristic(r.self, target)}
-	openset[r.self] = fScore[r.self]
-
-	for len(openset) > 0 {
-		// Find node in openset with lowest fScore
-		var current types.Node
-		minScore := math.MaxFloat64
-		for n, score := range openset {
-			if score < minScore {
-				current = n
-				minScore = score
-			}
-		}
-		delete(openset, current)
+// NextHop executes A* to the target and returns the first link of the path
+func (r *AStarRouter) NextHop(target types.Node, payload types.Payload) (types.Link, error) {
+	_, first, _, err := r.search(target)
+	return first, err
+}
 
-		if current == target {
-			return NewOnRouteResult(int(gScore[current]), 0), nil
-		}
+// search executes A* from the mounted node and returns the latency and first link of the path to the target.
+func (r *AStarRouter) search(target types.Node) (float64, types.Link, bool, error) {
+	if r.self == nil {
+		return 0, nil, false, errors.New("router not mounted")
+	}
 
-		for _, l := range current.GetLinkNodeProtocol().Established() {
-			neighbor := l.GetOther(current)
-			alt := gScore[current] + l.Latency()
-			if prev, ok := gScore[neighbor]; !ok || alt < prev {
-				gScore[neighbor] = alt
-				fScore[neighbor] = alt + heuristic(neighbor, target)
-				openset[neighbor] = fScore[neighbor]
-			}
-		}
+	snapshot := r.engine.Snapshot()
+	src, ok := snapshot.IndexOf(r.self)
+	if !ok {
+		return 0, nil, false, errors.New("router not registered at routing engine")
+	}
+	dst, ok := snapshot.IndexOf(target)
+	if !ok {
+		return 0, nil, false, nil
 	}
-	return UnreachableRouteResultInstance, nil
-}
 
-// getNeighbourhood returns all nodes connected to the current node (BFS).
-func (r *AStarRouter) getNeighbourhood() []types.Node {
-	visited := map[types.Node]bool{}
-	queue := []types.Node{r.self}
-	var result []types.Node
-
-	for len(queue) > 0 {
-		n := queue[0]
-		queue = queue[1:]
-		if visited[n] {
-			continue
+	// gScore is the link cost from the source, latency the latency along the same path
+	// and first the edge leaving the source on this path
+	gScore := make([]float64, snapshot.Len())
+	latency := make([]float64, snapshot.Len())
+	first := make([]int32, snapshot.Len())
+	for i := range gScore {
+		gScore[i] = math.Inf(1)
+		first[i] = -1
+	}
+	cost := r.engine.LinkCost()
+	targetPosition := snapshot.Position(dst)
+	openset := newNodeHeap(snapshot.Len())
+
+	gScore[src] = 0
+	openset.PushOrDecrease(src, cost.Estimate(snapshot.Position(src), targetPosition))
+	for openset.Len() > 0 {
+		// Pop node in openset with lowest fScore
+		current := openset.Pop()
+		if current == dst {
+			var firstLink types.Link
+			if first[current] >= 0 {
+				firstLink = snapshot.edges[first[current]].Link
+			}
+			return latency[current], firstLink, true, nil
 		}
-		visited[n] = true
-		result = append(result, n)
-		for _, l := range n.GetLinkNodeProtocol().Established() {
-			other := l.GetOther(n)
-			if !visited[other] {
-				queue = append(queue, other)
+
+		base := snapshot.offsets[current]
+		for i, edge := range snapshot.Edges(current) {
+			alt := gScore[current] + edge.Cost
+			if alt < gScore[edge.To] {
+				gScore[edge.To] = alt
+				latency[edge.To] = latency[current] + edge.Latency
+				first[edge.To] = first[current]
+				if current == src {
+					first[edge.To] = int32(base + i)
+				}
+				openset.PushOrDecrease(edge.To, alt+cost.Estimate(snapshot.Position(edge.To), targetPosition))
 			}
 		}
 	}
-	return result
-}
-
-// heuristic estimates the distance from node a to node b (in ms).
-func heuristic(a, b types.Node) float64 {
-	d := a.DistanceTo(b)
-	return d / configs.SpeedOfLight * 1000
+	return 0, nil, false, nil
 }
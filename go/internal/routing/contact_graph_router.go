@@ -0,0 +1,164 @@
+package routing
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var _ types.Router = (*ContactGraphRouter)(nil)
+
+// ErrNoContactPlan is returned by contact graph routers without the contact plan of the precomputed mode.
+var ErrNoContactPlan = errors.New("contact-graph routing needs the contact plan of a precomputed simulation state (--simulationStateInputFile)")
+
+// ContactGraph holds the contact plan shared by all contact graph routers and the current simulation time.
+// The plan covers all simulation states and is only known in precomputed mode; a live simulation does not
+// know its future contacts, so contact graph routers cannot route in it.
+type ContactGraph struct {
+	mu      sync.Mutex
+	plan    *ContactPlan // plan of the whole simulation, nil if unknown
+	simTime time.Time
+	routes  map[types.Node]*ContactRoutes // earliest arrival routes from each source at simTime
+}
+
+// NewContactGraph creates a contact graph without contact plan.
+func NewContactGraph() *ContactGraph {
+	return &ContactGraph{
+		routes: make(map[types.Node]*ContactRoutes),
+	}
+}
+
+// SetContactPlan sets the contact plan of the whole simulation.
+func (g *ContactGraph) SetContactPlan(plan *ContactPlan) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.plan = plan
+	g.routes = make(map[types.Node]*ContactRoutes)
+}
+
+// Plan returns the contact plan of the whole simulation, or nil if it is unknown.
+func (g *ContactGraph) Plan() *ContactPlan {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.plan
+}
+
+// Advance moves the contact graph to the new simulation time.
+func (g *ContactGraph) Advance(simTime time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.simTime = simTime
+	g.routes = make(map[types.Node]*ContactRoutes)
+}
+
+// RoutesFrom returns the earliest arrival routes for data leaving the source now.
+// It returns ErrNoContactPlan if no contact plan is set.
+func (g *ContactGraph) RoutesFrom(src types.Node) (*ContactRoutes, error) {
+	g.mu.Lock()
+	if r, ok := g.routes[src]; ok {
+		g.mu.Unlock()
+		return r, nil
+	}
+	plan, simTime := g.plan, g.simTime
+	g.mu.Unlock()
+	if plan == nil {
+		return nil, ErrNoContactPlan
+	}
+
+	routes, ok := plan.EarliestArrival(src, simTime)
+	if !ok {
+		return nil, errors.New("node is not part of the contact plan")
+	}
+
+	g.mu.Lock()
+	if g.simTime.Equal(simTime) {
+		g.routes[src] = routes
+	}
+	g.mu.Unlock()
+	return routes, nil
+}
+
+// ContactGraphRouter implements Contact Graph Routing (CGR) for store-and-forward traffic.
+// Routes lead over the contact plan and may hold the data on a node until a future contact starts,
+// so a target is reachable even if there is no end-to-end path right now.
+// Service routes are learned from the advertisements propagated by the other routers.
+type ContactGraphRouter struct {
+	serviceAdvertiser
+
+	node   types.Node
+	engine *RoutingEngine
+	graph  *ContactGraph
+}
+
+// NewContactGraphRouter creates a new contact graph router on the given contact graph
+func NewContactGraphRouter(engine *RoutingEngine, graph *ContactGraph, adverts *ServiceAdvertisementPlane) *ContactGraphRouter {
+	return &ContactGraphRouter{
+		serviceAdvertiser: newServiceAdvertiser(adverts),
+		engine:            engine,
+		graph:             graph,
+	}
+}
+
+// Mount attaches the router to a node
+func (r *ContactGraphRouter) Mount(node types.Node) error {
+	if r.node != nil {
+		return errors.New("router already mounted")
+	}
+	r.node = node
+	r.engine.Register(node)
+	r.mount(node)
+	return nil
+}
+
+// CanPreRouteCalc returns true, the earliest arrival routes can be calculated ahead of time
+func (r *ContactGraphRouter) CanPreRouteCalc() bool { return true }
+
+// CanOnRouteCalc returns true
+func (r *ContactGraphRouter) CanOnRouteCalc() bool { return true }
+
+// CalculateRoutingTable calculates the earliest arrival routes for data leaving the mounted node now
+func (r *ContactGraphRouter) CalculateRoutingTable() error {
+	if r.node == nil {
+		return errors.New("router not mounted")
+	}
+	_, err := r.graph.RoutesFrom(r.node)
+	return err
+}
+
+// RouteToNode returns the earliest arrival route to the target together with the expected delivery time
+func (r *ContactGraphRouter) RouteToNode(target types.Node, payload types.Payload) (types.RouteResult, error) {
+	if r.node == nil {
+		return nil, errors.New("router not mounted")
+	}
+	if r.node == target {
+		return NewPreRouteResult(0), nil
+	}
+
+	routes, err := r.graph.RoutesFrom(r.node)
+	if err != nil {
+		return nil, err
+	}
+	if !routes.Reachable(target) {
+		return UnreachableRouteResultInstance, nil
+	}
+	return NewContactRouteResult(routes.Delay(target), routes.ArrivalTime(target), routes.ContactsTo(target)), nil
+}
+
+// RouteToService routes to the closest replica of the service advertised to this router
+func (r *ContactGraphRouter) RouteToService(serviceName string, payload types.Payload) (types.RouteResult, error) {
+	if r.node == nil {
+		return nil, errors.New("router not mounted")
+	}
+	if r.node.GetComputing().HostsService(serviceName) {
+		return NewPreRouteResult(0), nil
+	}
+
+	route, ok := r.bestServiceRoute(serviceName, payload)
+	if !ok {
+		return UnreachableRouteResultInstance, nil
+	}
+	result, err := r.RouteToNode(route.Origin, payload)
+	return replicaResult(result, err, route.Origin)
+}
@@ -0,0 +1,225 @@
+package routing
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// openEnd marks a contact without known end.
+var openEnd = time.Unix(1<<62, 0)
+
+// Contact is a time window in which data can be sent from one node to another over a link.
+type Contact struct {
+	From    types.Node
+	To      types.Node
+	Link    types.Link
+	Start   time.Time
+	End     time.Time
+	Latency float64 // worst-case one-way latency in ms during the contact
+}
+
+// contactLatencies are the latencies of a contact per topology sample.
+type contactLatencies struct {
+	first     int       // index of the first sample of the contact
+	latencies []float64 // latency in ms by sample, starting at first
+}
+
+// ContactPlan is the time-expanded contact graph of the simulation: all future link up intervals.
+// Data may be stored on a node until one of its contacts starts (store-and-forward).
+type ContactPlan struct {
+	nodes    []types.Node
+	index    map[types.Node]int
+	contacts []Contact
+	from     []int              // dense ID of the sending node by contact
+	to       []int              // dense ID of the receiving node by contact
+	outgoing [][]int            // contacts by dense ID of the sending node, ordered by start
+	times    []time.Time        // start times of the topology samples
+	samples  []contactLatencies // latencies by contact
+}
+
+// NewContactPlan builds a contact plan from topology samples, e.g. the precomputed simulation states.
+// established[i] holds the links established from times[i] until times[i+1]; the last sample is assumed
+// to last as long as the one before. latency returns the latency of a link in ms at sample i.
+// Consecutive samples of the same link are merged into one contact.
+func NewContactPlan(nodes []types.Node, times []time.Time, established [][]types.Link, latency func(i int, l types.Link) float64) *ContactPlan {
+	p := &ContactPlan{
+		nodes:    nodes,
+		index:    make(map[types.Node]int, len(nodes)),
+		outgoing: make([][]int, len(nodes)),
+		times:    times,
+	}
+	for i, n := range nodes {
+		p.index[n] = i
+	}
+
+	type direction struct {
+		link types.Link
+		from types.Node
+	}
+	active := make(map[direction]int) // open contact index by link direction
+	for i, links := range established {
+		start, end := times[i], sampleEnd(times, i)
+		for _, l := range links {
+			n1, n2 := l.Nodes()
+			lat := latency(i, l)
+			for _, d := range [][2]types.Node{{n1, n2}, {n2, n1}} {
+				key := direction{l, d[0]}
+				if c, ok := active[key]; ok && p.contacts[c].End.Equal(start) {
+					p.contacts[c].End = end
+					p.contacts[c].Latency = math.Max(p.contacts[c].Latency, lat)
+					p.samples[c].latencies = append(p.samples[c].latencies, lat)
+					continue
+				}
+				if c := p.add(Contact{From: d[0], To: d[1], Link: l, Start: start, End: end, Latency: lat}, i); c >= 0 {
+					active[key] = c
+				}
+			}
+		}
+	}
+
+	for _, out := range p.outgoing {
+		sort.Slice(out, func(a, b int) bool { return p.contacts[out[a]].Start.Before(p.contacts[out[b]].Start) })
+	}
+	return p
+}
+
+// sampleEnd returns the end of the sample i.
+func sampleEnd(times []time.Time, i int) time.Time {
+	switch {
+	case i+1 < len(times):
+		return times[i+1]
+	case i > 0:
+		return times[i].Add(times[i].Sub(times[i-1]))
+	default:
+		return openEnd
+	}
+}
+
+// add appends a contact starting at the given sample between two nodes of the plan and returns its index.
+func (p *ContactPlan) add(c Contact, sample int) int {
+	from, ok1 := p.index[c.From]
+	to, ok2 := p.index[c.To]
+	if !ok1 || !ok2 {
+		return -1
+	}
+	ix := len(p.contacts)
+	p.contacts = append(p.contacts, c)
+	p.from = append(p.from, from)
+	p.to = append(p.to, to)
+	p.outgoing[from] = append(p.outgoing[from], ix)
+	p.samples = append(p.samples, contactLatencies{first: sample, latencies: []float64{c.Latency}})
+	return ix
+}
+
+// latencyAt returns the latency of the contact for data sent at the given time.
+func (p *ContactPlan) latencyAt(c int, t time.Time) float64 {
+	s := p.samples[c]
+	k := sort.Search(len(p.times), func(i int) bool { return p.times[i].After(t) }) - 1 - s.first
+	return s.latencies[max(0, min(k, len(s.latencies)-1))]
+}
+
+// Contacts returns all contacts of the plan.
+func (p *ContactPlan) Contacts() []Contact {
+	return p.contacts
+}
+
+// ContactRoutes holds the earliest arrival routes from one source for data leaving at a given time.
+type ContactRoutes struct {
+	plan      *ContactPlan
+	source    int
+	departure time.Time
+	arrival   []float64 // ms after departure by dense node ID, +Inf if unreachable
+	via       []int32   // contact used to reach the node, -1 for the source and unreachable nodes
+}
+
+// EarliestArrival calculates the earliest arrival time at every node for data leaving src at departure.
+// Data is held on a node until a contact starts; a contact can be used until it ends.
+func (p *ContactPlan) EarliestArrival(src types.Node, departure time.Time) (*ContactRoutes, bool) {
+	source, ok := p.index[src]
+	if !ok {
+		return nil, false
+	}
+
+	n := len(p.nodes)
+	r := &ContactRoutes{
+		plan:      p,
+		source:    source,
+		departure: departure,
+		arrival:   make([]float64, n),
+		via:       make([]int32, n),
+	}
+	for i := range r.arrival {
+		r.arrival[i] = math.Inf(1)
+		r.via[i] = -1
+	}
+
+	h := newNodeHeap(n)
+	r.arrival[source] = 0
+	h.PushOrDecrease(source, 0)
+	for h.Len() > 0 {
+		u := h.Pop()
+		for _, c := range p.outgoing[u] {
+			contact := &p.contacts[c]
+			if !contact.End.After(departure) {
+				continue
+			}
+			send := math.Max(r.arrival[u], msSince(departure, contact.Start))
+			if send >= msSince(departure, contact.End) {
+				continue
+			}
+			alt := send + p.latencyAt(c, departure.Add(time.Duration(send*float64(time.Millisecond))))
+			if v := p.to[c]; alt < r.arrival[v] {
+				r.arrival[v] = alt
+				r.via[v] = int32(c)
+				h.PushOrDecrease(v, alt)
+			}
+		}
+	}
+	return r, true
+}
+
+// msSince returns the milliseconds from departure until t, which may be negative.
+func msSince(departure, t time.Time) float64 {
+	return float64(t.Sub(departure)) / float64(time.Millisecond)
+}
+
+// Reachable returns true if the target is reached within the contact plan.
+func (r *ContactRoutes) Reachable(target types.Node) bool {
+	id, ok := r.plan.index[target]
+	return ok && !math.IsInf(r.arrival[id], 1)
+}
+
+// Delay returns the ms from departure until the data arrives at the target, including the time stored on nodes.
+func (r *ContactRoutes) Delay(target types.Node) float64 {
+	id, ok := r.plan.index[target]
+	if !ok {
+		return math.Inf(1)
+	}
+	return r.arrival[id]
+}
+
+// ArrivalTime returns the earliest time the data arrives at the target.
+func (r *ContactRoutes) ArrivalTime(target types.Node) time.Time {
+	return r.departure.Add(time.Duration(r.Delay(target) * float64(time.Millisecond)))
+}
+
+// ContactsTo returns the contacts used from the source to the target in order.
+func (r *ContactRoutes) ContactsTo(target types.Node) []Contact {
+	id, ok := r.plan.index[target]
+	if !ok {
+		return nil
+	}
+	var contacts []Contact
+	for r.via[id] >= 0 {
+		c := int(r.via[id])
+		contacts = append(contacts, r.plan.contacts[c])
+		id = r.plan.from[c]
+	}
+	for i, j := 0, len(contacts)-1; i < j; i, j = i+1, j-1 {
+		contacts[i], contacts[j] = contacts[j], contacts[i]
+	}
+	return contacts
+}
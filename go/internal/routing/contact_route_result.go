@@ -0,0 +1,44 @@
+package routing
+
+import (
+	"time"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var _ types.RouteResult = (*ContactRouteResult)(nil)
+
+// ContactRouteResult is a store-and-forward route over a sequence of contacts.
+// Its latency is the time from now until delivery, including the time the data is stored on nodes.
+type ContactRouteResult struct {
+	PreRouteResult
+	delivery time.Time
+	contacts []Contact
+}
+
+// NewContactRouteResult creates a route delivering the data at the given time
+func NewContactRouteResult(delay float64, delivery time.Time, contacts []Contact) *ContactRouteResult {
+	return &ContactRouteResult{
+		PreRouteResult: PreRouteResult{latency: int(delay)},
+		delivery:       delivery,
+		contacts:       contacts,
+	}
+}
+
+// ExpectedDeliveryTime returns the simulation time the data arrives at the target
+func (r *ContactRouteResult) ExpectedDeliveryTime() time.Time {
+	return r.delivery
+}
+
+// Destination returns the node the route delivers the data to, nil for a route without contacts
+func (r *ContactRouteResult) Destination() types.Node {
+	if len(r.contacts) == 0 {
+		return nil
+	}
+	return r.contacts[len(r.contacts)-1].To
+}
+
+// Contacts returns the contacts used by the route in order
+func (r *ContactRouteResult) Contacts() []Contact {
+	return r.contacts
+}
@@ -0,0 +1,87 @@
+package routing
+
+import (
+	"sync"
+	"time"
+
+	"github.com/keniack/stardustGo/internal/geo"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// GeoFence locates nodes in the regions of geographic routing constraints.
+// The location of a node is the sub-point of its position, cached per simulation step.
+type GeoFence struct {
+	mu      sync.Mutex
+	regions *geo.Regions
+	epoch   time.Time // simulation start, at which the simulation frame matches the Earth-fixed frame
+	simTime time.Time
+	located map[types.Node][]string
+}
+
+// NewGeoFence creates a geo fence on the given regions.
+func NewGeoFence(regions *geo.Regions) *GeoFence {
+	if regions == nil {
+		regions = geo.NewRegions()
+	}
+	return &GeoFence{
+		regions: regions,
+		located: make(map[types.Node][]string),
+	}
+}
+
+// Regions returns the known regions.
+func (g *GeoFence) Regions() *geo.Regions {
+	return g.regions
+}
+
+// SetEpoch sets the simulation start time, which is needed to account for the Earth rotation.
+func (g *GeoFence) SetEpoch(epoch time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.epoch = epoch
+	g.located = make(map[types.Node][]string)
+}
+
+// Advance moves the geo fence to the new simulation time.
+func (g *GeoFence) Advance(simTime time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.simTime = simTime
+	g.located = make(map[types.Node][]string)
+}
+
+// SubPoint returns the geographic coordinate below the node at the current simulation time.
+func (g *GeoFence) SubPoint(n types.Node) geo.Coordinate {
+	g.mu.Lock()
+	elapsed := g.elapsed()
+	g.mu.Unlock()
+	return geo.SubPoint(n.GetPosition(), elapsed)
+}
+
+// RegionsOf returns the names of all regions the node is located in.
+func (g *GeoFence) RegionsOf(n types.Node) []string {
+	g.mu.Lock()
+	if names, ok := g.located[n]; ok {
+		g.mu.Unlock()
+		return names
+	}
+	simTime, elapsed := g.simTime, g.elapsed()
+	g.mu.Unlock()
+
+	names := g.regions.Containing(geo.SubPoint(n.GetPosition(), elapsed))
+
+	g.mu.Lock()
+	if g.simTime.Equal(simTime) {
+		g.located[n] = names
+	}
+	g.mu.Unlock()
+	return names
+}
+
+// elapsed returns the simulation time since the epoch, zero before the first step.
+func (g *GeoFence) elapsed() time.Duration {
+	if g.simTime.IsZero() || g.epoch.IsZero() {
+		return 0
+	}
+	return g.simTime.Sub(g.epoch)
+}
@@ -0,0 +1,214 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var _ types.Router = (*GeoFencedRouter)(nil)
+
+// GeoFencedRouter routes along the path with the lowest latency which complies with the geographic
+// constraints of the payload (types.RegionConstrainedPayload). Nodes are located by their sub-point
+// in the regions of the shared GeoFence. Avoided regions may contain no node of the path except the source,
+// with egress regions every ground station on the path has to lie in one of them.
+// If no compliant path exists, the route is unreachable with the violated constraint as reason.
+// Service routes are learned from the advertisements propagated by the other routers.
+type GeoFencedRouter struct {
+	serviceAdvertiser
+
+	node   types.Node
+	engine *RoutingEngine
+	fence  *GeoFence
+}
+
+// NewGeoFencedRouter creates a new geo-fenced router using the regions of the given geo fence
+func NewGeoFencedRouter(engine *RoutingEngine, fence *GeoFence, adverts *ServiceAdvertisementPlane) *GeoFencedRouter {
+	return &GeoFencedRouter{
+		serviceAdvertiser: newServiceAdvertiser(adverts),
+		engine:            engine,
+		fence:             fence,
+	}
+}
+
+// Mount attaches the router to a node
+func (r *GeoFencedRouter) Mount(node types.Node) error {
+	if r.node != nil {
+		return errors.New("router already mounted")
+	}
+	r.node = node
+	r.engine.Register(node)
+	r.mount(node)
+	return nil
+}
+
+// CanPreRouteCalc returns false, paths depend on the constraints of each payload
+func (r *GeoFencedRouter) CanPreRouteCalc() bool { return false }
+
+// CanOnRouteCalc returns true
+func (r *GeoFencedRouter) CanOnRouteCalc() bool { return true }
+
+// CalculateRoutingTable is a no-op, paths are calculated per payload
+func (r *GeoFencedRouter) CalculateRoutingTable() error {
+	return nil
+}
+
+// RouteToNode returns the path with the lowest latency to the target complying with the constraints of the payload
+func (r *GeoFencedRouter) RouteToNode(target types.Node, payload types.Payload) (types.RouteResult, error) {
+	if r.node == nil {
+		return nil, errors.New("router not mounted")
+	}
+	if r.node == target {
+		return NewPreRouteResult(0), nil
+	}
+
+	var constraints types.RegionConstraints
+	if c, ok := payload.(types.RegionConstrainedPayload); ok {
+		constraints = c.Constraints()
+	}
+	if err := r.validate(constraints); err != nil {
+		return nil, err
+	}
+
+	snapshot := r.engine.Snapshot()
+	src, ok := snapshot.IndexOf(r.node)
+	if !ok {
+		return nil, errors.New("node is not registered at routing engine")
+	}
+	dst, ok := snapshot.IndexOf(target)
+	if !ok {
+		return UnreachableRouteResultInstance, nil
+	}
+
+	path, reason := r.findPath(snapshot, src, dst, constraints)
+	if reason != "" {
+		return NewUnreachableRouteResult(reason), nil
+	}
+	return NewPathRouteResult(path.toPath(snapshot)), nil
+}
+
+// RouteToService routes to the closest replica of the service which is reachable on a compliant path
+func (r *GeoFencedRouter) RouteToService(serviceName string, payload types.Payload) (types.RouteResult, error) {
+	if r.node == nil {
+		return nil, errors.New("router not mounted")
+	}
+	if r.node.GetComputing().HostsService(serviceName) {
+		return NewPreRouteResult(0), nil
+	}
+
+	var closest types.RouteResult
+	for _, route := range r.serviceRoutes(serviceName) {
+		result, err := r.RouteToNode(route.Origin, payload)
+		if err != nil {
+			return nil, err
+		}
+		if result.Reachable() {
+			return result, nil
+		}
+		if closest == nil {
+			closest = result
+		}
+	}
+	if closest == nil {
+		return UnreachableRouteResultInstance, nil
+	}
+	return closest, nil
+}
+
+// validate checks that all regions of the constraints are known.
+func (r *GeoFencedRouter) validate(c types.RegionConstraints) error {
+	for _, names := range [][]string{c.AvoidRegions, c.EgressRegions} {
+		for _, name := range names {
+			if _, ok := r.fence.Regions().Get(name); !ok {
+				return fmt.Errorf("unknown region: %s", name)
+			}
+		}
+	}
+	return nil
+}
+
+// findPath returns the path with the lowest latency complying with the constraints, or why none exists.
+func (r *GeoFencedRouter) findPath(s *TopologySnapshot, src, dst int, c types.RegionConstraints) (snapshotPath, string) {
+	target := s.Node(dst)
+	if region, ok := r.locatedIn(target, c.AvoidRegions); ok {
+		return snapshotPath{}, fmt.Sprintf("target %s lies in avoided region %s", target.GetName(), region)
+	}
+	if len(c.EgressRegions) > 0 {
+		if !isGroundStation(target) {
+			return snapshotPath{}, fmt.Sprintf("target %s is no ground station to egress via", target.GetName())
+		}
+		if _, ok := r.locatedIn(target, c.EgressRegions); !ok {
+			return snapshotPath{}, fmt.Sprintf("target %s lies outside the egress regions %s", target.GetName(), strings.Join(c.EgressRegions, ", "))
+		}
+	}
+
+	search := newPathSearch(s)
+	var egressBans []int
+	for id := 0; id < s.Len(); id++ {
+		if id == src {
+			continue
+		}
+		n := s.Node(id)
+		if _, ok := r.locatedIn(n, c.AvoidRegions); ok {
+			search.banNode(id)
+		} else if len(c.EgressRegions) > 0 && isGroundStation(n) {
+			if _, ok := r.locatedIn(n, c.EgressRegions); !ok {
+				search.banNode(id)
+				egressBans = append(egressBans, id)
+			}
+		}
+	}
+	if path, ok := search.shortest(src, dst); ok {
+		return path, ""
+	}
+	return snapshotPath{}, r.diagnose(s, search, src, dst, c, egressBans)
+}
+
+// diagnose finds the constraint which makes the target unreachable.
+func (r *GeoFencedRouter) diagnose(s *TopologySnapshot, search *pathSearch, src, dst int, c types.RegionConstraints, egressBans []int) string {
+	search.clearBans()
+	shortest, ok := search.shortest(src, dst)
+	if !ok {
+		return "no route to target"
+	}
+
+	// Lift the egress bans, if a path exists now only the egress constraint is violated
+	for id := 0; id < s.Len(); id++ {
+		if _, avoided := r.locatedIn(s.Node(id), c.AvoidRegions); avoided && id != src {
+			search.banNode(id)
+		}
+	}
+	if _, ok := search.shortest(src, dst); ok && len(egressBans) > 0 {
+		return fmt.Sprintf("every path relays over a ground station outside the egress regions %s", strings.Join(c.EgressRegions, ", "))
+	}
+
+	for _, id := range shortest.nodes[1:] {
+		if region, ok := r.locatedIn(s.Node(id), c.AvoidRegions); ok {
+			return fmt.Sprintf("every path relays over an avoided region, the shortest one over %s in %s", s.Node(id).GetName(), region)
+		}
+	}
+	return "every path relays over an avoided region"
+}
+
+// locatedIn returns the first of the given regions the node is located in.
+func (r *GeoFencedRouter) locatedIn(n types.Node, regions []string) (string, bool) {
+	if len(regions) == 0 {
+		return "", false
+	}
+	for _, located := range r.fence.RegionsOf(n) {
+		for _, region := range regions {
+			if located == region {
+				return region, true
+			}
+		}
+	}
+	return "", false
+}
+
+// isGroundStation checks if the node is a ground station, i.e. any node which is not a satellite.
+func isGroundStation(n types.Node) bool {
+	_, ok := n.(types.Satellite)
+	return !ok
+}
@@ -0,0 +1,221 @@
+package routing
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// snapshotPath is a path in a TopologySnapshot given by dense node IDs and edge indices.
+type snapshotPath struct {
+	nodes   []int
+	edges   []int
+	latency float64
+}
+
+// pathSearch runs shortest path searches on a snapshot which avoid banned nodes and edges.
+// The buffers are reused between searches.
+type pathSearch struct {
+	s           *TopologySnapshot
+	h           *nodeHeap
+	dist        []float64
+	prevNode    []int32
+	prevEdge    []int32
+	bannedNodes []bool
+	bannedEdges []bool
+	banned      []int // banned node IDs to reset
+	bannedE     []int // banned edge indices to reset
+}
+
+func newPathSearch(s *TopologySnapshot) *pathSearch {
+	return &pathSearch{
+		s:           s,
+		h:           newNodeHeap(s.Len()),
+		dist:        make([]float64, s.Len()),
+		prevNode:    make([]int32, s.Len()),
+		prevEdge:    make([]int32, s.Len()),
+		bannedNodes: make([]bool, s.Len()),
+		bannedEdges: make([]bool, s.EdgeCount()),
+	}
+}
+
+func (p *pathSearch) banNode(id int) {
+	if !p.bannedNodes[id] {
+		p.bannedNodes[id] = true
+		p.banned = append(p.banned, id)
+	}
+}
+
+func (p *pathSearch) banEdge(e int) {
+	if !p.bannedEdges[e] {
+		p.bannedEdges[e] = true
+		p.bannedE = append(p.bannedE, e)
+	}
+}
+
+// clearBans lifts all bans set since the last call.
+func (p *pathSearch) clearBans() {
+	for _, id := range p.banned {
+		p.bannedNodes[id] = false
+	}
+	for _, e := range p.bannedE {
+		p.bannedEdges[e] = false
+	}
+	p.banned = p.banned[:0]
+	p.bannedE = p.bannedE[:0]
+}
+
+// shortest runs Dijkstra's algorithm from src to dst without using banned nodes or edges.
+func (p *pathSearch) shortest(src, dst int) (snapshotPath, bool) {
+	for i := range p.dist {
+		p.dist[i] = math.Inf(1)
+		p.prevNode[i] = -1
+		p.prevEdge[i] = -1
+	}
+
+	p.h.Reset()
+	p.dist[src] = 0
+	p.h.PushOrDecrease(src, 0)
+	for p.h.Len() > 0 {
+		u := p.h.Pop()
+		if u == dst {
+			break
+		}
+		base := p.s.offsets[u]
+		for i, edge := range p.s.Edges(u) {
+			if p.bannedEdges[base+i] || p.bannedNodes[edge.To] {
+				continue
+			}
+			alt := p.dist[u] + edge.Latency
+			if alt < p.dist[edge.To] {
+				p.dist[edge.To] = alt
+				p.prevNode[edge.To] = int32(u)
+				p.prevEdge[edge.To] = int32(base + i)
+				p.h.PushOrDecrease(edge.To, alt)
+			}
+		}
+	}
+	if math.IsInf(p.dist[dst], 1) {
+		return snapshotPath{}, false
+	}
+
+	path := snapshotPath{latency: p.dist[dst]}
+	for id := dst; id != src; id = int(p.prevNode[id]) {
+		path.nodes = append(path.nodes, id)
+		path.edges = append(path.edges, int(p.prevEdge[id]))
+	}
+	path.nodes = append(path.nodes, src)
+	reverseInts(path.nodes)
+	reverseInts(path.edges)
+	return path, true
+}
+
+// kShortestPaths returns up to k loop-free paths from src to dst ordered by latency (Yen's algorithm).
+func kShortestPaths(s *TopologySnapshot, src, dst, k int) []snapshotPath {
+	if k <= 0 || src == dst {
+		return nil
+	}
+	search := newPathSearch(s)
+	first, ok := search.shortest(src, dst)
+	if !ok {
+		return nil
+	}
+
+	paths := []snapshotPath{first}
+	seen := map[string]bool{first.key(): true}
+	var candidates []snapshotPath
+	for len(paths) < k {
+		last := paths[len(paths)-1]
+		rootLatency := 0.0
+		for i := 0; i < len(last.nodes)-1; i++ {
+			spur := last.nodes[i]
+			root := last.edges[:i]
+
+			// Remove the next edge of every known path sharing the root, and the root nodes itself
+			for _, p := range paths {
+				if len(p.edges) > i && equalInts(p.edges[:i], root) {
+					search.banEdge(p.edges[i])
+				}
+			}
+			for _, id := range last.nodes[:i] {
+				search.banNode(id)
+			}
+			spurPath, ok := search.shortest(spur, dst)
+			search.clearBans()
+
+			if ok {
+				candidate := snapshotPath{
+					nodes:   append(append([]int{}, last.nodes[:i]...), spurPath.nodes...),
+					edges:   append(append([]int{}, root...), spurPath.edges...),
+					latency: rootLatency + spurPath.latency,
+				}
+				if key := candidate.key(); !seen[key] {
+					seen[key] = true
+					candidates = append(candidates, candidate)
+				}
+			}
+			rootLatency += s.edges[last.edges[i]].Latency
+		}
+		if len(candidates) == 0 {
+			break
+		}
+
+		// Take the best candidate, preferring fewer hops on equal latency
+		best := 0
+		for i, c := range candidates {
+			if c.latency < candidates[best].latency ||
+				(c.latency == candidates[best].latency && len(c.edges) < len(candidates[best].edges)) {
+				best = i
+			}
+		}
+		paths = append(paths, candidates[best])
+		candidates = append(candidates[:best], candidates[best+1:]...)
+	}
+	return paths
+}
+
+// key identifies a path by its edges.
+func (p snapshotPath) key() string {
+	var b strings.Builder
+	for _, e := range p.edges {
+		b.WriteString(strconv.Itoa(e))
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+// toPath resolves the dense IDs of the path to nodes and links.
+func (p snapshotPath) toPath(s *TopologySnapshot) types.Path {
+	path := types.Path{
+		Nodes:   make([]types.Node, len(p.nodes)),
+		Links:   make([]types.Link, len(p.edges)),
+		Latency: p.latency,
+	}
+	for i, id := range p.nodes {
+		path.Nodes[i] = s.Node(id)
+	}
+	for i, e := range p.edges {
+		path.Links[i] = s.edges[e].Link
+	}
+	return path
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func reverseInts(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
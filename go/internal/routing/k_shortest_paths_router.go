@@ -0,0 +1,191 @@
+package routing
+
+import (
+	"errors"
+	"hash/fnv"
+	"sync"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var _ types.MultipathRouter = (*KShortestPathsRouter)(nil)
+
+// Defaults of the k-shortest-paths router if not configured
+const (
+	DefaultPathCount = 4
+)
+
+// KShortestPathsRouter computes the k loop-free shortest paths to a target with Yen's algorithm.
+// Paths whose latency is within a tolerance of the shortest one form the equal-cost multipath (ECMP) set,
+// over which flows are spread by hashing their flow ID.
+// The searches run on the shared topology snapshot of the routing engine, results are cached per step.
+// Service routes are learned from the advertisements propagated by the other routers.
+type KShortestPathsRouter struct {
+	serviceAdvertiser
+
+	node      types.Node
+	engine    *RoutingEngine
+	k         int     // number of paths calculated per target
+	tolerance float64 // relative latency tolerance of the ECMP set, e.g. 0.05 for 5%
+
+	mu       sync.Mutex
+	snapshot *TopologySnapshot   // snapshot the cached paths were calculated on
+	paths    map[int]cachedPaths // cached paths by dense target ID
+}
+
+// cachedPaths are the paths found when searching for the k shortest paths.
+type cachedPaths struct {
+	k     int
+	paths []types.Path
+}
+
+// NewKShortestPathsRouter creates a new router calculating k paths per target.
+// A non-positive k falls back to DefaultPathCount.
+func NewKShortestPathsRouter(engine *RoutingEngine, adverts *ServiceAdvertisementPlane, k int, tolerance float64) *KShortestPathsRouter {
+	if k <= 0 {
+		k = DefaultPathCount
+	}
+	if tolerance < 0 {
+		tolerance = 0
+	}
+	return &KShortestPathsRouter{
+		serviceAdvertiser: newServiceAdvertiser(adverts),
+		engine:            engine,
+		k:                 k,
+		tolerance:         tolerance,
+	}
+}
+
+// Mount attaches the router to a node
+func (r *KShortestPathsRouter) Mount(node types.Node) error {
+	if r.node != nil {
+		return errors.New("router already mounted")
+	}
+	r.node = node
+	r.engine.Register(node)
+	r.mount(node)
+	return nil
+}
+
+// CanPreRouteCalc returns false, paths are calculated per target on demand
+func (r *KShortestPathsRouter) CanPreRouteCalc() bool { return false }
+
+// CanOnRouteCalc returns true
+func (r *KShortestPathsRouter) CanOnRouteCalc() bool { return true }
+
+// CalculateRoutingTable is a no-op, the paths are calculated on first use in every step
+func (r *KShortestPathsRouter) CalculateRoutingTable() error {
+	return nil
+}
+
+// RouteToNode routes over one of the equal-cost paths to the target.
+// If the payload is a types.Flow, the path is selected by its flow ID, otherwise the shortest path is used.
+func (r *KShortestPathsRouter) RouteToNode(target types.Node, payload types.Payload) (types.RouteResult, error) {
+	if r.node == nil {
+		return nil, errors.New("router not mounted")
+	}
+	if r.node == target {
+		return NewPreRouteResult(0), nil
+	}
+
+	paths, err := r.EqualCostPathsToNode(target)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return UnreachableRouteResultInstance, nil
+	}
+	if flow, ok := payload.(types.Flow); ok {
+		path, _ := SelectPathForFlow(paths, flow.FlowID())
+		return NewPathRouteResult(path), nil
+	}
+	return NewPathRouteResult(paths[0]), nil
+}
+
+// RouteToService routes to the closest replica of the service advertised to this router
+func (r *KShortestPathsRouter) RouteToService(serviceName string, payload types.Payload) (types.RouteResult, error) {
+	if r.node == nil {
+		return nil, errors.New("router not mounted")
+	}
+	if r.node.GetComputing().HostsService(serviceName) {
+		return NewPreRouteResult(0), nil
+	}
+
+	route, ok := r.bestServiceRoute(serviceName, payload)
+	if !ok {
+		return UnreachableRouteResultInstance, nil
+	}
+	result, err := r.RouteToNode(route.Origin, payload)
+	return replicaResult(result, err, route.Origin)
+}
+
+// KShortestPathsToNode returns up to k loop-free paths to the target ordered by latency
+func (r *KShortestPathsRouter) KShortestPathsToNode(target types.Node, k int) ([]types.Path, error) {
+	if r.node == nil {
+		return nil, errors.New("router not mounted")
+	}
+
+	snapshot := r.engine.Snapshot()
+	src, ok := snapshot.IndexOf(r.node)
+	if !ok {
+		return nil, errors.New("node is not registered at routing engine")
+	}
+	dst, ok := snapshot.IndexOf(target)
+	if !ok {
+		return nil, nil
+	}
+
+	r.mu.Lock()
+	if r.snapshot != snapshot {
+		r.snapshot = snapshot
+		r.paths = make(map[int]cachedPaths)
+	}
+	entry, cached := r.paths[dst]
+	r.mu.Unlock()
+
+	// Search again only if more paths are requested than searched for and the last search found all of them
+	if !cached || (k > entry.k && len(entry.paths) == entry.k) {
+		entry = cachedPaths{k: max(k, r.k)}
+		for _, p := range kShortestPaths(snapshot, src, dst, entry.k) {
+			entry.paths = append(entry.paths, p.toPath(snapshot))
+		}
+		r.mu.Lock()
+		if r.snapshot == snapshot {
+			r.paths[dst] = entry
+		}
+		r.mu.Unlock()
+	}
+
+	paths := entry.paths
+
+	if len(paths) > k {
+		paths = paths[:k]
+	}
+	return paths, nil
+}
+
+// EqualCostPathsToNode returns the paths to the target whose latency is within the tolerance of the shortest path
+func (r *KShortestPathsRouter) EqualCostPathsToNode(target types.Node) ([]types.Path, error) {
+	paths, err := r.KShortestPathsToNode(target, r.k)
+	if err != nil || len(paths) == 0 {
+		return nil, err
+	}
+
+	limit := paths[0].Latency * (1 + r.tolerance)
+	n := 1
+	for n < len(paths) && paths[n].Latency <= limit {
+		n++
+	}
+	return paths[:n], nil
+}
+
+// SelectPathForFlow maps a flow onto one of the given paths by hashing its flow ID.
+// All payloads of a flow take the same path, while different flows are spread over all paths.
+func SelectPathForFlow(paths []types.Path, flowID string) (types.Path, bool) {
+	if len(paths) == 0 {
+		return types.Path{}, false
+	}
+	h := fnv.New32a()
+	h.Write([]byte(flowID))
+	return paths[h.Sum32()%uint32(len(paths))], true
+}
@@ -0,0 +1,47 @@
+package routing
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestKShortestPaths(t *testing.T) {
+	s := NewTopologySnapshot(diamond(), latencyCost{})
+	a, c, d, e := 0, 2, 3, 4
+
+	tests := []struct {
+		name      string
+		src, dst  int
+		k         int
+		paths     []string
+		latencies []float64
+	}{
+		{"shortest", a, d, 1, []string{"A-B-D"}, []float64{2}},
+		{"three", a, d, 3, []string{"A-B-D", "A-C-B-D", "A-C-D"}, []float64{2, 4.5, 5}},
+		{"all loop-free", a, d, 10, []string{"A-B-D", "A-C-B-D", "A-C-D", "A-B-C-D"}, []float64{2, 4.5, 5, 5.5}},
+		{"reverse", d, c, 2, []string{"D-B-C", "D-C"}, []float64{2.5, 3}},
+		{"none", a, d, 0, nil, nil},
+		{"same node", a, a, 3, nil, nil},
+		{"unreachable", a, e, 3, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths []string
+			var latencies []float64
+			for _, p := range kShortestPaths(s, tt.src, tt.dst, tt.k) {
+				path := p.toPath(s)
+				paths = append(paths, pathNames(path.Nodes))
+				latencies = append(latencies, path.Latency)
+				if len(path.Links) != len(path.Nodes)-1 {
+					t.Errorf("path %s has %d links", pathNames(path.Nodes), len(path.Links))
+				}
+			}
+			if !slices.Equal(paths, tt.paths) {
+				t.Errorf("paths = %v, want %v", paths, tt.paths)
+			}
+			if !slices.Equal(latencies, tt.latencies) {
+				t.Errorf("latencies = %v, want %v", latencies, tt.latencies)
+			}
+		})
+	}
+}
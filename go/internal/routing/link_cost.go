@@ -0,0 +1,174 @@
+package routing
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// LinkCost assigns the weight of a link used by the shortest path searches.
+type LinkCost interface {
+	// Cost returns the non-negative cost of sending over the link from one node to the other
+	Cost(l types.Link, from, to types.Node) float64
+
+	// Estimate returns a lower bound of the cost of any path between two positions.
+	// It guides the A* search and must never overestimate, otherwise A* is not optimal.
+	Estimate(from, to types.Vector) float64
+}
+
+// LinkCostFactory creates a link cost from the router configuration.
+type LinkCostFactory func(cfg configs.RouterConfig) (LinkCost, error)
+
+// Built-in link cost metrics
+const (
+	LatencyCost          = "latency"           // link latency in ms (default)
+	HopCost              = "hops"              // every link costs 1
+	InverseBandwidthCost = "inverse-bandwidth" // ReferenceBandwidth divided by the link bandwidth
+	DistanceCost         = "distance"          // link distance in km
+	WeightedCost         = "weighted"          // weighted sum of other metrics, see RouterConfig.LinkCostWeights
+)
+
+// ReferenceBandwidth is the bandwidth in bits per second costing 1 with the inverse bandwidth metric (as in OSPF).
+const ReferenceBandwidth = 1_000_000_000
+
+var (
+	linkCostsMu sync.RWMutex
+	linkCosts   = map[string]LinkCostFactory{
+		LatencyCost:          func(configs.RouterConfig) (LinkCost, error) { return latencyCost{}, nil },
+		HopCost:              func(configs.RouterConfig) (LinkCost, error) { return hopCost{}, nil },
+		InverseBandwidthCost: func(configs.RouterConfig) (LinkCost, error) { return inverseBandwidthCost{}, nil },
+		DistanceCost:         func(configs.RouterConfig) (LinkCost, error) { return distanceCost{}, nil },
+	}
+)
+
+func init() {
+	// Registered separately as the weighted cost resolves the other costs by name
+	linkCosts[WeightedCost] = newWeightedCost
+}
+
+// RegisterLinkCost makes a custom link cost available under the given name for RouterConfig.LinkCost.
+// It has to be called before the RouterBuilder is created.
+func RegisterLinkCost(name string, factory LinkCostFactory) {
+	linkCostsMu.Lock()
+	defer linkCostsMu.Unlock()
+	linkCosts[strings.ToLower(name)] = factory
+}
+
+// NewLinkCost creates the link cost selected in the router configuration, latency if none is selected.
+func NewLinkCost(cfg configs.RouterConfig) (LinkCost, error) {
+	if cfg.LinkCost == "" {
+		return latencyCost{}, nil
+	}
+	return newLinkCost(cfg.LinkCost, cfg)
+}
+
+func newLinkCost(name string, cfg configs.RouterConfig) (LinkCost, error) {
+	linkCostsMu.RLock()
+	factory, ok := linkCosts[strings.ToLower(name)]
+	linkCostsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown link cost: %s", name)
+	}
+	return factory(cfg)
+}
+
+// LinkCostFunc adapts a function to a LinkCost without estimate, i.e. A* behaves like Dijkstra.
+type LinkCostFunc func(l types.Link, from, to types.Node) float64
+
+// Cost calls the function
+func (f LinkCostFunc) Cost(l types.Link, from, to types.Node) float64 { return f(l, from, to) }
+
+// Estimate returns 0, which is a lower bound for every non-negative cost
+func (f LinkCostFunc) Estimate(from, to types.Vector) float64 { return 0 }
+
+type latencyCost struct{}
+
+func (latencyCost) Cost(l types.Link, _, _ types.Node) float64 { return l.Latency() }
+
+// Estimate is the latency of a straight line at the speed of light, no link is faster
+func (latencyCost) Estimate(from, to types.Vector) float64 {
+	return from.Subtract(to).Magnitude() / configs.SpeedOfLight * 1000
+}
+
+type hopCost struct{}
+
+func (hopCost) Cost(types.Link, types.Node, types.Node) float64 { return 1 }
+
+// Estimate returns 0, the range of links is not known
+func (hopCost) Estimate(types.Vector, types.Vector) float64 { return 0 }
+
+type inverseBandwidthCost struct{}
+
+func (inverseBandwidthCost) Cost(l types.Link, _, _ types.Node) float64 {
+	return ReferenceBandwidth / l.Bandwidth()
+}
+
+// Estimate returns 0, the highest link bandwidth is not known
+func (inverseBandwidthCost) Estimate(types.Vector, types.Vector) float64 { return 0 }
+
+type distanceCost struct{}
+
+func (distanceCost) Cost(l types.Link, _, _ types.Node) float64 { return l.Distance() / 1000 }
+
+// Estimate is the straight line distance, no path is shorter
+func (distanceCost) Estimate(from, to types.Vector) float64 {
+	return from.Subtract(to).Magnitude() / 1000
+}
+
+// weightedCost is the weighted sum of other link costs.
+// The sum of the weighted lower bounds is a lower bound of the weighted sum, so the estimate stays admissible.
+type weightedCost struct {
+	costs   []LinkCost
+	weights []float64
+}
+
+func newWeightedCost(cfg configs.RouterConfig) (LinkCost, error) {
+	if len(cfg.LinkCostWeights) == 0 {
+		return nil, fmt.Errorf("link cost %s requires LinkCostWeights", WeightedCost)
+	}
+
+	// Sort the names so the summation order is deterministic
+	names := make([]string, 0, len(cfg.LinkCostWeights))
+	for name := range cfg.LinkCostWeights {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := &weightedCost{}
+	for _, name := range names {
+		weight := cfg.LinkCostWeights[name]
+		if weight < 0 {
+			return nil, fmt.Errorf("weight of link cost %s must not be negative", name)
+		}
+		if strings.EqualFold(name, WeightedCost) {
+			return nil, fmt.Errorf("link cost %s cannot contain itself", WeightedCost)
+		}
+		cost, err := newLinkCost(name, cfg)
+		if err != nil {
+			return nil, err
+		}
+		w.costs = append(w.costs, cost)
+		w.weights = append(w.weights, weight)
+	}
+	return w, nil
+}
+
+func (w *weightedCost) Cost(l types.Link, from, to types.Node) float64 {
+	sum := 0.0
+	for i, c := range w.costs {
+		sum += w.weights[i] * c.Cost(l, from, to)
+	}
+	return sum
+}
+
+func (w *weightedCost) Estimate(from, to types.Vector) float64 {
+	sum := 0.0
+	for i, c := range w.costs {
+		sum += w.weights[i] * c.Estimate(from, to)
+	}
+	return sum
+}
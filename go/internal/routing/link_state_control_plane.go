@@ -0,0 +1,259 @@
+package routing
+
+import (
+	"container/heap"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+const (
+	lsaHeaderBytes    = 20 // size of an LSA header in bytes (as in OSPF)
+	lsaAdjacencyBytes = 12 // size of a single adjacency entry in bytes
+
+	// relative change of an adjacency's latency since the last LSA which triggers a new LSA
+	lsaLatencyThreshold = 0.1
+)
+
+// LinkStateControlPlane simulates the flooding of link-state advertisements (LSAs) between link-state routers.
+// LSAs travel over the established links and arrive after the link latency, so every router builds its
+// link-state database (LSDB) from information which may be outdated. An LSA is installed once the time of
+// the route queries reaches its arrival, see AdvanceTo.
+type LinkStateControlPlane struct {
+	mu      sync.Mutex // protects the router registry
+	routers []*LinkStateRouter
+	ids     map[types.Node]int
+
+	stepMu  sync.Mutex // protects the flooding state
+	simTime time.Time
+	stats   []LinkStateStepStats
+
+	clockMu sync.Mutex
+	now     time.Time // time of the route queries, at least the simulation time
+}
+
+// LinkStateStepStats describes the control plane activity caused by one simulation step.
+type LinkStateStepStats struct {
+	Time            time.Time     // simulation time of the step
+	OriginatedLSAs  int           // number of LSAs originated due to adjacency changes
+	Transmissions   int           // number of LSAs sent over links
+	ControlBytes    int           // number of bytes sent over links
+	ConvergenceTime time.Duration // time until the last LSA of this step was installed
+}
+
+// linkStateAdvertisement describes the adjacencies of its origin.
+type linkStateAdvertisement struct {
+	Origin      int
+	Seq         uint64
+	Adjacencies []lsaAdjacency
+}
+
+type lsaAdjacency struct {
+	Neighbour int
+	Link      types.Link
+	Latency   float64
+}
+
+// lsaDelivery is an LSA arriving at a router at a given time.
+type lsaDelivery struct {
+	at     time.Time
+	to     int
+	inLink types.Link
+	lsa    *linkStateAdvertisement
+}
+
+type lsaDeliveryQueue []*lsaDelivery
+
+func (q lsaDeliveryQueue) Len() int           { return len(q) }
+func (q lsaDeliveryQueue) Less(i, j int) bool { return q[i].at.Before(q[j].at) }
+func (q lsaDeliveryQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *lsaDeliveryQueue) Push(x interface{}) {
+	*q = append(*q, x.(*lsaDelivery))
+}
+func (q *lsaDeliveryQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return item
+}
+
+// NewLinkStateControlPlane creates an empty control plane.
+func NewLinkStateControlPlane() *LinkStateControlPlane {
+	return &LinkStateControlPlane{
+		ids: make(map[types.Node]int),
+	}
+}
+
+// register adds a mounted link-state router to the control plane.
+func (c *LinkStateControlPlane) register(r *LinkStateRouter) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id := len(c.routers)
+	c.routers = append(c.routers, r)
+	c.ids[r.node] = id
+	return id
+}
+
+// idOf returns the router ID of a node.
+func (c *LinkStateControlPlane) idOf(n types.Node) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id, ok := c.ids[n]
+	return id, ok
+}
+
+// router returns the router with the given ID.
+func (c *LinkStateControlPlane) router(id int) *LinkStateRouter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.routers[id]
+}
+
+// size returns the number of registered routers.
+func (c *LinkStateControlPlane) size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.routers)
+}
+
+// SimulationTime returns the simulation time the control plane has advanced to.
+func (c *LinkStateControlPlane) SimulationTime() time.Time {
+	c.stepMu.Lock()
+	defer c.stepMu.Unlock()
+	return c.simTime
+}
+
+// AdvanceTo moves the time of the route queries forward, LSAs which arrived until then are installed
+// before the next route lookup. Earlier times than the current one are ignored.
+func (c *LinkStateControlPlane) AdvanceTo(t time.Time) {
+	c.clockMu.Lock()
+	defer c.clockMu.Unlock()
+	if t.After(c.now) {
+		c.now = t
+	}
+}
+
+// queryTime returns the time of the route queries.
+func (c *LinkStateControlPlane) queryTime() time.Time {
+	c.clockMu.Lock()
+	defer c.clockMu.Unlock()
+	return c.now
+}
+
+// Stats returns the control plane statistics of all steps so far.
+func (c *LinkStateControlPlane) Stats() []LinkStateStepStats {
+	c.stepMu.Lock()
+	defer c.stepMu.Unlock()
+	out := make([]LinkStateStepStats, len(c.stats))
+	copy(out, c.stats)
+	return out
+}
+
+// Advance moves the control plane to the new simulation time.
+// LSAs which arrived until then are installed, then every router whose adjacencies changed originates
+// a new LSA which is flooded over the current topology. The flooded LSAs are installed at their arrival.
+func (c *LinkStateControlPlane) Advance(simTime time.Time) {
+	c.stepMu.Lock()
+	defer c.stepMu.Unlock()
+
+	c.mu.Lock()
+	routers := make([]*LinkStateRouter, len(c.routers))
+	copy(routers, c.routers)
+	c.mu.Unlock()
+	if len(routers) == 0 {
+		return
+	}
+	c.simTime = simTime
+	c.AdvanceTo(simTime)
+
+	for _, r := range routers {
+		r.installPending(simTime)
+	}
+
+	stats := LinkStateStepStats{Time: simTime}
+	queue := &lsaDeliveryQueue{}
+	for _, r := range routers {
+		adjacencies := c.adjacencies(r)
+		if sameAdjacencies(adjacencies, r.originated) {
+			continue
+		}
+		r.seq++
+		r.originated = adjacencies
+		lsa := &linkStateAdvertisement{Origin: r.id, Seq: r.seq, Adjacencies: adjacencies}
+		r.install(lsa)
+		stats.OriginatedLSAs++
+		c.flood(queue, r, lsa, nil, simTime, &stats)
+	}
+
+	// Run the flooding until all LSAs are delivered
+	last := simTime
+	for queue.Len() > 0 {
+		d := heap.Pop(queue).(*lsaDelivery)
+		r := routers[d.to]
+		if !r.accept(d.lsa, d.at) {
+			continue
+		}
+		if d.at.After(last) {
+			last = d.at
+		}
+		c.flood(queue, r, d.lsa, d.inLink, d.at, &stats)
+	}
+	stats.ConvergenceTime = last.Sub(simTime)
+	c.stats = append(c.stats, stats)
+}
+
+// flood sends the LSA from the router over all its established links except the one it arrived on.
+func (c *LinkStateControlPlane) flood(queue *lsaDeliveryQueue, from *LinkStateRouter, lsa *linkStateAdvertisement, inLink types.Link, at time.Time, stats *LinkStateStepStats) {
+	size := lsaHeaderBytes + lsaAdjacencyBytes*len(lsa.Adjacencies)
+	for _, l := range from.node.GetLinkNodeProtocol().Established() {
+		if l == inLink {
+			continue
+		}
+		to, ok := c.idOf(l.GetOther(from.node))
+		if !ok {
+			continue // neighbour does not take part in link-state routing
+		}
+		stats.Transmissions++
+		stats.ControlBytes += size
+		heap.Push(queue, &lsaDelivery{
+			at:     at.Add(time.Duration(l.Latency() * float64(time.Millisecond))),
+			to:     to,
+			inLink: l,
+			lsa:    lsa,
+		})
+	}
+}
+
+// adjacencies returns the current adjacencies of a router towards other link-state routers.
+func (c *LinkStateControlPlane) adjacencies(r *LinkStateRouter) []lsaAdjacency {
+	var adjacencies []lsaAdjacency
+	for _, l := range r.node.GetLinkNodeProtocol().Established() {
+		if id, ok := c.idOf(l.GetOther(r.node)); ok {
+			adjacencies = append(adjacencies, lsaAdjacency{Neighbour: id, Link: l, Latency: l.Latency()})
+		}
+	}
+	return adjacencies
+}
+
+// sameAdjacencies checks if both lists contain the same links regardless of order, and no latency changed
+// by more than lsaLatencyThreshold.
+func sameAdjacencies(a, b []lsaAdjacency) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	latencies := make(map[types.Link]float64, len(a))
+	for _, adj := range a {
+		latencies[adj.Link] = adj.Latency
+	}
+	for _, adj := range b {
+		latency, ok := latencies[adj.Link]
+		if !ok || math.Abs(latency-adj.Latency) > lsaLatencyThreshold*adj.Latency {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,264 @@
+package routing
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var _ types.ForwardingRouter = (*LinkStateRouter)(nil)
+var _ types.TimedRouter = (*LinkStateRouter)(nil)
+
+// LinkStateRouter implements a distributed link-state routing protocol.
+// Each router computes its routes from its own link-state database, which is filled by the
+// LinkStateControlPlane with the delay of the LSA flooding. Routes are forwarded hop by hop,
+// so while the network has not converged, a route may point over a link which is already down.
+// Service routes are learned from the advertisements propagated by the other routers.
+type LinkStateRouter struct {
+	serviceAdvertiser
+
+	node  types.Node
+	plane *LinkStateControlPlane
+	id    int
+
+	mu         sync.Mutex
+	seq        uint64                          // sequence number of the last originated LSA
+	originated []lsaAdjacency                  // adjacencies of the last originated LSA
+	lsdb       map[int]*linkStateAdvertisement // installed LSAs by origin
+	known      map[int]uint64                  // highest sequence number seen by origin
+	pending    []pendingLSA                    // received LSAs which are installed in the future
+	dirty      bool                            // lsdb changed since the last SPF calculation
+	dist       []float64                       // SPF result: latency by router ID
+	firstHop   []int32                         // SPF result: neighbour router ID by router ID
+	firstLink  []types.Link                    // SPF result: outgoing link by router ID
+}
+
+type pendingLSA struct {
+	at  time.Time
+	lsa *linkStateAdvertisement
+}
+
+// NewLinkStateRouter creates a new link-state router attached to the given control plane
+func NewLinkStateRouter(plane *LinkStateControlPlane, adverts *ServiceAdvertisementPlane) *LinkStateRouter {
+	return &LinkStateRouter{
+		serviceAdvertiser: newServiceAdvertiser(adverts),
+		plane:             plane,
+		lsdb:              make(map[int]*linkStateAdvertisement),
+		known:             make(map[int]uint64),
+		dirty:             true,
+	}
+}
+
+// Mount attaches the router to a node and registers it at the control plane
+func (r *LinkStateRouter) Mount(node types.Node) error {
+	if r.node != nil {
+		return errors.New("router already mounted")
+	}
+	r.node = node
+	r.id = r.plane.register(r)
+	r.mount(node)
+	return nil
+}
+
+// AdvanceTo moves the time of the route queries of the control plane, see LinkStateControlPlane.AdvanceTo
+func (r *LinkStateRouter) AdvanceTo(t time.Time) {
+	r.plane.AdvanceTo(t)
+}
+
+// CanPreRouteCalc returns true, the SPF calculation can be done ahead of time
+func (r *LinkStateRouter) CanPreRouteCalc() bool { return true }
+
+// CanOnRouteCalc returns true, the SPF calculation is done on demand if the LSDB changed
+func (r *LinkStateRouter) CanOnRouteCalc() bool { return true }
+
+// CalculateRoutingTable runs the SPF calculation on the local LSDB if it changed
+func (r *LinkStateRouter) CalculateRoutingTable() error {
+	if r.node == nil {
+		return errors.New("router not mounted")
+	}
+	now := r.plane.queryTime()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.installArrived(now)
+	r.calculateSpf()
+	return nil
+}
+
+// RouteToNode forwards hop by hop towards the target, every hop deciding on its own LSDB.
+// The route is unreachable if a hop has no route, forwards over a link which is down, or a loop occurs.
+func (r *LinkStateRouter) RouteToNode(target types.Node, payload types.Payload) (types.RouteResult, error) {
+	if r.node == nil {
+		return nil, errors.New("router not mounted")
+	}
+	if r.node == target {
+		return NewPreRouteResult(0), nil
+	}
+
+	targetID, ok := r.plane.idOf(target)
+	if !ok {
+		return UnreachableRouteResultInstance, nil
+	}
+
+	latency := 0.0
+	visited := map[int]bool{r.id: true}
+	current := r
+	for current.id != targetID {
+		next, link, ok := current.nextHop(targetID)
+		if !ok || !isEstablished(current.node, link) {
+			return UnreachableRouteResultInstance, nil
+		}
+		latency += link.Latency()
+		if visited[next] {
+			return UnreachableRouteResultInstance, nil
+		}
+		visited[next] = true
+		current = r.plane.router(next)
+	}
+	return NewOnRouteResult(int(latency), 0), nil
+}
+
+// RouteToService forwards hop by hop to the closest replica of the service advertised to this router
+func (r *LinkStateRouter) RouteToService(serviceName string, payload types.Payload) (types.RouteResult, error) {
+	if r.node == nil {
+		return nil, errors.New("router not mounted")
+	}
+	if r.node.GetComputing().HostsService(serviceName) {
+		return NewPreRouteResult(0), nil
+	}
+
+	route, ok := r.bestServiceRoute(serviceName, payload)
+	if !ok {
+		return UnreachableRouteResultInstance, nil
+	}
+	result, err := r.RouteToNode(route.Origin, payload)
+	return replicaResult(result, err, route.Origin)
+}
+
+// NextHop returns the outgoing link towards the target according to the local LSDB,
+// or nil if the LSDB has no path or the link is not established anymore
+func (r *LinkStateRouter) NextHop(target types.Node, payload types.Payload) (types.Link, error) {
+	if r.node == nil {
+		return nil, errors.New("router not mounted")
+	}
+	targetID, ok := r.plane.idOf(target)
+	if !ok || targetID == r.id {
+		return nil, nil
+	}
+	_, link, ok := r.nextHop(targetID)
+	if !ok || !isEstablished(r.node, link) {
+		return nil, nil
+	}
+	return link, nil
+}
+
+// nextHop returns the neighbour and outgoing link towards the target according to the local LSDB.
+func (r *LinkStateRouter) nextHop(target int) (int, types.Link, bool) {
+	now := r.plane.queryTime()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.installArrived(now)
+	r.calculateSpf()
+	if target >= len(r.firstHop) || r.firstHop[target] < 0 {
+		return 0, nil, false
+	}
+	return int(r.firstHop[target]), r.firstLink[target], true
+}
+
+// accept records a received LSA if it is newer than everything seen so far from its origin.
+func (r *LinkStateRouter) accept(lsa *linkStateAdvertisement, at time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if seq, ok := r.known[lsa.Origin]; ok && seq >= lsa.Seq {
+		return false
+	}
+	r.known[lsa.Origin] = lsa.Seq
+	r.pending = append(r.pending, pendingLSA{at: at, lsa: lsa})
+	return true
+}
+
+// install puts an LSA into the LSDB immediately.
+func (r *LinkStateRouter) install(lsa *linkStateAdvertisement) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.known[lsa.Origin] = lsa.Seq
+	r.lsdb[lsa.Origin] = lsa
+	r.dirty = true
+}
+
+// installPending installs all received LSAs which arrived until the given time.
+func (r *LinkStateRouter) installPending(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.installArrived(now)
+}
+
+// installArrived installs all received LSAs which arrived until the given time. The caller must hold the lock.
+func (r *LinkStateRouter) installArrived(now time.Time) {
+	remaining := r.pending[:0]
+	for _, p := range r.pending {
+		if p.at.After(now) {
+			remaining = append(remaining, p)
+			continue
+		}
+		if installed, ok := r.lsdb[p.lsa.Origin]; !ok || installed.Seq < p.lsa.Seq {
+			r.lsdb[p.lsa.Origin] = p.lsa
+			r.dirty = true
+		}
+	}
+	r.pending = remaining
+}
+
+// calculateSpf runs Dijkstra's algorithm over the LSDB. The caller must hold the lock.
+func (r *LinkStateRouter) calculateSpf() {
+	n := r.plane.size()
+	if !r.dirty && len(r.dist) == n {
+		return
+	}
+
+	r.dist = make([]float64, n)
+	r.firstHop = make([]int32, n)
+	r.firstLink = make([]types.Link, n)
+	for i := range r.dist {
+		r.dist[i] = math.Inf(1)
+		r.firstHop[i] = -1
+	}
+
+	h := newNodeHeap(n)
+	r.dist[r.id] = 0
+	h.PushOrDecrease(r.id, 0)
+	for h.Len() > 0 {
+		u := h.Pop()
+		lsa, ok := r.lsdb[u]
+		if !ok {
+			continue
+		}
+		for _, adj := range lsa.Adjacencies {
+			alt := r.dist[u] + adj.Latency
+			if alt < r.dist[adj.Neighbour] {
+				r.dist[adj.Neighbour] = alt
+				if u == r.id {
+					r.firstHop[adj.Neighbour] = int32(adj.Neighbour)
+					r.firstLink[adj.Neighbour] = adj.Link
+				} else {
+					r.firstHop[adj.Neighbour] = r.firstHop[u]
+					r.firstLink[adj.Neighbour] = r.firstLink[u]
+				}
+				h.PushOrDecrease(adj.Neighbour, alt)
+			}
+		}
+	}
+	r.dirty = false
+}
+
+// isEstablished checks if the link is currently established at the node.
+func isEstablished(node types.Node, link types.Link) bool {
+	for _, l := range node.GetLinkNodeProtocol().Established() {
+		if l == link {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,238 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// Multicast tree algorithms
+const (
+	MulticastShortestPathTree = "shortest-path-tree" // union of the shortest paths from the source
+	MulticastSteiner          = "steiner"            // Steiner tree approximation, fewer links at the price of longer paths
+)
+
+// MulticastEdge is a link of a multicast tree directed away from the source.
+type MulticastEdge struct {
+	From types.Node
+	To   types.Node
+	Link types.Link
+}
+
+// MulticastTree delivers data from the source to a set of destinations, sending it over every link of the tree once.
+type MulticastTree struct {
+	Source       types.Node
+	Algorithm    string
+	Edges        []MulticastEdge        // links of the tree, every edge follows the edge reaching its From node
+	Latencies    map[types.Node]float64 // latency in ms along the tree by reachable destination
+	Unreachable  []types.Node           // destinations without a path from the source
+	UnicastLinks int                    // links used if every destination were served by its own shortest path
+}
+
+// LinkCount returns the number of links used by the tree.
+func (t *MulticastTree) LinkCount() int {
+	return len(t.Edges)
+}
+
+// TotalLatency returns the sum of the latencies of all links of the tree in ms.
+func (t *MulticastTree) TotalLatency() float64 {
+	sum := 0.0
+	for _, e := range t.Edges {
+		sum += e.Link.Latency()
+	}
+	return sum
+}
+
+// MaxLatency returns the highest latency to a reachable destination in ms.
+func (t *MulticastTree) MaxLatency() float64 {
+	maxLatency := 0.0
+	for _, latency := range t.Latencies {
+		maxLatency = max(maxLatency, latency)
+	}
+	return maxLatency
+}
+
+// Latency returns the latency to the destination along the tree.
+func (t *MulticastTree) Latency(destination types.Node) (float64, bool) {
+	latency, ok := t.Latencies[destination]
+	return latency, ok
+}
+
+// LinkSavings returns the share of link transmissions saved compared to unicast, e.g. 0.6 for 60%.
+func (t *MulticastTree) LinkSavings() float64 {
+	if t.UnicastLinks == 0 {
+		return 0
+	}
+	return 1 - float64(t.LinkCount())/float64(t.UnicastLinks)
+}
+
+// MulticastTree computes a tree from the source to the destinations on the current topology.
+// Trees minimize the link cost of the engine, the latencies are measured along the tree.
+func (e *RoutingEngine) MulticastTree(source types.Node, destinations []types.Node, algorithm string) (*MulticastTree, error) {
+	spt, err := e.TreeFrom(source)
+	if err != nil {
+		return nil, err
+	}
+	s := spt.snapshot
+
+	tree := &MulticastTree{
+		Source:    source,
+		Algorithm: strings.ToLower(algorithm),
+		Latencies: make(map[types.Node]float64),
+	}
+	targets := make([]int, 0, len(destinations))
+	seen := make(map[int]bool)
+	for _, d := range destinations {
+		id, ok := s.IndexOf(d)
+		switch {
+		case !ok || math.IsInf(spt.dist[id], 1):
+			tree.Unreachable = append(tree.Unreachable, d)
+		case !seen[id]:
+			seen[id] = true
+			targets = append(targets, id)
+			tree.UnicastLinks += spt.hops(id)
+		}
+	}
+
+	var b *treeBuilder
+	switch tree.Algorithm {
+	case "", MulticastShortestPathTree:
+		tree.Algorithm = MulticastShortestPathTree
+		b = shortestPathMulticast(spt, targets)
+	case MulticastSteiner:
+		b = steinerMulticast(s, spt.source, targets, newNodeHeap(s.Len()))
+	default:
+		return nil, fmt.Errorf("unknown multicast algorithm: %s", algorithm)
+	}
+
+	latency := make(map[int]float64, len(b.order)+1)
+	latency[spt.source] = 0
+	for _, id := range b.order {
+		edge := s.edges[b.parentEdge[id]]
+		from := s.edgeSource(int(b.parentEdge[id]))
+		latency[id] = latency[from] + edge.Latency
+		tree.Edges = append(tree.Edges, MulticastEdge{From: s.Node(from), To: s.Node(id), Link: edge.Link})
+	}
+	for _, id := range targets {
+		tree.Latencies[s.Node(id)] = latency[id]
+	}
+	return tree, nil
+}
+
+// BroadcastTree computes a tree from the source to all other registered nodes on the current topology.
+func (e *RoutingEngine) BroadcastTree(source types.Node, algorithm string) (*MulticastTree, error) {
+	nodes := e.Snapshot().Nodes()
+	destinations := make([]types.Node, 0, len(nodes))
+	for _, n := range nodes {
+		if n != source {
+			destinations = append(destinations, n)
+		}
+	}
+	if len(destinations) == len(nodes) {
+		return nil, errors.New("node is not registered at routing engine")
+	}
+	return e.MulticastTree(source, destinations, algorithm)
+}
+
+// treeBuilder collects the nodes of a tree with the edges reaching them.
+type treeBuilder struct {
+	inTree     []bool
+	parentEdge []int32 // snapshot edge reaching the node
+	order      []int   // nodes except the root, every node after its parent
+}
+
+func newTreeBuilder(n, root int) *treeBuilder {
+	b := &treeBuilder{
+		inTree:     make([]bool, n),
+		parentEdge: make([]int32, n),
+	}
+	b.inTree[root] = true
+	return b
+}
+
+// attach adds the branch ending at id, following prev and prevEdge back to a node of the tree.
+// It returns the nodes added, parents first.
+func (b *treeBuilder) attach(id int, prev, prevEdge []int32) []int {
+	start := len(b.order)
+	for ; !b.inTree[id]; id = int(prev[id]) {
+		b.inTree[id] = true
+		b.parentEdge[id] = prevEdge[id]
+		b.order = append(b.order, id)
+	}
+	added := b.order[start:]
+	reverseInts(added)
+	return added
+}
+
+// shortestPathMulticast joins the shortest paths from the source to the targets.
+func shortestPathMulticast(t *ShortestPathTree, targets []int) *treeBuilder {
+	b := newTreeBuilder(t.snapshot.Len(), t.source)
+	for _, id := range targets {
+		b.attach(id, t.prev, t.prevEdge)
+	}
+	return b
+}
+
+// steinerMulticast builds a Steiner tree with the heuristic of Takahashi and Matsuyama:
+// the target closest to the tree is attached along its shortest path until all targets are connected.
+// The distances to the tree are updated incrementally by a Dijkstra search from the newly attached nodes.
+func steinerMulticast(s *TopologySnapshot, src int, targets []int, h *nodeHeap) *treeBuilder {
+	n := s.Len()
+	b := newTreeBuilder(n, src)
+	dist := make([]float64, n)
+	prev := make([]int32, n)
+	prevEdge := make([]int32, n)
+	for i := range dist {
+		dist[i] = math.Inf(1)
+		prev[i] = -1
+		prevEdge[i] = -1
+	}
+
+	h.Reset()
+	dist[src] = 0
+	h.PushOrDecrease(src, 0)
+	remaining := append([]int(nil), targets...)
+	for len(remaining) > 0 {
+		for h.Len() > 0 {
+			u := h.Pop()
+			base := s.offsets[u]
+			for i, edge := range s.Edges(u) {
+				if alt := dist[u] + edge.Cost; alt < dist[edge.To] {
+					dist[edge.To] = alt
+					prev[edge.To] = int32(u)
+					prevEdge[edge.To] = int32(base + i)
+					h.PushOrDecrease(edge.To, alt)
+				}
+			}
+		}
+
+		closest := 0
+		for i, id := range remaining {
+			if dist[id] < dist[remaining[closest]] {
+				closest = i
+			}
+		}
+		id := remaining[closest]
+		remaining[closest] = remaining[len(remaining)-1]
+		remaining = remaining[:len(remaining)-1]
+
+		// Attached nodes are part of the tree and have distance 0, so their branch never changes
+		for _, added := range b.attach(id, prev, prevEdge) {
+			dist[added] = 0
+			h.PushOrDecrease(added, 0)
+		}
+	}
+	return b
+}
+
+// hops returns the number of links on the path from the source to the node.
+func (t *ShortestPathTree) hops(id int) int {
+	hops := 0
+	for ; id != t.source && t.prev[id] >= 0; id = int(t.prev[id]) {
+		hops++
+	}
+	return hops
+}
@@ -0,0 +1,104 @@
+package routing
+
+// nodeHeap is an indexed binary min-heap over dense node IDs.
+// It supports decrease-key, so every node is contained at most once and no allocations
+// happen while a search is running.
+type nodeHeap struct {
+	items    []int     // heap of node IDs
+	priority []float64 // priority by node ID
+	position []int     // position in items by node ID, -1 if not contained
+}
+
+// newNodeHeap creates a heap able to hold the node IDs 0..size-1.
+func newNodeHeap(size int) *nodeHeap {
+	h := &nodeHeap{
+		items:    make([]int, 0, size),
+		priority: make([]float64, size),
+		position: make([]int, size),
+	}
+	for i := range h.position {
+		h.position[i] = -1
+	}
+	return h
+}
+
+// Len returns the number of queued nodes.
+func (h *nodeHeap) Len() int {
+	return len(h.items)
+}
+
+// PushOrDecrease inserts the node or lowers its priority if it is already queued with a higher one.
+func (h *nodeHeap) PushOrDecrease(id int, priority float64) {
+	if pos := h.position[id]; pos >= 0 {
+		if priority < h.priority[id] {
+			h.priority[id] = priority
+			h.up(pos)
+		}
+		return
+	}
+	h.priority[id] = priority
+	h.position[id] = len(h.items)
+	h.items = append(h.items, id)
+	h.up(len(h.items) - 1)
+}
+
+// Pop removes and returns the node with the lowest priority.
+func (h *nodeHeap) Pop() int {
+	top := h.items[0]
+	last := len(h.items) - 1
+	h.swap(0, last)
+	h.items = h.items[:last]
+	h.position[top] = -1
+	if last > 0 {
+		h.down(0)
+	}
+	return top
+}
+
+// Reset empties the heap so it can be reused for another search.
+func (h *nodeHeap) Reset() {
+	for _, id := range h.items {
+		h.position[id] = -1
+	}
+	h.items = h.items[:0]
+}
+
+func (h *nodeHeap) less(i, j int) bool {
+	return h.priority[h.items[i]] < h.priority[h.items[j]]
+}
+
+func (h *nodeHeap) swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.position[h.items[i]] = i
+	h.position[h.items[j]] = j
+}
+
+func (h *nodeHeap) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(i, parent) {
+			break
+		}
+		h.swap(i, parent)
+		i = parent
+	}
+}
+
+func (h *nodeHeap) down(i int) {
+	n := len(h.items)
+	for {
+		left := 2*i + 1
+		if left >= n {
+			break
+		}
+		smallest := left
+		if right := left + 1; right < n && h.less(right, left) {
+			smallest = right
+		}
+		if !h.less(smallest, i) {
+			break
+		}
+		h.swap(i, smallest)
+		i = smallest
+	}
+}
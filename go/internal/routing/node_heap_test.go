@@ -0,0 +1,42 @@
+package routing
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNodeHeap(t *testing.T) {
+	type push struct {
+		id       int
+		priority float64
+	}
+	tests := []struct {
+		name   string
+		pushes []push
+		want   []int
+	}{
+		{"empty", nil, nil},
+		{"ordered by priority", []push{{0, 3}, {1, 1}, {2, 2}, {3, 0}}, []int{3, 1, 2, 0}},
+		{"decrease", []push{{0, 3}, {1, 1}, {2, 2}, {0, 0.5}}, []int{0, 1, 2}},
+		{"no increase", []push{{0, 1}, {1, 2}, {0, 5}}, []int{0, 1}},
+	}
+	h := newNodeHeap(4)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h.Reset()
+			for _, p := range tt.pushes {
+				h.PushOrDecrease(p.id, p.priority)
+			}
+			if h.Len() != len(tt.want) {
+				t.Errorf("len = %d, want %d", h.Len(), len(tt.want))
+			}
+			var got []int
+			for h.Len() > 0 {
+				got = append(got, h.Pop())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("pop order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
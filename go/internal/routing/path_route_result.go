@@ -0,0 +1,24 @@
+package routing
+
+import "github.com/keniack/stardustGo/pkg/types"
+
+var _ types.RouteResult = (*PathRouteResult)(nil)
+
+// PathRouteResult is a route which knows the path it takes through the network.
+type PathRouteResult struct {
+	PreRouteResult
+	path types.Path
+}
+
+// NewPathRouteResult creates a route along the given path
+func NewPathRouteResult(path types.Path) *PathRouteResult {
+	return &PathRouteResult{
+		PreRouteResult: PreRouteResult{latency: int(path.Latency)},
+		path:           path,
+	}
+}
+
+// Path returns the path of the route
+func (r *PathRouteResult) Path() types.Path {
+	return r.path
+}
@@ -0,0 +1,97 @@
+package routing
+
+import (
+	"math"
+	"sort"
+)
+
+// constrainedShortestPath returns the path with the lowest latency from src to dst which only uses
+// links offering at least minBandwidth and, if maxHops > 0, consists of at most maxHops links.
+func constrainedShortestPath(s *TopologySnapshot, src, dst int, minBandwidth float64, maxHops int) (snapshotPath, bool) {
+	if maxHops <= 0 {
+		search := newPathSearch(s)
+		for e, edge := range s.edges {
+			if edge.Bandwidth < minBandwidth {
+				search.banEdge(e)
+			}
+		}
+		return search.shortest(src, dst)
+	}
+	return hopLimitedShortestPath(s, src, dst, minBandwidth, maxHops)
+}
+
+// hopLimitedShortestPath runs maxHops rounds of Bellman-Ford, so round h holds the best paths with at most h links.
+func hopLimitedShortestPath(s *TopologySnapshot, src, dst int, minBandwidth float64, maxHops int) (snapshotPath, bool) {
+	const unchanged = -2 // the node kept the path of the previous round
+
+	n := s.Len()
+	dist := make([]float64, n)
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+	dist[src] = 0
+
+	prevEdge := make([][]int32, maxHops+1) // edge used to reach the node in round h
+	active := []int{src}                   // nodes improved in the previous round
+	for h := 1; h <= maxHops && len(active) > 0; h++ {
+		next := make([]float64, n)
+		copy(next, dist)
+		prevEdge[h] = make([]int32, n)
+		for i := range prevEdge[h] {
+			prevEdge[h][i] = unchanged
+		}
+
+		var improved []int
+		for _, u := range active {
+			base := s.offsets[u]
+			for i, edge := range s.Edges(u) {
+				if edge.Bandwidth < minBandwidth {
+					continue
+				}
+				if alt := dist[u] + edge.Latency; alt < next[edge.To] {
+					if prevEdge[h][edge.To] == unchanged {
+						improved = append(improved, edge.To)
+					}
+					next[edge.To] = alt
+					prevEdge[h][edge.To] = int32(base + i)
+				}
+			}
+		}
+		dist = next
+		active = improved
+	}
+	if math.IsInf(dist[dst], 1) {
+		return snapshotPath{}, false
+	}
+
+	// Walk back through the rounds, skipping rounds in which the node kept its path
+	path := snapshotPath{latency: dist[dst]}
+	h := len(prevEdge) - 1
+	for v := dst; v != src; h-- {
+		if prevEdge[h] == nil || prevEdge[h][v] == unchanged {
+			continue
+		}
+		e := int(prevEdge[h][v])
+		path.nodes = append(path.nodes, v)
+		path.edges = append(path.edges, e)
+		v = s.edgeSource(e)
+	}
+	path.nodes = append(path.nodes, src)
+	reverseInts(path.nodes)
+	reverseInts(path.edges)
+	return path, true
+}
+
+// bandwidthLevels returns the distinct link bandwidths of at least minBandwidth in descending order.
+func bandwidthLevels(s *TopologySnapshot, minBandwidth float64) []float64 {
+	seen := make(map[float64]bool)
+	var levels []float64
+	for _, edge := range s.edges {
+		if edge.Bandwidth >= minBandwidth && !seen[edge.Bandwidth] {
+			seen[edge.Bandwidth] = true
+			levels = append(levels, edge.Bandwidth)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(levels)))
+	return levels
+}
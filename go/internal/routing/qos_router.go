@@ -0,0 +1,168 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var _ types.Router = (*QosRouter)(nil)
+
+// Path selection modes of the QoS router
+const (
+	QosLowestLatency            = "latency"                    // feasible path with the lowest latency
+	QosWidest                   = "widest"                     // path with the highest bottleneck bandwidth, ignoring the maximum latency
+	QosLatencyConstrainedWidest = "latency-constrained-widest" // widest path within the maximum latency
+)
+
+// QosRouter routes along paths fulfilling the quality of service requirements of the payload
+// (types.QosPayload): minimum bandwidth, maximum latency and maximum hop count.
+// If no feasible path exists, the route is unreachable with the violated requirement as reason.
+// Service routes are learned from the advertisements propagated by the other routers.
+type QosRouter struct {
+	serviceAdvertiser
+
+	node   types.Node
+	engine *RoutingEngine
+	mode   string
+}
+
+// NewQosRouter creates a new QoS router using the given path selection mode.
+// An empty mode selects the path with the lowest latency.
+func NewQosRouter(engine *RoutingEngine, adverts *ServiceAdvertisementPlane, mode string) (*QosRouter, error) {
+	mode = strings.ToLower(mode)
+	switch mode {
+	case "":
+		mode = QosLowestLatency
+	case QosLowestLatency, QosWidest, QosLatencyConstrainedWidest:
+	default:
+		return nil, fmt.Errorf("unknown qos mode: %s", mode)
+	}
+	return &QosRouter{
+		serviceAdvertiser: newServiceAdvertiser(adverts),
+		engine:            engine,
+		mode:              mode,
+	}, nil
+}
+
+// Mount attaches the router to a node
+func (r *QosRouter) Mount(node types.Node) error {
+	if r.node != nil {
+		return errors.New("router already mounted")
+	}
+	r.node = node
+	r.engine.Register(node)
+	r.mount(node)
+	return nil
+}
+
+// CanPreRouteCalc returns false, paths depend on the requirements of each payload
+func (r *QosRouter) CanPreRouteCalc() bool { return false }
+
+// CanOnRouteCalc returns true
+func (r *QosRouter) CanOnRouteCalc() bool { return true }
+
+// CalculateRoutingTable is a no-op, paths are calculated per payload
+func (r *QosRouter) CalculateRoutingTable() error {
+	return nil
+}
+
+// RouteToNode returns the best path to the target fulfilling the requirements of the payload
+func (r *QosRouter) RouteToNode(target types.Node, payload types.Payload) (types.RouteResult, error) {
+	if r.node == nil {
+		return nil, errors.New("router not mounted")
+	}
+	if r.node == target {
+		return NewPreRouteResult(0), nil
+	}
+
+	var req types.QosRequirements
+	if qos, ok := payload.(types.QosPayload); ok {
+		req = qos.Requirements()
+	}
+
+	snapshot := r.engine.Snapshot()
+	src, ok := snapshot.IndexOf(r.node)
+	if !ok {
+		return nil, errors.New("node is not registered at routing engine")
+	}
+	dst, ok := snapshot.IndexOf(target)
+	if !ok {
+		return UnreachableRouteResultInstance, nil
+	}
+
+	path, reason := r.findPath(snapshot, src, dst, req)
+	if reason != "" {
+		return NewUnreachableRouteResult(reason), nil
+	}
+	return NewPathRouteResult(path.toPath(snapshot)), nil
+}
+
+// RouteToService routes to the closest replica of the service advertised to this router
+func (r *QosRouter) RouteToService(serviceName string, payload types.Payload) (types.RouteResult, error) {
+	if r.node == nil {
+		return nil, errors.New("router not mounted")
+	}
+	if r.node.GetComputing().HostsService(serviceName) {
+		return NewPreRouteResult(0), nil
+	}
+
+	route, ok := r.bestServiceRoute(serviceName, payload)
+	if !ok {
+		return UnreachableRouteResultInstance, nil
+	}
+	return r.RouteToNode(route.Origin, payload)
+}
+
+// findPath selects the path according to the mode, or returns why no feasible path exists.
+func (r *QosRouter) findPath(s *TopologySnapshot, src, dst int, req types.QosRequirements) (snapshotPath, string) {
+	levels := []float64{req.MinBandwidth}
+	if r.mode != QosLowestLatency {
+		levels = bandwidthLevels(s, req.MinBandwidth)
+	}
+	if r.mode == QosWidest {
+		req.MaxLatency = 0
+	}
+
+	// Try the bandwidth levels from the widest down, the first feasible path is the widest one.
+	// In the latency-constrained mode a path exceeding the maximum latency may have a narrower alternative within it.
+	for _, bandwidth := range levels {
+		path, ok := constrainedShortestPath(s, src, dst, bandwidth, req.MaxHops)
+		if !ok || !withinLatency(path, req) {
+			continue
+		}
+		return path, ""
+	}
+	return snapshotPath{}, r.diagnose(s, src, dst, req)
+}
+
+// diagnose finds the requirement which makes the target unreachable.
+func (r *QosRouter) diagnose(s *TopologySnapshot, src, dst int, req types.QosRequirements) string {
+	if _, ok := constrainedShortestPath(s, src, dst, 0, 0); !ok {
+		return "no route to target"
+	}
+	path, ok := constrainedShortestPath(s, src, dst, req.MinBandwidth, 0)
+	if !ok {
+		return fmt.Sprintf("no path with a bandwidth of at least %.0f bit/s", req.MinBandwidth)
+	}
+	if req.MaxHops > 0 && path.hops() > req.MaxHops {
+		hopPath, ok := constrainedShortestPath(s, src, dst, req.MinBandwidth, req.MaxHops)
+		if !ok {
+			return fmt.Sprintf("no path within %d hops", req.MaxHops)
+		}
+		path = hopPath
+	}
+	return fmt.Sprintf("latency of %.2f ms exceeds the maximum of %.2f ms", path.latency, req.MaxLatency)
+}
+
+// withinLatency checks the maximum latency requirement.
+func withinLatency(p snapshotPath, req types.QosRequirements) bool {
+	return req.MaxLatency <= 0 || p.latency <= req.MaxLatency
+}
+
+// hops returns the number of links of the path.
+func (p snapshotPath) hops() int {
+	return len(p.edges)
+}
@@ -0,0 +1,46 @@
+package routing
+
+import (
+	"math"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// ResultLatency returns the most exact latency in ms the route result knows, infinite if unreachable.
+func ResultLatency(result types.RouteResult) float64 {
+	if result == nil || !result.Reachable() {
+		return math.Inf(1)
+	}
+	switch r := result.(type) {
+	case *PathRouteResult:
+		return r.Path().Latency
+	case *ServiceRouteResult:
+		return r.ExactLatency()
+	}
+	return float64(result.Latency())
+}
+
+// RouteLatency returns the latency in ms of the route the router of from calculates to to, infinite if unreachable.
+func RouteLatency(from, to types.Node) float64 {
+	if from == to {
+		return 0
+	}
+	result, err := from.GetRouter().RouteToNode(to, nil)
+	if err != nil {
+		return math.Inf(1)
+	}
+	return ResultLatency(result)
+}
+
+// ServiceLatency returns the latency in ms of the route the router of the node calculates to the service,
+// infinite if unreachable. The route is probed, so it does not count as a request to the replicas.
+func ServiceLatency(n types.Node, service string) float64 {
+	if n.GetComputing().HostsService(service) {
+		return 0
+	}
+	result, err := n.GetRouter().RouteToService(service, types.RouteProbe{})
+	if err != nil {
+		return math.Inf(1)
+	}
+	return ResultLatency(result)
+}
@@ -3,36 +3,188 @@ package routing
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/keniack/stardustGo/internal/geo"
 	"github.com/keniack/stardustGo/pkg/types"
 
 	"github.com/keniack/stardustGo/configs"
 )
 
 // RouterBuilder constructs routers based on configuration.
+// All routers built by the same builder share one RoutingEngine.
 type RouterBuilder struct {
-	Config configs.RouterConfig
+	Config    configs.RouterConfig
+	engine    *RoutingEngine
+	linkState *LinkStateControlPlane
+	adverts   *ServiceAdvertisementPlane
+	contacts  *ContactGraph
+	fence     *GeoFence
+	anycast   *AnycastGroups
+	err       error // configuration error reported by Build
 }
 
 // Supported routing strategies
 const (
-	Dijkstra = "dijkstra"
-	AStar    = "a-star"
+	Dijkstra            = "dijkstra"
+	AStar               = "a-star"
+	LinkState           = "link-state"
+	KShortestPaths      = "k-shortest-paths"
+	ContactGraphRouting = "contact-graph"
+	Qos                 = "qos"
+	GeoFenced           = "geo-fenced"
+
+	// DefaultRouter selects the protocol of the router configuration
+	DefaultRouter = "default"
 )
 
 // NewRouterBuilder creates a new builder using the provided config.
 func NewRouterBuilder(cfg configs.RouterConfig) *RouterBuilder {
-	return &RouterBuilder{Config: cfg}
+	cost, err := NewLinkCost(cfg)
+	engine := NewRoutingEngine(cost)
+
+	var regions *geo.Regions
+	if cfg.Regions != "" && err == nil {
+		regions, err = geo.LoadGeoJSON(cfg.Regions)
+	}
+	anycast, anycastErr := NewAnycastGroups(engine, cfg.AnycastGroups)
+	if err == nil {
+		err = anycastErr
+	}
+	adverts := NewServiceAdvertisementPlane()
+	if cfg.ServiceBalancing != nil {
+		var balancerErr error
+		adverts.balancer, balancerErr = NewServiceBalancer(*cfg.ServiceBalancing)
+		if err == nil {
+			err = balancerErr
+		}
+	}
+
+	return &RouterBuilder{
+		Config:    cfg,
+		engine:    engine,
+		linkState: NewLinkStateControlPlane(),
+		adverts:   adverts,
+		contacts:  NewContactGraph(),
+		fence:     NewGeoFence(regions),
+		anycast:   anycast,
+		err:       err,
+	}
+}
+
+// Engine returns the routing engine shared by all routers of this builder.
+func (b *RouterBuilder) Engine() *RoutingEngine {
+	return b.engine
+}
+
+// LinkStateControlPlane returns the control plane shared by all link-state routers of this builder.
+func (b *RouterBuilder) LinkStateControlPlane() *LinkStateControlPlane {
+	return b.linkState
+}
+
+// ContactGraph returns the contact graph shared by all contact graph routers of this builder.
+// The precomputed mode sets the contact plan of the whole simulation on it.
+func (b *RouterBuilder) ContactGraph() *ContactGraph {
+	return b.contacts
+}
+
+// GeoFence returns the geo fence locating nodes in the configured regions.
+// Its epoch has to be set to the simulation start time.
+func (b *RouterBuilder) GeoFence() *GeoFence {
+	return b.fence
+}
+
+// Anycast returns the anycast groups resolved on the routing engine of this builder.
+func (b *RouterBuilder) Anycast() *AnycastGroups {
+	return b.anycast
+}
+
+// ServiceBalancer returns the balancer selecting the replicas of service requests, nil if not configured.
+func (b *RouterBuilder) ServiceBalancer() *ServiceBalancer {
+	return b.adverts.balancer
+}
+
+// UpdateTopology informs the shared routing state that the established links changed.
+// The simulation calls it once per step after all links were updated.
+func (b *RouterBuilder) UpdateTopology(simTime time.Time) {
+	b.engine.Invalidate()
+	b.linkState.Advance(simTime)
+	b.contacts.Advance(simTime)
+	b.fence.Advance(simTime)
+	b.adverts.Refresh()
 }
 
 // Build creates an IRouter implementation based on config.
 func (b *RouterBuilder) Build() (types.Router, error) {
-	switch strings.ToLower(b.Config.Protocol) {
+	return b.BuildProtocol(DefaultRouter)
+}
+
+// BuildProtocol creates a router of the given protocol, e.g. selected per node.
+// An empty protocol or DefaultRouter builds the protocol of the router configuration.
+// All routers share the state of this builder, independent of their protocol.
+func (b *RouterBuilder) BuildProtocol(protocol string) (types.Router, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if protocol == "" || strings.EqualFold(protocol, DefaultRouter) {
+		protocol = b.Config.Protocol
+	}
+	switch strings.ToLower(protocol) {
 	case Dijkstra:
-		return NewDijkstraRouter(), nil
+		return NewDijkstraRouter(b.engine, b.adverts), nil
 	case AStar:
-		return NewAStarRouter(), nil
+		return NewAStarRouter(b.engine, b.adverts), nil
+	case LinkState:
+		return NewLinkStateRouter(b.linkState, b.adverts), nil
+	case KShortestPaths:
+		return NewKShortestPathsRouter(b.engine, b.adverts, b.Config.PathCount, b.Config.EcmpTolerance), nil
+	case ContactGraphRouting:
+		return NewContactGraphRouter(b.engine, b.contacts, b.adverts), nil
+	case Qos:
+		router, err := NewQosRouter(b.engine, b.adverts, b.Config.QosMode)
+		if err != nil {
+			return nil, err
+		}
+		return router, nil
+	case GeoFenced:
+		return NewGeoFencedRouter(b.engine, b.fence, b.adverts), nil
+	default:
+		return nil, fmt.Errorf("unknown routing protocol: %s", protocol)
+	}
+}
+
+// RequiresContactPlan reports if the router configuration or one of the router selectors of the satellites
+// uses contact graph routing, which needs the contact plan of a precomputed simulation state.
+func RequiresContactPlan(cfg configs.RouterConfig, selectors []configs.RouterSelector) bool {
+	if strings.EqualFold(cfg.Protocol, ContactGraphRouting) {
+		return true
+	}
+	for _, sel := range selectors {
+		if strings.EqualFold(sel.Router, ContactGraphRouting) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProtocolOf returns the protocol name of a router built by a RouterBuilder, or an empty string if unknown.
+func ProtocolOf(router types.Router) string {
+	switch router.(type) {
+	case *DijkstraRouter:
+		return Dijkstra
+	case *AStarRouter:
+		return AStar
+	case *LinkStateRouter:
+		return LinkState
+	case *KShortestPathsRouter:
+		return KShortestPaths
+	case *ContactGraphRouter:
+		return ContactGraphRouting
+	case *QosRouter:
+		return Qos
+	case *GeoFencedRouter:
+		return GeoFenced
 	default:
-		return nil, fmt.Errorf("unknown routing protocol: %s", b.Config.Protocol)
+		return ""
 	}
 }
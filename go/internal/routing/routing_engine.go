@@ -0,0 +1,248 @@
+package routing
+
+import (
+	"errors"
+	"math"
+	"runtime"
+	"sync"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// RoutingEngine computes shortest paths once per simulation step over a shared TopologySnapshot.
+// All routers built by the same RouterBuilder share one engine, so a node's shortest path tree
+// is calculated at most once per step, no matter how many routers read from it.
+// Paths minimize the engine's LinkCost, the reported latency is the latency along these paths.
+type RoutingEngine struct {
+	cost LinkCost
+
+	mu    sync.Mutex
+	nodes []types.Node
+	known map[types.Node]bool
+	state *engineState
+}
+
+// engineState holds everything derived from one topology snapshot.
+type engineState struct {
+	snapshot *TopologySnapshot
+	trees    []*ShortestPathTree
+	once     []sync.Once
+}
+
+// ShortestPathTree holds the result of a single source shortest path search.
+type ShortestPathTree struct {
+	snapshot *TopologySnapshot
+	source   int
+	dist     []float64 // cost by dense node ID, +Inf if unreachable
+	latency  []float64 // latency in ms along the path by dense node ID
+	prev     []int32   // predecessor by dense node ID, -1 for the source and unreachable nodes
+	prevEdge []int32   // index into snapshot edges used to reach the node
+}
+
+// NewRoutingEngine creates an empty routing engine minimizing the given link cost, latency if nil.
+func NewRoutingEngine(cost LinkCost) *RoutingEngine {
+	if cost == nil {
+		cost = latencyCost{}
+	}
+	return &RoutingEngine{
+		cost:  cost,
+		known: make(map[types.Node]bool),
+	}
+}
+
+// LinkCost returns the link cost minimized by the engine.
+func (e *RoutingEngine) LinkCost() LinkCost {
+	return e.cost
+}
+
+// Register adds a node to the set of nodes considered by the engine.
+// Routers register their node when they are mounted.
+func (e *RoutingEngine) Register(n types.Node) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.known[n] {
+		return
+	}
+	e.known[n] = true
+	e.nodes = append(e.nodes, n)
+	e.state = nil
+}
+
+// Invalidate drops the current snapshot and all computed trees.
+// It has to be called whenever the established links changed, i.e. once per simulation step.
+func (e *RoutingEngine) Invalidate() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.state = nil
+}
+
+// Snapshot returns the topology snapshot of the current step, capturing it if necessary.
+func (e *RoutingEngine) Snapshot() *TopologySnapshot {
+	return e.current().snapshot
+}
+
+// TreeFrom returns the shortest path tree rooted at the given node for the current step.
+// The tree is calculated on first use and shared afterwards.
+func (e *RoutingEngine) TreeFrom(n types.Node) (*ShortestPathTree, error) {
+	state := e.current()
+	src, ok := state.snapshot.IndexOf(n)
+	if !ok {
+		return nil, errors.New("node is not registered at routing engine")
+	}
+	state.once[src].Do(func() {
+		state.trees[src] = computeShortestPathTree(state.snapshot, src, newNodeHeap(state.snapshot.Len()))
+	})
+	return state.trees[src], nil
+}
+
+// ComputeAll calculates the shortest path trees of all registered nodes (all-pairs).
+func (e *RoutingEngine) ComputeAll() {
+	e.ComputeFrom(e.current().snapshot.Nodes())
+}
+
+// ComputeFrom calculates the shortest path trees of the given source nodes (many-to-many).
+// The work is spread over a pool of workers, each reusing its own heap.
+func (e *RoutingEngine) ComputeFrom(sources []types.Node) {
+	state := e.current()
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range runtime.GOMAXPROCS(0) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h := newNodeHeap(state.snapshot.Len())
+			for src := range jobs {
+				state.once[src].Do(func() {
+					state.trees[src] = computeShortestPathTree(state.snapshot, src, h)
+				})
+			}
+		}()
+	}
+	for _, n := range sources {
+		if src, ok := state.snapshot.IndexOf(n); ok {
+			jobs <- src
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// current returns the state of the current step, capturing a new snapshot if it was invalidated.
+func (e *RoutingEngine) current() *engineState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.state == nil {
+		snapshot := NewTopologySnapshot(e.nodes, e.cost)
+		e.state = &engineState{
+			snapshot: snapshot,
+			trees:    make([]*ShortestPathTree, snapshot.Len()),
+			once:     make([]sync.Once, snapshot.Len()),
+		}
+	}
+	return e.state
+}
+
+// computeShortestPathTree runs Dijkstra's algorithm on the link costs from src using a binary heap.
+func computeShortestPathTree(s *TopologySnapshot, src int, h *nodeHeap) *ShortestPathTree {
+	n := s.Len()
+	t := &ShortestPathTree{
+		snapshot: s,
+		source:   src,
+		dist:     make([]float64, n),
+		latency:  make([]float64, n),
+		prev:     make([]int32, n),
+		prevEdge: make([]int32, n),
+	}
+	for i := range t.dist {
+		t.dist[i] = math.Inf(1)
+		t.prev[i] = -1
+		t.prevEdge[i] = -1
+	}
+
+	h.Reset()
+	t.dist[src] = 0
+	h.PushOrDecrease(src, 0)
+	for h.Len() > 0 {
+		u := h.Pop()
+		base := s.offsets[u]
+		for i, edge := range s.Edges(u) {
+			alt := t.dist[u] + edge.Cost
+			if alt < t.dist[edge.To] {
+				t.dist[edge.To] = alt
+				t.latency[edge.To] = t.latency[u] + edge.Latency
+				t.prev[edge.To] = int32(u)
+				t.prevEdge[edge.To] = int32(base + i)
+				h.PushOrDecrease(edge.To, alt)
+			}
+		}
+	}
+	return t
+}
+
+// Source returns the root node of the tree.
+func (t *ShortestPathTree) Source() types.Node {
+	return t.snapshot.Node(t.source)
+}
+
+// Reachable returns true if the target can be reached from the source.
+func (t *ShortestPathTree) Reachable(target types.Node) bool {
+	id, ok := t.snapshot.IndexOf(target)
+	return ok && !math.IsInf(t.dist[id], 1)
+}
+
+// Latency returns the latency in ms along the path from the source to the target, or +Inf if unreachable.
+func (t *ShortestPathTree) Latency(target types.Node) float64 {
+	id, ok := t.snapshot.IndexOf(target)
+	if !ok || math.IsInf(t.dist[id], 1) {
+		return math.Inf(1)
+	}
+	return t.latency[id]
+}
+
+// Cost returns the link cost of the path from the source to the target, or +Inf if unreachable.
+func (t *ShortestPathTree) Cost(target types.Node) float64 {
+	id, ok := t.snapshot.IndexOf(target)
+	if !ok {
+		return math.Inf(1)
+	}
+	return t.dist[id]
+}
+
+// FirstHop returns the outgoing link of the source on the path to the target, or nil if there is none.
+func (t *ShortestPathTree) FirstHop(target types.Node) types.Link {
+	id, ok := t.snapshot.IndexOf(target)
+	if !ok || t.prev[id] < 0 {
+		return nil
+	}
+	for int(t.prev[id]) != t.source {
+		id = int(t.prev[id])
+	}
+	return t.snapshot.edges[t.prevEdge[id]].Link
+}
+
+// PathTo returns the nodes and links from the source to the target.
+// Both slices are empty if the target is unreachable.
+func (t *ShortestPathTree) PathTo(target types.Node) ([]types.Node, []types.Link) {
+	id, ok := t.snapshot.IndexOf(target)
+	if !ok || math.IsInf(t.dist[id], 1) {
+		return nil, nil
+	}
+
+	var nodes []types.Node
+	var links []types.Link
+	for id != t.source {
+		nodes = append(nodes, t.snapshot.Node(id))
+		links = append(links, t.snapshot.edges[t.prevEdge[id]].Link)
+		id = int(t.prev[id])
+	}
+	nodes = append(nodes, t.snapshot.Node(t.source))
+
+	// Reverse to get source -> target order
+	for i, j := 0, len(nodes)-1; i < j; i, j = i+1, j-1 {
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+	}
+	for i, j := 0, len(links)-1; i < j; i, j = i+1, j-1 {
+		links[i], links[j] = links[j], links[i]
+	}
+	return nodes, links
+}
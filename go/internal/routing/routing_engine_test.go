@@ -0,0 +1,185 @@
+package routing
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// testNode is a node with fixed links and without router or computing.
+type testNode struct {
+	name     string
+	position types.Vector
+	links    testLinks
+}
+
+func (n *testNode) GetName() string               { return n.name }
+func (n *testNode) GetRouter() types.Router       { return nil }
+func (n *testNode) GetComputing() types.Computing { return nil }
+func (n *testNode) GetPosition() types.Vector     { return n.position }
+func (n *testNode) DistanceTo(other types.Node) float64 {
+	return n.position.Subtract(other.GetPosition()).Magnitude()
+}
+func (n *testNode) UpdatePosition(time.Time)                    {}
+func (n *testNode) GetLinkNodeProtocol() types.LinkNodeProtocol { return &n.links }
+
+// testLinks is the link protocol of a test node, all its links are established.
+type testLinks struct {
+	links []types.Link
+}
+
+func (p *testLinks) Mount(types.Node)                   {}
+func (p *testLinks) ConnectLink(types.Link) error       { return nil }
+func (p *testLinks) DisconnectLink(types.Link) error    { return nil }
+func (p *testLinks) UpdateLinks() ([]types.Link, error) { return p.links, nil }
+func (p *testLinks) Established() []types.Link          { return p.links }
+func (p *testLinks) Links() []types.Link                { return p.links }
+
+// testLink is a link with a fixed latency.
+type testLink struct {
+	a, b    *testNode
+	latency float64
+}
+
+func (l *testLink) Distance() float64  { return l.a.DistanceTo(l.b) }
+func (l *testLink) Latency() float64   { return l.latency }
+func (l *testLink) Bandwidth() float64 { return ReferenceBandwidth }
+func (l *testLink) IsReachable() bool  { return true }
+func (l *testLink) Nodes() (types.Node, types.Node) {
+	return l.a, l.b
+}
+func (l *testLink) GetOther(self types.Node) types.Node {
+	if self == types.Node(l.a) {
+		return l.b
+	}
+	return l.a
+}
+
+// testTopology creates the named nodes and connects them by the links "A-B" with the given latencies.
+func testTopology(names []string, links map[string]float64) []types.Node {
+	byName := make(map[string]*testNode)
+	nodes := make([]types.Node, len(names))
+	for i, name := range names {
+		byName[name] = &testNode{name: name}
+		nodes[i] = byName[name]
+	}
+	for pair, latency := range links {
+		ends := strings.Split(pair, "-")
+		a, b := byName[ends[0]], byName[ends[1]]
+		l := &testLink{a: a, b: b, latency: latency}
+		a.links.links = append(a.links.links, l)
+		b.links.links = append(b.links.links, l)
+	}
+	return nodes
+}
+
+// diamond has the paths A-B-D (2 ms), A-C-B-D (4.5 ms), A-C-D (5 ms) and A-B-C-D (5.5 ms), E is isolated.
+func diamond() []types.Node {
+	return testTopology([]string{"A", "B", "C", "D", "E"}, map[string]float64{
+		"A-B": 1, "B-D": 1, "A-C": 2, "C-D": 3, "B-C": 1.5,
+	})
+}
+
+// torus creates a grid of size x size nodes with wrap-around links, like a +Grid constellation.
+func torus(size int) []types.Node {
+	names := make([]string, 0, size*size)
+	links := make(map[string]float64)
+	name := func(i, j int) string { return fmt.Sprintf("%d/%d", i%size, j%size) }
+	for i := range size {
+		for j := range size {
+			names = append(names, name(i, j))
+			links[name(i, j)+"-"+name(i+1, j)] = 1 + float64((i*7+j*3)%5)
+			links[name(i, j)+"-"+name(i, j+1)] = 1 + float64((i*3+j*7)%5)
+		}
+	}
+	return testTopology(names, links)
+}
+
+func pathNames(nodes []types.Node) string {
+	names := make([]string, len(nodes))
+	for i, n := range nodes {
+		names[i] = n.GetName()
+	}
+	return strings.Join(names, "-")
+}
+
+func TestShortestPathTree(t *testing.T) {
+	nodes := diamond()
+	engine := NewRoutingEngine(nil)
+	for _, n := range nodes {
+		engine.Register(n)
+	}
+	tree, err := engine.TreeFrom(nodes[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		target  int
+		path    string
+		latency float64
+	}{
+		{0, "A", 0},
+		{1, "A-B", 1},
+		{2, "A-C", 2},
+		{3, "A-B-D", 2},
+		{4, "", math.Inf(1)},
+	}
+	for _, tt := range tests {
+		target := nodes[tt.target]
+		t.Run(target.GetName(), func(t *testing.T) {
+			if reachable := tree.Reachable(target); reachable == math.IsInf(tt.latency, 1) {
+				t.Errorf("reachable = %v, want %v", reachable, !reachable)
+			}
+			if got := tree.Latency(target); got != tt.latency {
+				t.Errorf("latency = %v, want %v", got, tt.latency)
+			}
+			if path, _ := tree.PathTo(target); pathNames(path) != tt.path {
+				t.Errorf("path = %s, want %s", pathNames(path), tt.path)
+			}
+		})
+	}
+}
+
+func BenchmarkTopologySnapshot(b *testing.B) {
+	nodes := torus(40)
+	b.ResetTimer()
+	for range b.N {
+		NewTopologySnapshot(nodes, latencyCost{})
+	}
+}
+
+func BenchmarkShortestPathTree(b *testing.B) {
+	s := NewTopologySnapshot(torus(40), latencyCost{})
+	h := newNodeHeap(s.Len())
+	b.ResetTimer()
+	for i := range b.N {
+		h.Reset()
+		computeShortestPathTree(s, i%s.Len(), h)
+	}
+}
+
+func BenchmarkComputeAll(b *testing.B) {
+	engine := NewRoutingEngine(nil)
+	for _, n := range torus(40) {
+		engine.Register(n)
+	}
+	b.ResetTimer()
+	for range b.N {
+		engine.Invalidate()
+		engine.ComputeAll()
+	}
+}
+
+func BenchmarkKShortestPaths(b *testing.B) {
+	s := NewTopologySnapshot(torus(20), latencyCost{})
+	b.ResetTimer()
+	for i := range b.N {
+		src := i % s.Len()
+		kShortestPaths(s, src, (src+s.Len()/2+10)%s.Len(), 5)
+	}
+}
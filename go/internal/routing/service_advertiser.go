@@ -0,0 +1,361 @@
+package routing
+
+import (
+	"container/heap"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// ServiceAdvertisementPlane keeps the service advertisements of all routers in sync with the topology.
+// Advertisements are soft state: once per simulation step every hosting node re-advertises its services
+// over the current links and all routes which were not refreshed are dropped. This way routes over links
+// which went down or to nodes which failed disappear.
+// Advertisements are delivered in the order of their accumulated latency, so every router passes on
+// only the best route to a replica instead of every improvement.
+type ServiceAdvertisementPlane struct {
+	balancer    *ServiceBalancer // selects the replica of a request, nil for the closest one
+	mu          sync.Mutex
+	advertisers []*serviceAdvertiser
+	generation  uint64
+	queue       advertisementQueue
+	delivering  bool
+}
+
+// advertisementDelivery is an advertisement or a withdrawal on its way to a neighbour.
+type advertisementDelivery struct {
+	to          types.Router
+	serviceName string
+	link        types.Link
+	route       *ServiceRouteResult // advertised route, nil for withdrawals
+	withdrawn   types.Node          // withdrawn replica, nil for advertisements
+}
+
+// latency returns the accumulated latency the delivery is ordered by, withdrawals go first.
+func (d *advertisementDelivery) latency() float64 {
+	if d.route == nil {
+		return 0
+	}
+	return d.route.ExactLatency()
+}
+
+type advertisementQueue []*advertisementDelivery
+
+func (q advertisementQueue) Len() int { return len(q) }
+func (q advertisementQueue) Less(i, j int) bool {
+	return q[i].latency() < q[j].latency()
+}
+func (q advertisementQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *advertisementQueue) Push(x interface{}) {
+	*q = append(*q, x.(*advertisementDelivery))
+}
+func (q *advertisementQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return item
+}
+
+// NewServiceAdvertisementPlane creates an empty advertisement plane.
+func NewServiceAdvertisementPlane() *ServiceAdvertisementPlane {
+	return &ServiceAdvertisementPlane{}
+}
+
+// register adds a mounted advertiser to the plane.
+func (p *ServiceAdvertisementPlane) register(a *serviceAdvertiser) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.advertisers = append(p.advertisers, a)
+}
+
+// currentGeneration returns the refresh round advertisements are tagged with.
+func (p *ServiceAdvertisementPlane) currentGeneration() uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.generation
+}
+
+// sendAll queues the deliveries and delivers all queued ones unless a delivery is in progress.
+// All advertisements are queued before the first one is delivered, so none of them is overtaken by a worse route.
+// Delivering from the queue instead of recursing keeps the stack flat however many nodes pass a message on.
+func (p *ServiceAdvertisementPlane) sendAll(deliveries []*advertisementDelivery) {
+	if len(deliveries) == 0 {
+		return
+	}
+	p.mu.Lock()
+	for _, d := range deliveries {
+		heap.Push(&p.queue, d)
+	}
+	if p.delivering {
+		p.mu.Unlock()
+		return
+	}
+	p.delivering = true
+	for p.queue.Len() > 0 {
+		next := heap.Pop(&p.queue).(*advertisementDelivery)
+		p.mu.Unlock()
+		if next.withdrawn != nil {
+			next.to.ReceiveServiceWithdrawalAsync(next.serviceName, next.withdrawn)
+		} else {
+			next.to.ReceiveServiceAdvertismentsAsync(next.serviceName, next.link, next.route)
+		}
+		p.mu.Lock()
+	}
+	p.delivering = false
+	p.mu.Unlock()
+}
+
+// Refresh re-advertises all deployed services over the current topology and drops stale routes.
+func (p *ServiceAdvertisementPlane) Refresh() {
+	p.mu.Lock()
+	p.generation++
+	generation := p.generation
+	advertisers := make([]*serviceAdvertiser, len(p.advertisers))
+	copy(advertisers, p.advertisers)
+	p.mu.Unlock()
+
+	for _, a := range advertisers {
+		for _, service := range a.node.GetComputing().GetServices() {
+			if service.IsDeployed() {
+				a.AdvertiseNewServiceAsync(service.GetServiceName())
+			}
+		}
+	}
+	for _, a := range advertisers {
+		a.purge(generation)
+	}
+	if p.balancer != nil {
+		p.balancer.advance()
+	}
+}
+
+// serviceAdvertiser implements the service advertisement part of the Router interface.
+// Routers embed it to learn the services propagated through the network.
+type serviceAdvertiser struct {
+	node  types.Node
+	plane *ServiceAdvertisementPlane
+
+	mu     sync.Mutex
+	routes map[string]map[types.Node]*serviceRoute // routes by service name and hosting node
+}
+
+// serviceRoute is the best known route to one replica of a service.
+type serviceRoute struct {
+	OutLink    types.Link
+	Origin     types.Node
+	Latency    float64
+	generation uint64
+}
+
+func newServiceAdvertiser(plane *ServiceAdvertisementPlane) serviceAdvertiser {
+	return serviceAdvertiser{
+		plane:  plane,
+		routes: make(map[string]map[types.Node]*serviceRoute),
+	}
+}
+
+// mount binds the advertiser to the router's node.
+func (a *serviceAdvertiser) mount(node types.Node) {
+	a.node = node
+	a.plane.register(a)
+}
+
+// AdvertiseNewServiceAsync floods an advertisement of a service hosted on the mounted node
+func (a *serviceAdvertiser) AdvertiseNewServiceAsync(serviceName string) error {
+	if a.node == nil {
+		return errors.New("router not mounted")
+	}
+	a.flood(serviceName, a.node, 0, nil)
+	return nil
+}
+
+// ReceiveServiceAdvertismentsAsync stores the advertised route if it is new or better
+// and passes the advertisement on with the accumulated latency
+func (a *serviceAdvertiser) ReceiveServiceAdvertismentsAsync(serviceName string, outlink types.Link, route types.RouteResult) error {
+	if a.node == nil {
+		return errors.New("router not mounted")
+	}
+
+	origin := outlink.GetOther(a.node)
+	latency := float64(route.Latency())
+	if sr, ok := route.(*ServiceRouteResult); ok {
+		origin = sr.Origin()
+		latency = sr.ExactLatency()
+	}
+	if origin == a.node {
+		return nil
+	}
+
+	generation := a.plane.currentGeneration()
+	a.mu.Lock()
+	replicas, ok := a.routes[serviceName]
+	if !ok {
+		replicas = make(map[types.Node]*serviceRoute)
+		a.routes[serviceName] = replicas
+	}
+	if existing, ok := replicas[origin]; ok && existing.generation == generation && existing.Latency <= latency {
+		a.mu.Unlock()
+		return nil
+	}
+	replicas[origin] = &serviceRoute{
+		OutLink:    outlink,
+		Origin:     origin,
+		Latency:    latency,
+		generation: generation,
+	}
+	a.mu.Unlock()
+
+	a.flood(serviceName, origin, latency, outlink)
+	return nil
+}
+
+// WithdrawServiceAsync floods the withdrawal of a service which is no longer hosted on the mounted node.
+// Nothing is withdrawn while the node still serves another deployed replica of the service.
+func (a *serviceAdvertiser) WithdrawServiceAsync(serviceName string) error {
+	if a.node == nil {
+		return errors.New("router not mounted")
+	}
+	if a.serves(serviceName) {
+		return nil
+	}
+	a.withdraw(serviceName, a.node)
+	return nil
+}
+
+// serves reports if the mounted node hosts a deployed replica of the service.
+func (a *serviceAdvertiser) serves(serviceName string) bool {
+	c := a.node.GetComputing()
+	if c == nil || !c.HostsService(serviceName) {
+		return false
+	}
+	for _, service := range c.GetServices() {
+		if service.GetServiceName() == serviceName && service.IsDeployed() {
+			return true
+		}
+	}
+	return false
+}
+
+// ReceiveServiceWithdrawalAsync removes the route to the withdrawn replica and passes the withdrawal on
+func (a *serviceAdvertiser) ReceiveServiceWithdrawalAsync(serviceName string, origin types.Node) error {
+	if a.node == nil {
+		return errors.New("router not mounted")
+	}
+
+	a.mu.Lock()
+	replicas := a.routes[serviceName]
+	_, ok := replicas[origin]
+	delete(replicas, origin)
+	a.mu.Unlock()
+
+	if ok {
+		a.withdraw(serviceName, origin)
+	}
+	return nil
+}
+
+// bestServiceRoute returns the route to the known replica of the service which serves a request,
+// selected by the service balancer of the plane or the closest one without balancer.
+// Probe payloads get the replica the next request would be assigned to, without assigning them.
+func (a *serviceAdvertiser) bestServiceRoute(serviceName string, payload types.Payload) (serviceRoute, bool) {
+	if a.plane.balancer != nil {
+		probe, _ := payload.(types.ProbePayload)
+		return a.plane.balancer.choose(serviceName, a.serviceRoutes(serviceName), probe != nil && probe.Probe())
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var best *serviceRoute
+	for _, route := range a.routes[serviceName] {
+		if best == nil || route.Latency < best.Latency {
+			best = route
+		}
+	}
+	if best == nil {
+		return serviceRoute{}, false
+	}
+	return *best, true
+}
+
+// replicaResult names the replica a reachable route leads to, so callers know the serving node.
+// Routes which know their destination are kept as they are.
+func replicaResult(result types.RouteResult, err error, replica types.Node) (types.RouteResult, error) {
+	if err != nil || !result.Reachable() {
+		return result, err
+	}
+	if _, ok := Destination(result); ok {
+		return result, nil
+	}
+	return NewServiceRouteResult(float64(result.Latency()), replica), nil
+}
+
+// serviceRoutes returns the routes to all known replicas of the service ordered by latency.
+func (a *serviceAdvertiser) serviceRoutes(serviceName string) []serviceRoute {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	routes := make([]serviceRoute, 0, len(a.routes[serviceName]))
+	for _, route := range a.routes[serviceName] {
+		routes = append(routes, *route)
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].Latency < routes[j].Latency })
+	return routes
+}
+
+// flood sends an advertisement over all established links except the one it arrived on.
+func (a *serviceAdvertiser) flood(serviceName string, origin types.Node, latency float64, inLink types.Link) {
+	var deliveries []*advertisementDelivery
+	a.forEachNeighbour(inLink, func(neighbour types.Node, l types.Link) {
+		deliveries = append(deliveries, &advertisementDelivery{
+			to:          neighbour.GetRouter(),
+			serviceName: serviceName,
+			link:        l,
+			route:       NewServiceRouteResult(latency+l.Latency(), origin),
+		})
+	})
+	a.plane.sendAll(deliveries)
+}
+
+// withdraw sends the withdrawal of the replica to all neighbours.
+func (a *serviceAdvertiser) withdraw(serviceName string, origin types.Node) {
+	var deliveries []*advertisementDelivery
+	a.forEachNeighbour(nil, func(neighbour types.Node, l types.Link) {
+		deliveries = append(deliveries, &advertisementDelivery{
+			to:          neighbour.GetRouter(),
+			serviceName: serviceName,
+			link:        l,
+			withdrawn:   origin,
+		})
+	})
+	a.plane.sendAll(deliveries)
+}
+
+// forEachNeighbour calls fn for every neighbour over the established links, skipping the excluded link.
+func (a *serviceAdvertiser) forEachNeighbour(exclude types.Link, fn func(neighbour types.Node, l types.Link)) {
+	for _, l := range a.node.GetLinkNodeProtocol().Established() {
+		if l == exclude {
+			continue
+		}
+		if neighbour := l.GetOther(a.node); neighbour != nil && neighbour.GetRouter() != nil {
+			fn(neighbour, l)
+		}
+	}
+}
+
+// purge drops all routes which were not refreshed in the given generation.
+func (a *serviceAdvertiser) purge(generation uint64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for name, replicas := range a.routes {
+		for origin, route := range replicas {
+			if route.generation < generation {
+				delete(replicas, origin)
+			}
+		}
+		if len(replicas) == 0 {
+			delete(a.routes, name)
+		}
+	}
+}
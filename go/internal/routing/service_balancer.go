@@ -0,0 +1,196 @@
+package routing
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// Policies of the service balancer
+const (
+	LowestLatencyBalancing = "lowest-latency" // closest replica, the behaviour without balancer
+	RoundRobinBalancing    = "round-robin"    // known replicas in turn, ordered by name
+	LeastLoadedBalancing   = "least-loaded"   // most available CPU per request assigned in the current step
+	PowerOfTwoBalancing    = "power-of-two"   // fewer requests in the current step of two random replicas
+)
+
+// ReplicaLoad are the requests a service balancer assigned to one replica.
+type ReplicaLoad struct {
+	Requests     int     // requests since the start of the simulation
+	StepRequests int     // requests since the start of the current step
+	LatencySum   float64 // sum of the route latencies in ms of all requests
+}
+
+// MeanLatency returns the mean route latency of the requests in ms.
+func (l ReplicaLoad) MeanLatency() float64 {
+	if l.Requests == 0 {
+		return 0
+	}
+	return l.LatencySum / float64(l.Requests)
+}
+
+// ServiceBalancer selects the replica serving a request to a service among the replicas advertised to the
+// router of the client. Every call of RouteToService of a router is one request, except for probe payloads.
+// A node hosting the service serves its own requests, they are not balanced.
+type ServiceBalancer struct {
+	policy string
+	source *rand.PCG
+	rand   *rand.Rand
+
+	mu    sync.Mutex
+	next  map[string]int                         // round-robin position by service
+	loads map[string]map[types.Node]*ReplicaLoad // assigned requests by service and replica
+}
+
+// NewServiceBalancer creates a balancer with the policy, the seed makes power-of-two choices reproducible.
+func NewServiceBalancer(cfg configs.ServiceBalancingConfig) (*ServiceBalancer, error) {
+	policy := strings.ToLower(cfg.Policy)
+	switch policy {
+	case "":
+		policy = LowestLatencyBalancing
+	case LowestLatencyBalancing, RoundRobinBalancing, LeastLoadedBalancing, PowerOfTwoBalancing:
+	default:
+		return nil, fmt.Errorf("unknown service balancing policy: %s", cfg.Policy)
+	}
+	source := rand.NewPCG(uint64(cfg.Seed), 0)
+	return &ServiceBalancer{
+		policy: policy,
+		source: source,
+		rand:   rand.New(source),
+		next:   make(map[string]int),
+		loads:  make(map[string]map[types.Node]*ReplicaLoad),
+	}, nil
+}
+
+// Policy returns the balancing policy.
+func (b *ServiceBalancer) Policy() string {
+	return b.policy
+}
+
+// Services returns the sorted names of the services requests were assigned for.
+func (b *ServiceBalancer) Services() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	names := make([]string, 0, len(b.loads))
+	for name := range b.loads {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Loads returns the requests assigned to every replica of the service.
+func (b *ServiceBalancer) Loads(service string) map[types.Node]ReplicaLoad {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	loads := make(map[types.Node]ReplicaLoad, len(b.loads[service]))
+	for n, l := range b.loads[service] {
+		loads[n] = *l
+	}
+	return loads
+}
+
+// LoadSkew returns the requests of the busiest replica of the service relative to the mean over its replicas,
+// 1 for an even load and 0 without requests.
+func (b *ServiceBalancer) LoadSkew(service string) float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	total, busiest := 0, 0
+	for _, l := range b.loads[service] {
+		total += l.Requests
+		busiest = max(busiest, l.Requests)
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(busiest) * float64(len(b.loads[service])) / float64(total)
+}
+
+// advance starts a new step, the requests of the current step no longer load the replicas.
+func (b *ServiceBalancer) advance() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, replicas := range b.loads {
+		for _, l := range replicas {
+			l.StepRequests = 0
+		}
+	}
+}
+
+// choose selects the route of a request among the routes to the known replicas and assigns the request to it.
+// A probe gets the route the next request would be assigned to and leaves the loads and choices unchanged.
+func (b *ServiceBalancer) choose(service string, routes []serviceRoute, probe bool) (serviceRoute, bool) {
+	if len(routes) == 0 {
+		return serviceRoute{}, false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	replicas, ok := b.loads[service]
+	if !ok {
+		replicas = make(map[types.Node]*ReplicaLoad)
+		if !probe {
+			b.loads[service] = replicas
+		}
+	}
+	load := func(r serviceRoute) *ReplicaLoad {
+		l, ok := replicas[r.Origin]
+		if !ok {
+			l = &ReplicaLoad{}
+			if !probe {
+				replicas[r.Origin] = l
+			}
+		}
+		return l
+	}
+	random := b.rand
+	if probe {
+		// Draw from a copy of the source, so the probe sees the choice of the next request
+		source := *b.source
+		random = rand.New(&source)
+	}
+
+	// Routes are ordered by latency, so ties keep the closer replica
+	chosen := routes[0]
+	switch b.policy {
+	case RoundRobinBalancing:
+		byName := append([]serviceRoute(nil), routes...)
+		sort.SliceStable(byName, func(i, j int) bool { return byName[i].Origin.GetName() < byName[j].Origin.GetName() })
+		chosen = byName[b.next[service]%len(byName)]
+		if !probe {
+			b.next[service]++
+		}
+	case LeastLoadedBalancing:
+		best := -1.0
+		for _, r := range routes {
+			if free := r.Origin.GetComputing().CpuAvailable() / float64(1+load(r).StepRequests); free > best {
+				chosen, best = r, free
+			}
+		}
+	case PowerOfTwoBalancing:
+		if len(routes) > 1 {
+			i := random.IntN(len(routes))
+			j := random.IntN(len(routes) - 1)
+			if j >= i {
+				j++
+			}
+			chosen = routes[min(i, j)]
+			if other := routes[max(i, j)]; load(other).StepRequests < load(chosen).StepRequests {
+				chosen = other
+			}
+		}
+	}
+
+	if probe {
+		return chosen, true
+	}
+	l := load(chosen)
+	l.Requests++
+	l.StepRequests++
+	l.LatencySum += chosen.Latency
+	return chosen, true
+}
@@ -0,0 +1,123 @@
+package routing
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/internal/simtest"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// cpuComputing is a computing unit with a fixed available CPU.
+type cpuComputing struct {
+	types.Computing
+	cpu float64
+}
+
+func (c cpuComputing) CpuAvailable() float64 { return c.cpu }
+
+// replicaRoutes returns routes to the replicas A, B and C with the latencies 1, 2 and 3 ms
+// and the available CPUs 2, 4 and 1, ordered by latency as the advertiser passes them.
+func replicaRoutes() []serviceRoute {
+	var routes []serviceRoute
+	for i, cpu := range []float64{2, 4, 1} {
+		n := simtest.NewNode(string(rune('A' + i)))
+		n.Computing = cpuComputing{cpu: cpu}
+		routes = append(routes, serviceRoute{Origin: n, Latency: float64(i + 1)})
+	}
+	return routes
+}
+
+func TestServiceBalancer(t *testing.T) {
+	tests := []struct {
+		policy string
+		routes int // number of replicas
+		want   string
+	}{
+		{"", 3, "AAAAAA"},
+		{LowestLatencyBalancing, 3, "AAAAAA"},
+		{RoundRobinBalancing, 3, "ABCABC"},
+		{LeastLoadedBalancing, 3, "BABBAB"},
+		{PowerOfTwoBalancing, 2, "ABABAB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			b, err := NewServiceBalancer(configs.ServiceBalancingConfig{Policy: tt.policy})
+			if err != nil {
+				t.Fatal(err)
+			}
+			routes := replicaRoutes()[:tt.routes]
+			var chosen strings.Builder
+			for range len(tt.want) {
+				route, ok := b.choose("web", routes, false)
+				if !ok {
+					t.Fatal("no replica chosen")
+				}
+				chosen.WriteString(route.Origin.GetName())
+			}
+			if chosen.String() != tt.want {
+				t.Errorf("chose %s, want %s", chosen.String(), tt.want)
+			}
+
+			requests := 0
+			for _, l := range b.Loads("web") {
+				requests += l.Requests
+			}
+			if requests != len(tt.want) {
+				t.Errorf("%d requests assigned, want %d", requests, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestServiceBalancerProbe(t *testing.T) {
+	for _, policy := range []string{LowestLatencyBalancing, RoundRobinBalancing, LeastLoadedBalancing, PowerOfTwoBalancing} {
+		t.Run(policy, func(t *testing.T) {
+			b, err := NewServiceBalancer(configs.ServiceBalancingConfig{Policy: policy, Seed: 42})
+			if err != nil {
+				t.Fatal(err)
+			}
+			routes := replicaRoutes()
+			for i := range 20 {
+				probed, _ := b.choose("web", routes, true)
+				if again, _ := b.choose("web", routes, true); again.Origin != probed.Origin {
+					t.Fatalf("request %d: probes chose %s and %s", i, probed.Origin.GetName(), again.Origin.GetName())
+				}
+				if chosen, _ := b.choose("web", routes, false); chosen.Origin != probed.Origin {
+					t.Fatalf("request %d: probe chose %s, the request %s", i, probed.Origin.GetName(), chosen.Origin.GetName())
+				}
+			}
+			if _, ok := b.choose("db", routes, true); !ok || len(b.Services()) != 1 {
+				t.Errorf("probe of a new service assigned it: %v", b.Services())
+			}
+		})
+	}
+}
+
+func TestServiceBalancerSteps(t *testing.T) {
+	b, err := NewServiceBalancer(configs.ServiceBalancingConfig{Policy: LeastLoadedBalancing})
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes := replicaRoutes()
+	for range 3 {
+		b.choose("web", routes, false)
+	}
+	// A new step forgets the requests of the last one, the replica with the most CPU is chosen again
+	b.advance()
+	if route, _ := b.choose("web", routes, false); route.Origin.GetName() != "B" {
+		t.Errorf("chose %s after the step, want B", route.Origin.GetName())
+	}
+	loads := b.Loads("web")
+	if l := loads[routes[1].Origin]; l.Requests != 3 || l.StepRequests != 1 || l.MeanLatency() != 2 {
+		t.Errorf("load of B = %+v, want 3 requests, 1 in the step and a mean latency of 2 ms", l)
+	}
+	if skew := b.LoadSkew("web"); skew != 2.25 {
+		t.Errorf("load skew = %v, want 2.25 for 3 of 4 requests on one of three replicas", skew)
+	}
+
+	if _, err := NewServiceBalancer(configs.ServiceBalancingConfig{Policy: "random"}); err == nil {
+		t.Error("unknown policy accepted")
+	}
+}
@@ -0,0 +1,48 @@
+package routing
+
+import "github.com/keniack/stardustGo/pkg/types"
+
+var _ types.RouteResult = (*ServiceRouteResult)(nil)
+
+// ServiceRouteResult is the route carried by a service advertisement.
+// Besides the accumulated latency it knows the node hosting the service.
+type ServiceRouteResult struct {
+	PreRouteResult
+	origin  types.Node
+	latency float64 // accumulated latency in ms without rounding
+}
+
+// NewServiceRouteResult creates a route to a service hosted on origin
+func NewServiceRouteResult(latency float64, origin types.Node) *ServiceRouteResult {
+	return &ServiceRouteResult{
+		PreRouteResult: PreRouteResult{latency: int(latency)},
+		origin:         origin,
+		latency:        latency,
+	}
+}
+
+// Origin returns the node hosting the service
+func (r *ServiceRouteResult) Origin() types.Node {
+	return r.origin
+}
+
+// Destination returns the node a route result ends at, false if the result does not name it.
+// Routes to services end at the serving replica.
+func Destination(result types.RouteResult) (types.Node, bool) {
+	switch r := result.(type) {
+	case *ServiceRouteResult:
+		return r.Origin(), r.Origin() != nil
+	case *PathRouteResult:
+		if nodes := r.Path().Nodes; len(nodes) > 0 {
+			return nodes[len(nodes)-1], true
+		}
+	case *ContactRouteResult:
+		return r.Destination(), r.Destination() != nil
+	}
+	return nil, false
+}
+
+// ExactLatency returns the accumulated latency in ms without rounding
+func (r *ServiceRouteResult) ExactLatency() float64 {
+	return r.latency
+}
@@ -0,0 +1,106 @@
+package routing
+
+import (
+	"sort"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// TopologySnapshot is an immutable view of the established links of all nodes at one simulation step.
+// Nodes are indexed by dense integer IDs and the adjacency is stored in compressed sparse row form,
+// so shortest path searches can run on plain slices instead of maps keyed by node.
+type TopologySnapshot struct {
+	nodes     []types.Node
+	index     map[types.Node]int
+	positions []types.Vector
+	offsets   []int          // edges of node i are edges[offsets[i]:offsets[i+1]]
+	edges     []SnapshotEdge // outgoing edges of all nodes
+}
+
+// SnapshotEdge is a directed edge in a TopologySnapshot.
+type SnapshotEdge struct {
+	To        int        // dense ID of the neighbour
+	Link      types.Link // the underlying link
+	Latency   float64    // link latency in milliseconds at snapshot time
+	Bandwidth float64    // link bandwidth in bits per second at snapshot time
+	Cost      float64    // link cost of the engine's LinkCost at snapshot time
+}
+
+// NewTopologySnapshot captures the currently established links of the given nodes and their costs.
+// Links towards nodes which are not part of the list are ignored.
+func NewTopologySnapshot(nodes []types.Node, cost LinkCost) *TopologySnapshot {
+	s := &TopologySnapshot{
+		nodes:     nodes,
+		index:     make(map[types.Node]int, len(nodes)),
+		positions: make([]types.Vector, len(nodes)),
+		offsets:   make([]int, len(nodes)+1),
+	}
+	for i, n := range nodes {
+		s.index[n] = i
+		s.positions[i] = n.GetPosition()
+	}
+
+	for i, n := range nodes {
+		s.offsets[i] = len(s.edges)
+		for _, l := range n.GetLinkNodeProtocol().Established() {
+			other := l.GetOther(n)
+			if other == nil {
+				continue
+			}
+			to, ok := s.index[other]
+			if !ok {
+				continue
+			}
+			s.edges = append(s.edges, SnapshotEdge{
+				To:        to,
+				Link:      l,
+				Latency:   l.Latency(),
+				Bandwidth: l.Bandwidth(),
+				Cost:      max(0, cost.Cost(l, n, other)),
+			})
+		}
+	}
+	s.offsets[len(nodes)] = len(s.edges)
+	return s
+}
+
+// Len returns the number of nodes in the snapshot.
+func (s *TopologySnapshot) Len() int {
+	return len(s.nodes)
+}
+
+// Node returns the node with the given dense ID.
+func (s *TopologySnapshot) Node(id int) types.Node {
+	return s.nodes[id]
+}
+
+// Nodes returns all nodes of the snapshot ordered by their dense ID.
+func (s *TopologySnapshot) Nodes() []types.Node {
+	return s.nodes
+}
+
+// IndexOf returns the dense ID of a node and whether it is part of the snapshot.
+func (s *TopologySnapshot) IndexOf(n types.Node) (int, bool) {
+	id, ok := s.index[n]
+	return id, ok
+}
+
+// Position returns the position of a node at snapshot time.
+func (s *TopologySnapshot) Position(id int) types.Vector {
+	return s.positions[id]
+}
+
+// Edges returns the outgoing edges of a node.
+func (s *TopologySnapshot) Edges(id int) []SnapshotEdge {
+	return s.edges[s.offsets[id]:s.offsets[id+1]]
+}
+
+// edgeSource returns the dense ID of the node the edge with the given index starts at.
+func (s *TopologySnapshot) edgeSource(e int) int {
+	return sort.Search(len(s.nodes), func(i int) bool { return s.offsets[i+1] > e })
+}
+
+// EdgeCount returns the number of directed edges in the snapshot.
+func (s *TopologySnapshot) EdgeCount() int {
+	return len(s.edges)
+}
@@ -2,10 +2,17 @@ package routing
 
 import "github.com/keniack/stardustGo/pkg/types"
 
-type UnreachableRouteResult struct{}
+type UnreachableRouteResult struct {
+	reason string
+}
 
 var UnreachableRouteResultInstance = &UnreachableRouteResult{}
 
+// NewUnreachableRouteResult creates an unreachable route explaining why no route was found
+func NewUnreachableRouteResult(reason string) *UnreachableRouteResult {
+	return &UnreachableRouteResult{reason: reason}
+}
+
 func (r *UnreachableRouteResult) Reachable() bool {
 	return false
 }
@@ -14,6 +21,14 @@ func (r *UnreachableRouteResult) Latency() int {
 	return -1
 }
 
+// Reason returns why the target is unreachable
+func (r *UnreachableRouteResult) Reason() string {
+	if r.reason == "" {
+		return "no route to target"
+	}
+	return r.reason
+}
+
 func (r *UnreachableRouteResult) WaitLatencyAsync() error {
 	return nil
 }
@@ -1,6 +1,9 @@
 package satellite
 
 import (
+	"fmt"
+	"math"
+	"regexp"
 	"time"
 
 	"github.com/keniack/stardustGo/configs"
@@ -26,15 +29,33 @@ type SatelliteBuilder struct {
 	computingBuilder *computing.DefaultComputingBuilder
 	islBuilder       *links.IslProtocolBuilder
 	islConfig        configs.InterSatelliteLinkConfig // Store the ISL config
+	routerSelectors  []routerSelector
+}
+
+// routerSelector is a compiled configs.RouterSelector.
+type routerSelector struct {
+	name   *regexp.Regexp
+	router string
 }
 
 // NewSatelliteBuilder creates a new SatelliteBuilder with required dependencies.
+// It panics if a router selector of the ISL config is not a valid regular expression.
 func NewSatelliteBuilder(routerBuilder *routing.RouterBuilder, computing *computing.DefaultComputingBuilder, islConfig configs.InterSatelliteLinkConfig) *SatelliteBuilder {
+	selectors := make([]routerSelector, len(islConfig.Routers))
+	for i, sel := range islConfig.Routers {
+		name, err := regexp.Compile(sel.Name)
+		if err != nil {
+			panic(fmt.Sprintf("invalid router selector %q: %v", sel.Name, err))
+		}
+		selectors[i] = routerSelector{name: name, router: sel.Router}
+	}
+
 	return &SatelliteBuilder{
 		routerBuilder:    routerBuilder,
 		computingBuilder: computing,
 		islConfig:        islConfig,                              // Initialize the ISL config
 		islBuilder:       links.NewIslProtocolBuilder(islConfig), // Pass the ISL config to the builder
+		routerSelectors:  selectors,
 	}
 }
 
@@ -78,6 +99,16 @@ func (b *SatelliteBuilder) SetEpoch(epoch time.Time) *SatelliteBuilder {
 	return b
 }
 
+// routerProtocol returns the router protocol of the satellite being built, selected by the ISL config.
+func (b *SatelliteBuilder) routerProtocol() string {
+	for _, sel := range b.routerSelectors {
+		if sel.name.MatchString(b.name) {
+			return sel.router
+		}
+	}
+	return routing.DefaultRouter
+}
+
 // ConfigureISL now uses the ISL config passed to the builder
 func (b *SatelliteBuilder) ConfigureISL(fn func(builder *links.IslProtocolBuilder) *links.IslProtocolBuilder) *SatelliteBuilder {
 	// Pass the ISL config to the builder
@@ -87,8 +118,8 @@ func (b *SatelliteBuilder) ConfigureISL(fn func(builder *links.IslProtocolBuilde
 
 // Build constructs the Satellite instance from configured parameters.
 func (b *SatelliteBuilder) Build() types.Satellite {
-	// Handle the error returned by routerBuilder.Build()
-	router, err := b.routerBuilder.Build()
+	// Handle the error returned by routerBuilder.BuildProtocol()
+	router, err := b.routerBuilder.BuildProtocol(b.routerProtocol())
 	if err != nil {
 		// Handle error (e.g., log it or return nil)
 		// For now, we'll panic, but you can return an error or a fallback value
@@ -107,6 +138,20 @@ func (b *SatelliteBuilder) Build() types.Satellite {
 		time.Now(), // Simulated current time, adjust if needed
 		b.islBuilder.Build(),
 		router, // Pass the router after error handling
-		b.computingBuilder.WithComputingType(types.ComputingType(types.Edge)).Build(),
+		b.computingBuilder.WithComputingType(types.ComputingType(types.Edge)).WithNode(computing.NodeDescriptor{
+			Name:        b.name,
+			Satellite:   true,
+			Inclination: b.inclination,
+			Altitude:    b.altitude(),
+		}).Build(),
 	)
 }
+
+// altitude returns the orbital altitude in km of the satellite being built, derived from its mean motion.
+func (b *SatelliteBuilder) altitude() float64 {
+	if b.meanMotion <= 0 {
+		return 0
+	}
+	n := b.meanMotion * 2 * math.Pi / 86400 // mean motion in rad/s
+	return (math.Cbrt(configs.MU/(n*n)) - configs.EarthRadius) / 1000
+}
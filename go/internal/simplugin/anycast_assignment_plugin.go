@@ -0,0 +1,72 @@
+package simplugin
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/internal/routing"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var _ types.SimulationPlugin = (*AnycastAssignmentPlugin)(nil)
+var _ io.Closer = (*AnycastAssignmentPlugin)(nil)
+
+// AnycastAssignmentPlugin exports the best gateway of every user terminal after each simulation step.
+// Terminals and gateways are anycast groups, every step appends one CSV row per terminal.
+type AnycastAssignmentPlugin struct {
+	groups *routing.AnycastGroups
+	config configs.AnycastExportConfig
+
+	file   *os.File
+	writer *bufio.Writer
+}
+
+// NewAnycastAssignmentPlugin creates the plugin, the output file is created on the first step.
+func NewAnycastAssignmentPlugin(groups *routing.AnycastGroups, config configs.AnycastExportConfig) *AnycastAssignmentPlugin {
+	return &AnycastAssignmentPlugin{
+		groups: groups,
+		config: config,
+	}
+}
+
+func (p *AnycastAssignmentPlugin) Name() string {
+	return "AnycastAssignmentPlugin"
+}
+
+// PostSimulationStep assigns every terminal to its best gateway and writes the assignments
+func (p *AnycastAssignmentPlugin) PostSimulationStep(simulation types.SimulationController) error {
+	if p.writer == nil {
+		file, writer, err := createCSV(p.config.File, "time,terminal,gateway,latency_ms,hops")
+		if err != nil {
+			return err
+		}
+		p.file, p.writer = file, writer
+	}
+
+	terminals, err := p.groups.Members(p.config.Terminals)
+	if err != nil {
+		return err
+	}
+	simTime := simulation.GetSimulationTime().Format(time.RFC3339)
+	for _, terminal := range terminals {
+		route, ok, err := p.groups.BestMember(terminal, p.config.Gateways)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintf(p.writer, "%s,%s,,,\n", simTime, terminal.GetName())
+			continue
+		}
+		fmt.Fprintf(p.writer, "%s,%s,%s,%.3f,%d\n", simTime, terminal.GetName(), route.Member.GetName(), route.Latency(), route.Path.Hops())
+	}
+	return p.writer.Flush()
+}
+
+// Close flushes and closes the output file
+func (p *AnycastAssignmentPlugin) Close() error {
+	return closeOutput(p.file, p.writer)
+}
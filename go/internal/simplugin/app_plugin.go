@@ -0,0 +1,136 @@
+package simplugin
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/internal/app"
+	"github.com/keniack/stardustGo/pkg/helper"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var _ types.SimulationPlugin = (*AppPlugin)(nil)
+var _ io.Closer = (*AppPlugin)(nil)
+
+// App types of the app config
+const (
+	PingAppType = "ping" // sends a ping to the closest replica of a service every Interval steps
+	EchoAppType = "echo" // provides a service answering every message
+)
+
+// AppPlugin runs the declared apps on the app runtime. The apps are deployed on their nodes at the first step
+// and tick once per step; every step appends the round-trip times of the ping apps to a CSV file.
+type AppPlugin struct {
+	config   configs.AppConfig
+	runtime  *app.Runtime
+	pings    []*deployedPing
+	deployed bool
+
+	file   *os.File
+	writer *bufio.Writer
+}
+
+// deployedPing is a ping app and the node it runs on.
+type deployedPing struct {
+	node     types.Node
+	app      *app.PingApp
+	answered int // answered pings written so far
+}
+
+// NewAppPlugin creates the plugin, the apps are deployed on the first step.
+func NewAppPlugin(config configs.AppConfig) (*AppPlugin, error) {
+	for i, cfg := range config.Apps {
+		if cfg.Node == "" || cfg.Service == "" {
+			return nil, fmt.Errorf("app %d needs a node and a service", i)
+		}
+		switch strings.ToLower(cfg.App) {
+		case PingAppType, EchoAppType:
+		default:
+			return nil, fmt.Errorf("unknown app on %s: %s", cfg.Node, cfg.App)
+		}
+	}
+	return &AppPlugin{config: config, runtime: app.NewRuntime()}, nil
+}
+
+func (p *AppPlugin) Name() string {
+	return "AppPlugin"
+}
+
+// Runtime returns the runtime running the apps.
+func (p *AppPlugin) Runtime() *app.Runtime {
+	return p.runtime
+}
+
+// PostSimulationStep deploys the apps on the first step, runs them until the simulation time and writes the pings
+func (p *AppPlugin) PostSimulationStep(simulation types.SimulationController) error {
+	if !p.deployed {
+		p.deployed = true
+		if err := p.deploy(simulation); err != nil {
+			return err
+		}
+	}
+	if p.writer == nil && p.config.File != "" {
+		file, writer, err := createCSV(p.config.File, "time,node,service,answered,lost,mean_rtt_ms,max_rtt_ms")
+		if err != nil {
+			return err
+		}
+		p.file, p.writer = file, writer
+	}
+
+	now := simulation.GetSimulationTime()
+	if err := p.runtime.RunUntil(now); err != nil {
+		return err
+	}
+	if p.writer == nil {
+		return nil
+	}
+
+	// Round-trip times of the pings answered since the previous step
+	for _, ping := range p.pings {
+		rtts := ping.app.RoundTripTimes()[ping.answered:]
+		ping.answered += len(rtts)
+		mean, worst := 0.0, 0.0
+		for _, rtt := range rtts {
+			mean += helper.ToMilliseconds(rtt) / float64(len(rtts))
+			worst = max(worst, helper.ToMilliseconds(rtt))
+		}
+		fmt.Fprintf(p.writer, "%s,%s,%s,%d,%d,%.3f,%.3f\n", now.Format(time.RFC3339), ping.node.GetName(), ping.app.Service,
+			len(rtts), ping.app.Lost(), mean, worst)
+	}
+	return p.writer.Flush()
+}
+
+// Close flushes and closes the output file
+func (p *AppPlugin) Close() error {
+	return closeOutput(p.file, p.writer)
+}
+
+// deploy places the declared apps on their nodes.
+func (p *AppPlugin) deploy(simulation types.SimulationController) error {
+	nodes := simulation.GetAllNodes()
+	for _, cfg := range p.config.Apps {
+		i := slices.IndexFunc(nodes, func(n types.Node) bool { return n.GetName() == cfg.Node })
+		if i < 0 {
+			return fmt.Errorf("node %s of app %s not found", cfg.Node, cfg.App)
+		}
+		var a app.App
+		switch strings.ToLower(cfg.App) {
+		case PingAppType:
+			ping := &app.PingApp{Service: cfg.Service, Interval: cfg.Interval}
+			p.pings = append(p.pings, &deployedPing{node: nodes[i], app: ping})
+			a = ping
+		case EchoAppType:
+			a = &app.EchoApp{Service: cfg.Service}
+		}
+		if err := p.runtime.Deploy(nodes[i], a, cfg.Cpu, cfg.Memory); err != nil {
+			return err
+		}
+	}
+	return nil
+}
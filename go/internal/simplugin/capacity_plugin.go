@@ -0,0 +1,194 @@
+package simplugin
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/internal/capacity"
+	"github.com/keniack/stardustGo/internal/traffic"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var _ types.SimulationPlugin = (*CapacityPlugin)(nil)
+var _ io.Closer = (*CapacityPlugin)(nil)
+
+// CapacityPlugin estimates the throughput of a traffic demand after each simulation step.
+// The active demands are routed by the node routers and share the link bandwidths by the configured fairness.
+// Transfers progress with their allocated rate until the next step. Every step appends the totals to a CSV file,
+// and optionally the rate of every flow and the load of every link.
+type CapacityPlugin struct {
+	config   configs.CapacityConfig
+	simStart time.Time
+
+	demands   []traffic.Demand
+	remaining []float64 // bits left per transfer
+	rates     []float64 // rate per demand allocated in the last step
+	last      time.Time
+
+	summary     *bufio.Writer
+	flows       *bufio.Writer
+	links       *bufio.Writer
+	summaryFile *os.File
+	flowFile    *os.File
+	linkFile    *os.File
+}
+
+// NewCapacityPlugin creates the plugin. The demand is generated and the output files are created on the first step.
+func NewCapacityPlugin(config configs.CapacityConfig, simStart time.Time) *CapacityPlugin {
+	return &CapacityPlugin{
+		config:   config,
+		simStart: simStart,
+	}
+}
+
+func (p *CapacityPlugin) Name() string {
+	return "CapacityPlugin"
+}
+
+// PostSimulationStep allocates the rates of the active demands and writes the results
+func (p *CapacityPlugin) PostSimulationStep(simulation types.SimulationController) error {
+	now := simulation.GetSimulationTime()
+	if p.summary == nil {
+		if err := p.start(simulation, now); err != nil {
+			return err
+		}
+	}
+
+	// Progress of the transfers since the last step
+	elapsed := now.Sub(p.last).Seconds()
+	for i, d := range p.demands {
+		if d.IsTransfer() {
+			p.remaining[i] -= p.rates[i] * elapsed
+		}
+		p.rates[i] = 0
+	}
+	p.last = now
+
+	simTime := now.Format(time.RFC3339)
+	var flows []capacity.Flow
+	var active []int
+	unrouted := 0
+	offered, unserved := 0.0, 0.0
+	for i, d := range p.demands {
+		if !p.isActive(i, now) {
+			continue
+		}
+		offered += d.Rate
+		links, ok, err := capacity.Route(d.Source, d.Target, d, now)
+		if err != nil || !ok {
+			unrouted++
+			unserved += d.Rate
+			if p.flows != nil {
+				fmt.Fprintf(p.flows, "%s,%s,%s,%s,%.0f,0,0,unreachable\n", simTime, d.ID, d.Source.GetName(), d.Target.GetName(), d.Rate)
+			}
+			continue
+		}
+		flows = append(flows, capacity.Flow{ID: d.ID, Links: links, Demand: d.Rate})
+		active = append(active, i)
+	}
+
+	allocation, err := capacity.Solve(flows, p.config.Fairness)
+	if err != nil {
+		return err
+	}
+
+	carried := 0.0
+	bottlenecks := make([]int, len(allocation.Links))
+	for f, rate := range allocation.Rates {
+		d := p.demands[active[f]]
+		p.rates[active[f]] = rate
+		carried += rate
+		if !d.IsTransfer() {
+			unserved += d.Rate - rate
+		}
+		bottleneck := ""
+		if l := allocation.Bottlenecks[f]; l >= 0 {
+			bottlenecks[l]++
+			bottleneck = linkName(allocation.Links[l].DirectedLink)
+		}
+		if p.flows != nil {
+			fmt.Fprintf(p.flows, "%s,%s,%s,%s,%.0f,%.0f,%d,%s\n", simTime, d.ID, d.Source.GetName(), d.Target.GetName(), d.Rate, rate, len(flows[f].Links), bottleneck)
+		}
+	}
+
+	saturated := 0
+	maxUtilization := 0.0
+	for l, load := range allocation.Links {
+		if load.Saturated() {
+			saturated++
+		}
+		maxUtilization = max(maxUtilization, load.Utilization())
+		if p.links != nil {
+			fmt.Fprintf(p.links, "%s,%s,%s,%.0f,%.0f,%.4f,%d,%d\n", simTime, load.From.GetName(), load.To().GetName(), load.Load, load.Capacity, load.Utilization(), load.Flows, bottlenecks[l])
+		}
+	}
+	fmt.Fprintf(p.summary, "%s,%d,%d,%.0f,%.0f,%.0f,%d,%.4f\n", simTime, len(flows), unrouted, offered, carried, unserved, saturated, maxUtilization)
+
+	for _, w := range []*bufio.Writer{p.summary, p.flows, p.links} {
+		if w == nil {
+			continue
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// start generates the demand and creates the output files.
+func (p *CapacityPlugin) start(simulation types.SimulationController, now time.Time) error {
+	demands, err := traffic.Generate(p.config.Traffic, simulation.GetGroundStations(), p.simStart)
+	if err != nil {
+		return err
+	}
+	p.demands = demands
+	p.remaining = make([]float64, len(demands))
+	p.rates = make([]float64, len(demands))
+	for i, d := range demands {
+		p.remaining[i] = float64(d.Bytes * 8)
+	}
+	p.last = now
+
+	if p.summaryFile, p.summary, err = createCSV(p.config.File, "time,flows,unrouted,offered_bps,carried_bps,unserved_bps,saturated_links,max_utilization"); err != nil {
+		return err
+	}
+	if p.config.FlowFile != "" {
+		if p.flowFile, p.flows, err = createCSV(p.config.FlowFile, "time,flow,source,target,demand_bps,rate_bps,hops,bottleneck"); err != nil {
+			return err
+		}
+	}
+	if p.config.LinkFile != "" {
+		if p.linkFile, p.links, err = createCSV(p.config.LinkFile, "time,from,to,load_bps,capacity_bps,utilization,flows,bottleneck_flows"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isActive reports if the demand sends at the given time: constant demands during their duration,
+// transfers from their start until all bytes are carried.
+func (p *CapacityPlugin) isActive(i int, now time.Time) bool {
+	d := p.demands[i]
+	if d.Start.After(now) {
+		return false
+	}
+	if d.IsTransfer() {
+		return p.remaining[i] > 0
+	}
+	return now.Before(d.Start.Add(d.Duration))
+}
+
+// Close flushes and closes the output files
+func (p *CapacityPlugin) Close() error {
+	return errors.Join(closeOutput(p.summaryFile, p.summary), closeOutput(p.flowFile, p.flows), closeOutput(p.linkFile, p.links))
+}
+
+// linkName names a directed link by its nodes.
+func linkName(l capacity.DirectedLink) string {
+	return l.From.GetName() + ">" + l.To().GetName()
+}
@@ -0,0 +1,232 @@
+package simplugin
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/internal/deployment"
+	"github.com/keniack/stardustGo/pkg/helper"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var (
+	_ types.SimulationPlugin       = (*DeploymentPlugin)(nil)
+	_ deployment.LifecycleObserver = (*DeploymentPlugin)(nil)
+	_ io.Closer                    = (*DeploymentPlugin)(nil)
+)
+
+// DeploymentPlugin hands the declared services to the orchestrator once the simulation reaches their start
+// and deletes them after their duration. Services with autoscaling policy are attached to the autoscaler of the
+// orchestrator. It writes the state transitions of the replicas and the SLO compliance if configured.
+type DeploymentPlugin struct {
+	orchestrator *deployment.DeploymentOrchestrator
+	config       configs.DeploymentConfig
+	simStart     time.Time
+	deployments  []*declaredDeployment
+	registrySet  bool
+
+	lifecycleFile   *os.File
+	lifecycleWriter *bufio.Writer
+	migrationFile   *os.File
+	migrationWriter *bufio.Writer
+	sloFile         *os.File
+	sloWriter       *bufio.Writer
+}
+
+// declaredDeployment tracks a declared service through the simulation.
+type declaredDeployment struct {
+	config  configs.ServiceDeploymentConfig
+	start   time.Time
+	stop    time.Time                          // zero if kept until the end
+	spec    *deployment.PlacementSpecification // nil until created
+	created bool
+	deleted bool
+}
+
+// NewDeploymentPlugin creates the plugin, the deployment types of all services must be known to the orchestrator.
+func NewDeploymentPlugin(orchestrator *deployment.DeploymentOrchestrator, config configs.DeploymentConfig, simStart time.Time) (*DeploymentPlugin, error) {
+	p := &DeploymentPlugin{
+		orchestrator: orchestrator,
+		config:       config,
+		simStart:     simStart,
+	}
+	names := make(map[string]bool)
+	for _, cfg := range config.Services {
+		if names[cfg.Name] {
+			return nil, fmt.Errorf("duplicate service: %s", cfg.Name)
+		}
+		names[cfg.Name] = true
+		if t := deployment.DeploymentTypeOf(cfg); !slices.Contains(orchestrator.DeploymentTypes(), t) {
+			return nil, fmt.Errorf("unknown deployment type of service %s: %s", cfg.Name, t)
+		}
+		if _, err := deployment.NewNodeSelector(cfg.NodeSelector); err != nil {
+			return nil, fmt.Errorf("invalid node selector of service %s: %w", cfg.Name, err)
+		}
+		if cfg.Autoscaling != nil && len(cfg.Users) == 0 {
+			return nil, fmt.Errorf("autoscaling of service %s requires users", cfg.Name)
+		}
+
+		d := &declaredDeployment{config: cfg, start: cfg.Start}
+		if d.start.IsZero() {
+			d.start = simStart
+		}
+		if cfg.Duration > 0 {
+			d.stop = d.start.Add(helper.Seconds(cfg.Duration))
+		}
+		p.deployments = append(p.deployments, d)
+	}
+	return p, nil
+}
+
+func (p *DeploymentPlugin) Name() string {
+	return "DeploymentPlugin"
+}
+
+// Specification returns the specification of a declared service, false if it has not been deployed yet.
+func (p *DeploymentPlugin) Specification(service string) (*deployment.PlacementSpecification, bool) {
+	for _, d := range p.deployments {
+		if d.config.Name == service && d.spec != nil {
+			return d.spec, true
+		}
+	}
+	return nil, false
+}
+
+// PostSimulationStep creates the deployments which started and deletes the ones which ended
+func (p *DeploymentPlugin) PostSimulationStep(simulation types.SimulationController) error {
+	if !p.registrySet {
+		p.registrySet = true
+		if err := p.setRegistry(simulation); err != nil {
+			return err
+		}
+	}
+	now := simulation.GetSimulationTime()
+	var errs []error
+	for _, d := range p.deployments {
+		if !d.created && !now.Before(d.start) {
+			d.created = true // a specification which cannot be created is not retried every step
+			spec, err := deployment.NewSpecificationFromConfig(d.config, simulation, p.simStart)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			d.spec = spec
+			log.Printf("Deploying %d replicas of %s (%s)", max(d.config.Replicas, 1), d.config.Name, spec.Type())
+			if err := p.orchestrator.CreateDeploymentAsync(spec); err != nil {
+				errs = append(errs, err)
+			}
+			if d.config.Autoscaling != nil {
+				errs = append(errs, p.orchestrator.Autoscaler().Attach(spec, *d.config.Autoscaling))
+			}
+		}
+		if d.spec != nil && !d.deleted && !d.stop.IsZero() && !now.Before(d.stop) {
+			d.deleted = true
+			log.Printf("Deleting deployment of %s", d.config.Name)
+			if err := p.orchestrator.DeleteDeploymentAsync(d.spec); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	errs = append(errs, p.writeMigrations(now), p.writeSlo())
+
+	if p.lifecycleWriter != nil {
+		errs = append(errs, p.lifecycleWriter.Flush())
+	}
+	return errors.Join(errs...)
+}
+
+// writeMigrations writes the migration statistics of the deployed services with migration policy.
+func (p *DeploymentPlugin) writeMigrations(now time.Time) error {
+	if p.config.MigrationFile == "" {
+		return nil
+	}
+	if p.migrationWriter == nil {
+		file, writer, err := createCSV(p.config.MigrationFile, "time,service,latency_ms,migrations,failed,downtime_s,violation_s")
+		if err != nil {
+			return err
+		}
+		p.migrationFile, p.migrationWriter = file, writer
+	}
+	for _, d := range p.deployments {
+		if d.spec == nil || d.deleted {
+			continue
+		}
+		stats, ok := p.orchestrator.MigrationStats(d.spec)
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(p.migrationWriter, "%s,%s,%.3f,%d,%d,%.3f,%.3f\n", now.Format(time.RFC3339), d.config.Name,
+			stats.Latency, stats.Migrations, stats.Failed, stats.Downtime.Seconds(), stats.ViolationTime.Seconds())
+	}
+	return p.migrationWriter.Flush()
+}
+
+// writeSlo writes the last SLO evaluation of the deployed services with autoscaling policy.
+func (p *DeploymentPlugin) writeSlo() error {
+	if p.config.SloFile == "" {
+		return nil
+	}
+	if p.sloWriter == nil {
+		file, writer, err := createCSV(p.config.SloFile, "time,service,replicas,latency_ms,utilization,latency_met,utilization_met,action")
+		if err != nil {
+			return err
+		}
+		p.sloFile, p.sloWriter = file, writer
+	}
+	for _, d := range p.deployments {
+		if d.spec == nil || d.deleted || d.config.Autoscaling == nil {
+			continue
+		}
+		s, ok := p.orchestrator.Autoscaler().Latest(d.spec)
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(p.sloWriter, "%s,%s,%d,%.3f,%.3f,%t,%t,%s\n", s.Time.Format(time.RFC3339), d.config.Name,
+			s.Replicas, s.Latency, s.Utilization, s.LatencyMet, s.UtilizationMet, s.Action)
+	}
+	return p.sloWriter.Flush()
+}
+
+// OnTransition writes the state transition of a replica
+func (p *DeploymentPlugin) OnTransition(t deployment.Transition) {
+	if p.config.LifecycleFile == "" {
+		return
+	}
+	if p.lifecycleWriter == nil {
+		file, writer, err := createCSV(p.config.LifecycleFile, "time,service,node,from,to")
+		if err != nil {
+			log.Printf("Failed to create lifecycle file: %v", err)
+			p.config.LifecycleFile = ""
+			return
+		}
+		p.lifecycleFile, p.lifecycleWriter = file, writer
+	}
+	fmt.Fprintf(p.lifecycleWriter, "%s,%s,%s,%s,%s\n", t.Time.Format(time.RFC3339Nano), t.Replica.GetServiceName(), t.Node.GetName(), t.From, t.To)
+}
+
+// Close flushes and closes the output files
+func (p *DeploymentPlugin) Close() error {
+	return errors.Join(closeOutput(p.lifecycleFile, p.lifecycleWriter), closeOutput(p.migrationFile, p.migrationWriter),
+		closeOutput(p.sloFile, p.sloWriter))
+}
+
+// setRegistry sets the configured registry node on the lifecycle of the orchestrator.
+func (p *DeploymentPlugin) setRegistry(simulation types.SimulationController) error {
+	if p.config.Registry == "" {
+		return nil
+	}
+	for _, n := range simulation.GetAllNodes() {
+		if n.GetName() == p.config.Registry {
+			p.orchestrator.Lifecycle().SetRegistry(n)
+			return nil
+		}
+	}
+	return fmt.Errorf("registry node %s not found", p.config.Registry)
+}
@@ -0,0 +1,204 @@
+package simplugin
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/internal/faas"
+	"github.com/keniack/stardustGo/pkg/helper"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var _ types.SimulationPlugin = (*FaasPlugin)(nil)
+var _ io.Closer = (*FaasPlugin)(nil)
+
+// Arrival processes of the invocations of a client
+const (
+	PoissonArrivals  = "poisson"  // exponentially distributed times between invocations
+	PeriodicArrivals = "periodic" // one invocation every 1/Rate seconds
+)
+
+// FaasPlugin runs the serverless platform: it issues the declared invocations between two steps, executes them
+// on the platform and writes the latency of every completed invocation including the routes to and from its node.
+// Invocations are routed over the topology of the step after they were issued.
+type FaasPlugin struct {
+	config   configs.FaasConfig
+	platform *faas.Platform
+	sources  []*invocationSource
+	rand     *rand.Rand
+	resolved bool
+
+	file   *os.File
+	writer *bufio.Writer
+}
+
+// invocationSource tracks the invocations of a declared client group.
+type invocationSource struct {
+	config  configs.InvocationConfig
+	clients []types.Node
+	next    []time.Time // next invocation per client, zero until the first step
+}
+
+// NewFaasPlugin creates the plugin, the functions and clients are resolved on the first step.
+func NewFaasPlugin(config configs.FaasConfig) (*FaasPlugin, error) {
+	platform, err := faas.NewPlatform(config.Placement)
+	if err != nil {
+		return nil, err
+	}
+	p := &FaasPlugin{
+		config:   config,
+		platform: platform,
+		rand:     rand.New(rand.NewPCG(uint64(config.Seed), 0)),
+	}
+	for _, cfg := range config.Invocations {
+		if !slices.ContainsFunc(config.Functions, func(f configs.FunctionConfig) bool { return f.Name == cfg.Function }) {
+			return nil, fmt.Errorf("invocations of unknown function %s", cfg.Function)
+		}
+		if len(cfg.Clients) == 0 || cfg.Rate <= 0 {
+			return nil, fmt.Errorf("invocations of function %s need clients and a positive rate", cfg.Function)
+		}
+		cfg.Arrivals = strings.ToLower(cfg.Arrivals)
+		switch cfg.Arrivals {
+		case "":
+			cfg.Arrivals = PoissonArrivals
+		case PoissonArrivals, PeriodicArrivals:
+		default:
+			return nil, fmt.Errorf("unknown arrivals of function %s: %s", cfg.Function, cfg.Arrivals)
+		}
+		p.sources = append(p.sources, &invocationSource{config: cfg})
+	}
+	return p, nil
+}
+
+func (p *FaasPlugin) Name() string {
+	return "FaasPlugin"
+}
+
+// Platform returns the serverless platform executing the invocations.
+func (p *FaasPlugin) Platform() *faas.Platform {
+	return p.platform
+}
+
+// PostSimulationStep issues the invocations due until the simulation time, executes them and writes the completed ones
+func (p *FaasPlugin) PostSimulationStep(simulation types.SimulationController) error {
+	if !p.resolved {
+		p.resolved = true
+		if err := p.resolve(simulation); err != nil {
+			return err
+		}
+	}
+	if p.writer == nil && p.config.File != "" {
+		file, writer, err := createCSV(p.config.File, "function,client,node,issued,cold,uplink_ms,queueing_ms,cold_start_ms,execution_ms,downlink_ms,latency_ms")
+		if err != nil {
+			return err
+		}
+		p.file, p.writer = file, writer
+	}
+
+	now := simulation.GetSimulationTime()
+	p.platform.Refresh()
+
+	// Invocations are issued in time order, so the placement sees the instances at the time of each invocation
+	type issue struct {
+		at       time.Time
+		client   types.Node
+		function string
+	}
+	var issues []issue
+	for _, s := range p.sources {
+		for i, client := range s.clients {
+			if s.next[i].IsZero() {
+				s.next[i] = now
+			}
+			for ; !s.next[i].After(now); s.next[i] = s.next[i].Add(p.gap(s.config)) {
+				issues = append(issues, issue{at: s.next[i], client: client, function: s.config.Function})
+			}
+		}
+	}
+	slices.SortStableFunc(issues, func(a, b issue) int { return a.at.Compare(b.at) })
+
+	var completed []*faas.Invocation
+	for _, is := range issues {
+		completed = append(completed, p.platform.Advance(is.at)...)
+		// Unreachable nodes are counted as rejected invocations of the function
+		_, _ = p.platform.Invoke(is.client, is.function, is.at)
+	}
+	completed = append(completed, p.platform.Advance(now)...)
+
+	for _, inv := range completed {
+		if p.writer != nil {
+			fmt.Fprintf(p.writer, "%s,%s,%s,%s,%t,%.3f,%.3f,%.3f,%.3f,%.3f,%.3f\n", inv.Function, inv.Client.GetName(), inv.Node.GetName(),
+				inv.Issued.Format(time.RFC3339Nano), inv.Cold, inv.Uplink, helper.ToMilliseconds(inv.QueueingDelay()),
+				helper.ToMilliseconds(inv.ColdStartDelay()), helper.ToMilliseconds(inv.Completion.Sub(inv.Start)), inv.Downlink,
+				helper.ToMilliseconds(inv.Latency()))
+		}
+	}
+
+	var errs []error
+	if p.writer != nil {
+		errs = append(errs, p.writer.Flush())
+	}
+	errs = append(errs, p.writeSummary())
+	return errors.Join(errs...)
+}
+
+// Close flushes and closes the invocation file
+func (p *FaasPlugin) Close() error {
+	return closeOutput(p.file, p.writer)
+}
+
+// writeSummary logs and rewrites the latency distribution, cold starts and evictions of every function.
+func (p *FaasPlugin) writeSummary() error {
+	var b strings.Builder
+	b.WriteString("function,placement,invocations,completed,rejected,cold_starts,cold_start_ratio,evictions,expirations,mean_ms,p50_ms,p95_ms,p99_ms\n")
+	for _, name := range p.platform.Functions() {
+		stats := p.platform.Stats(name)
+		fmt.Fprintf(&b, "%s,%s,%d,%d,%d,%d,%.6f,%d,%d,%.3f,%.3f,%.3f,%.3f\n", name, p.platform.Placement(), stats.Invocations,
+			stats.Completed, stats.Rejected, stats.ColdStarts, stats.ColdStartRatio(), stats.Evictions, stats.Expirations,
+			stats.MeanLatency(), stats.Percentile(50), stats.Percentile(95), stats.Percentile(99))
+		log.Printf("Function %s: %d invocations completed, %.1f%% cold starts, p50 %.3f ms, p99 %.3f ms", name,
+			stats.Completed, 100*stats.ColdStartRatio(), stats.Percentile(50), stats.Percentile(99))
+	}
+	if p.config.SummaryFile == "" {
+		return nil
+	}
+	return os.WriteFile(p.config.SummaryFile, []byte(b.String()), 0644)
+}
+
+// gap returns the time until the next invocation of a client.
+func (p *FaasPlugin) gap(cfg configs.InvocationConfig) time.Duration {
+	if cfg.Arrivals == PeriodicArrivals {
+		return time.Duration(float64(time.Second) / cfg.Rate)
+	}
+	return helper.Seconds(p.rand.ExpFloat64() / cfg.Rate)
+}
+
+// resolve registers the functions on the nodes of the simulation and finds the clients.
+func (p *FaasPlugin) resolve(simulation types.SimulationController) error {
+	nodes := simulation.GetAllNodes()
+	for _, cfg := range p.config.Functions {
+		if err := p.platform.Register(cfg, nodes); err != nil {
+			return err
+		}
+	}
+	for _, s := range p.sources {
+		for _, name := range s.config.Clients {
+			i := slices.IndexFunc(nodes, func(n types.Node) bool { return n.GetName() == name })
+			if i < 0 {
+				return fmt.Errorf("client %s of function %s not found", name, s.config.Function)
+			}
+			s.clients = append(s.clients, nodes[i])
+		}
+		s.next = make([]time.Time, len(s.clients))
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package simplugin
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/keniack/stardustGo/internal/routing"
+	"github.com/keniack/stardustGo/pkg/helper"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var _ types.SimulationPlugin = (*LinkStatePlugin)(nil)
+var _ io.Closer = (*LinkStatePlugin)(nil)
+
+// LinkStatePlugin exports the convergence time and control overhead of the link-state control plane.
+// Every step appends one CSV row per control plane step since the previous one.
+type LinkStatePlugin struct {
+	controlPlane *routing.LinkStateControlPlane
+	filename     string
+	written      int // control plane steps written so far
+
+	file   *os.File
+	writer *bufio.Writer
+}
+
+// NewLinkStatePlugin creates the plugin, the output file is created on the first step.
+func NewLinkStatePlugin(controlPlane *routing.LinkStateControlPlane, filename string) *LinkStatePlugin {
+	return &LinkStatePlugin{
+		controlPlane: controlPlane,
+		filename:     filename,
+	}
+}
+
+func (p *LinkStatePlugin) Name() string {
+	return "LinkStatePlugin"
+}
+
+// PostSimulationStep writes the control plane statistics of the steps since the previous one
+func (p *LinkStatePlugin) PostSimulationStep(simulation types.SimulationController) error {
+	if p.writer == nil {
+		file, writer, err := createCSV(p.filename, "time,originated_lsas,transmissions,control_bytes,convergence_ms")
+		if err != nil {
+			return err
+		}
+		p.file, p.writer = file, writer
+	}
+
+	stats := p.controlPlane.Stats()
+	for _, s := range stats[p.written:] {
+		fmt.Fprintf(p.writer, "%s,%d,%d,%d,%.3f\n", s.Time.Format(time.RFC3339), s.OriginatedLSAs, s.Transmissions,
+			s.ControlBytes, helper.ToMilliseconds(s.ConvergenceTime))
+	}
+	p.written = len(stats)
+	return p.writer.Flush()
+}
+
+// Close flushes and closes the output file
+func (p *LinkStatePlugin) Close() error {
+	return closeOutput(p.file, p.writer)
+}
@@ -0,0 +1,224 @@
+package simplugin
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/internal/computing"
+	"github.com/keniack/stardustGo/internal/routing"
+	"github.com/keniack/stardustGo/pkg/helper"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var _ types.SimulationPlugin = (*OffloadPlugin)(nil)
+var _ io.Closer = (*OffloadPlugin)(nil)
+
+// Offload targets besides node names
+const (
+	LocalTarget = "local" // the client executes its tasks itself
+	EdgeTarget  = "edge"  // closest other Edge node of the client
+	CloudTarget = "cloud" // closest other Cloud node of the client
+)
+
+// OffloadPlugin issues the tasks of the declared workloads, executes them on their targets and writes the response
+// time of every completed task: route latency to the target, queueing, execution and route latency back.
+// Tasks are issued in simulation time between two steps and routed over the topology of the later step.
+type OffloadPlugin struct {
+	workloads []*offloadWorkload
+	config    configs.OffloadConfig
+	resolved  bool
+
+	file   *os.File
+	writer *bufio.Writer
+}
+
+// offloadWorkload tracks the tasks of a declared workload.
+type offloadWorkload struct {
+	config    configs.OffloadWorkloadConfig
+	clients   []types.Node
+	target    types.Node // nil for local, edge and cloud
+	nextIssue time.Time  // zero until the first step
+	issued    int
+	pending   []*computing.Offloading
+}
+
+// NewOffloadPlugin creates the plugin, the clients and targets are resolved on the first step.
+func NewOffloadPlugin(config configs.OffloadConfig) (*OffloadPlugin, error) {
+	p := &OffloadPlugin{config: config}
+	names := make(map[string]bool)
+	for _, cfg := range config.Workloads {
+		if cfg.Name == "" || names[cfg.Name] {
+			return nil, fmt.Errorf("workload name missing or duplicate: %q", cfg.Name)
+		}
+		names[cfg.Name] = true
+		if len(cfg.Clients) == 0 || cfg.Target == "" {
+			return nil, fmt.Errorf("workload %s needs clients and a target", cfg.Name)
+		}
+		if cfg.Work < 0 || cfg.Interval <= 0 {
+			return nil, fmt.Errorf("workload %s needs a non-negative work and a positive interval", cfg.Name)
+		}
+		cfg.Target = strings.TrimSpace(cfg.Target)
+		p.workloads = append(p.workloads, &offloadWorkload{config: cfg})
+	}
+	return p, nil
+}
+
+func (p *OffloadPlugin) Name() string {
+	return "OffloadPlugin"
+}
+
+// PostSimulationStep issues the tasks due until the simulation time, executes them and writes the completed ones
+func (p *OffloadPlugin) PostSimulationStep(simulation types.SimulationController) error {
+	if !p.resolved {
+		p.resolved = true
+		if err := p.resolve(simulation); err != nil {
+			return err
+		}
+	}
+	if p.writer == nil && p.config.File != "" {
+		file, writer, err := createCSV(p.config.File, "workload,client,target,issued,uplink_ms,queueing_ms,execution_ms,downlink_ms,response_ms")
+		if err != nil {
+			return err
+		}
+		p.file, p.writer = file, writer
+	}
+
+	now := simulation.GetSimulationTime()
+	var errs []error
+	executors := make(map[*computing.Executor]bool)
+	for _, w := range p.workloads {
+		errs = append(errs, p.issue(w, simulation, now))
+		for _, o := range w.pending {
+			if executor, err := computing.ExecutorOf(o.Target); err == nil {
+				executors[executor] = true
+			}
+		}
+	}
+	for executor := range executors {
+		executor.Advance(now)
+	}
+
+	for _, w := range p.workloads {
+		var pending []*computing.Offloading
+		for _, o := range w.pending {
+			if !o.Done() {
+				pending = append(pending, o)
+				continue
+			}
+			if p.writer != nil {
+				fmt.Fprintf(p.writer, "%s,%s,%s,%s,%.3f,%.3f,%.3f,%.3f,%.3f\n", w.config.Name, o.Client.GetName(), o.Target.GetName(),
+					o.Issued.Format(time.RFC3339Nano), o.Uplink, helper.ToMilliseconds(o.Execution.QueueingDelay()),
+					helper.ToMilliseconds(o.Execution.Completion.Sub(o.Execution.Start)), o.Downlink, helper.ToMilliseconds(o.ResponseTime()))
+			}
+		}
+		w.pending = pending
+	}
+	if p.writer != nil {
+		errs = append(errs, p.writer.Flush())
+	}
+	return errors.Join(errs...)
+}
+
+// Close flushes and closes the task file
+func (p *OffloadPlugin) Close() error {
+	return closeOutput(p.file, p.writer)
+}
+
+// issue offloads the tasks of every client which are due until now.
+func (p *OffloadPlugin) issue(w *offloadWorkload, simulation types.SimulationController, now time.Time) error {
+	if w.nextIssue.IsZero() {
+		w.nextIssue = now
+	}
+	interval := helper.Seconds(w.config.Interval)
+	targets := make(map[types.Node]types.Node)
+	var errs []error
+	for ; !w.nextIssue.After(now); w.nextIssue = w.nextIssue.Add(interval) {
+		for _, client := range w.clients {
+			target, ok := targets[client]
+			if !ok {
+				target = p.target(w, client, simulation)
+				targets[client] = target
+			}
+			if target == nil {
+				errs = append(errs, fmt.Errorf("workload %s: no target reachable from %s", w.config.Name, client.GetName()))
+				continue
+			}
+			task := computing.Task{ID: fmt.Sprintf("%s-%d", w.config.Name, w.issued), Work: w.config.Work}
+			w.issued++
+			o, err := computing.Offload(client, target, task, w.nextIssue)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			w.pending = append(w.pending, o)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// target returns the node executing the tasks of the client, nil if none is reachable. Edge and cloud targets
+// are other nodes than the client.
+func (p *OffloadPlugin) target(w *offloadWorkload, client types.Node, simulation types.SimulationController) types.Node {
+	var computingType types.ComputingType
+	switch strings.ToLower(w.config.Target) {
+	case LocalTarget:
+		return client
+	case EdgeTarget:
+		computingType = types.Edge
+	case CloudTarget:
+		computingType = types.Cloud
+	default:
+		return w.target
+	}
+
+	var best types.Node
+	bestLatency := math.Inf(1)
+	for _, n := range simulation.GetAllNodes() {
+		if n == client || n.GetComputing().GetComputingType() != computingType {
+			continue
+		}
+		if l := routing.RouteLatency(client, n); l < bestLatency {
+			best, bestLatency = n, l
+		}
+	}
+	return best
+}
+
+// resolve finds the clients and the named target of all workloads.
+func (p *OffloadPlugin) resolve(simulation types.SimulationController) error {
+	nodes := simulation.GetAllNodes()
+	find := func(name string) (types.Node, error) {
+		i := slices.IndexFunc(nodes, func(n types.Node) bool { return n.GetName() == name })
+		if i < 0 {
+			return nil, fmt.Errorf("node %s not found", name)
+		}
+		return nodes[i], nil
+	}
+	for _, w := range p.workloads {
+		for _, name := range w.config.Clients {
+			client, err := find(name)
+			if err != nil {
+				return fmt.Errorf("workload %s: %w", w.config.Name, err)
+			}
+			w.clients = append(w.clients, client)
+		}
+		switch strings.ToLower(w.config.Target) {
+		case LocalTarget, EdgeTarget, CloudTarget:
+		default:
+			target, err := find(w.config.Target)
+			if err != nil {
+				return fmt.Errorf("workload %s: %w", w.config.Name, err)
+			}
+			w.target = target
+		}
+	}
+	return nil
+}
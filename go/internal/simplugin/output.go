@@ -0,0 +1,27 @@
+package simplugin
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// createCSV creates the file and writes the header.
+func createCSV(path string, header string) (*os.File, *bufio.Writer, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	w := bufio.NewWriter(file)
+	fmt.Fprintln(w, header)
+	return file, w, nil
+}
+
+// closeOutput flushes the writer and closes its file, files which were never created are skipped.
+func closeOutput(file *os.File, w *bufio.Writer) error {
+	if file == nil {
+		return nil
+	}
+	return errors.Join(w.Flush(), file.Close())
+}
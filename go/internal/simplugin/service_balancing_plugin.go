@@ -0,0 +1,79 @@
+package simplugin
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/keniack/stardustGo/internal/routing"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var _ types.SimulationPlugin = (*ServiceBalancingPlugin)(nil)
+var _ io.Closer = (*ServiceBalancingPlugin)(nil)
+
+// ServiceBalancingPlugin exports the requests the service balancer assigned to every replica after each simulation step.
+// Every step appends one CSV row per service and replica which received requests since the previous step.
+type ServiceBalancingPlugin struct {
+	balancer *routing.ServiceBalancer
+	filename string
+	previous map[string]map[types.Node]routing.ReplicaLoad
+
+	file   *os.File
+	writer *bufio.Writer
+}
+
+// NewServiceBalancingPlugin creates the plugin, the output file is created on the first step.
+func NewServiceBalancingPlugin(balancer *routing.ServiceBalancer, filename string) *ServiceBalancingPlugin {
+	return &ServiceBalancingPlugin{
+		balancer: balancer,
+		filename: filename,
+		previous: make(map[string]map[types.Node]routing.ReplicaLoad),
+	}
+}
+
+func (p *ServiceBalancingPlugin) Name() string {
+	return "ServiceBalancingPlugin"
+}
+
+// PostSimulationStep writes the requests per replica since the previous step
+func (p *ServiceBalancingPlugin) PostSimulationStep(simulation types.SimulationController) error {
+	if p.writer == nil {
+		file, writer, err := createCSV(p.filename, "time,service,policy,replica,requests,mean_latency_ms,cpu_available")
+		if err != nil {
+			return err
+		}
+		p.file, p.writer = file, writer
+	}
+
+	simTime := simulation.GetSimulationTime().Format(time.RFC3339)
+	for _, service := range p.balancer.Services() {
+		loads := p.balancer.Loads(service)
+		replicas := make([]types.Node, 0, len(loads))
+		for n := range loads {
+			replicas = append(replicas, n)
+		}
+		sort.Slice(replicas, func(i, j int) bool { return replicas[i].GetName() < replicas[j].GetName() })
+
+		for _, n := range replicas {
+			load, last := loads[n], p.previous[service][n]
+			requests := load.Requests - last.Requests
+			if requests == 0 {
+				continue
+			}
+			latency := (load.LatencySum - last.LatencySum) / float64(requests)
+			fmt.Fprintf(p.writer, "%s,%s,%s,%s,%d,%.3f,%.3f\n", simTime, service, p.balancer.Policy(), n.GetName(),
+				requests, latency, n.GetComputing().CpuAvailable())
+		}
+		p.previous[service] = loads
+	}
+	return p.writer.Flush()
+}
+
+// Close flushes and closes the output file
+func (p *ServiceBalancingPlugin) Close() error {
+	return closeOutput(p.file, p.writer)
+}
@@ -0,0 +1,149 @@
+// Package simtest provides small topologies of nodes with fixed links for the tests of the simulation packages.
+package simtest
+
+import (
+	"time"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var _ types.Node = (*Node)(nil)
+var _ types.GroundStation = (*GroundStation)(nil)
+var _ types.LinkNodeProtocol = (*linkProtocol)(nil)
+var _ types.Link = (*Link)(nil)
+
+// Node is a node at a fixed position whose links are established unless they are down.
+// Router and Computing are mounted by Mount.
+type Node struct {
+	Name      string
+	Position  types.Vector
+	Router    types.Router
+	Computing types.Computing
+
+	self  types.Node // outermost node, the ground station for ground stations
+	links linkProtocol
+}
+
+// NewNode creates a node without links, router and computing.
+func NewNode(name string) *Node {
+	n := &Node{Name: name}
+	n.self = n
+	return n
+}
+
+// Mount attaches the router and the computing unit to the node, nil values are skipped.
+func (n *Node) Mount(router types.Router, computing types.Computing) error {
+	if router != nil {
+		n.Router = router
+		if err := router.Mount(n.self); err != nil {
+			return err
+		}
+	}
+	if computing != nil {
+		n.Computing = computing
+		if err := computing.Mount(n.self); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *Node) GetName() string               { return n.Name }
+func (n *Node) GetRouter() types.Router       { return n.Router }
+func (n *Node) GetComputing() types.Computing { return n.Computing }
+func (n *Node) GetPosition() types.Vector     { return n.Position }
+func (n *Node) UpdatePosition(time.Time)      {}
+
+func (n *Node) DistanceTo(other types.Node) float64 {
+	return n.Position.Subtract(other.GetPosition()).Magnitude()
+}
+
+func (n *Node) GetLinkNodeProtocol() types.LinkNodeProtocol {
+	return &n.links
+}
+
+// GroundStation is a node on the ground with tags and a weight.
+type GroundStation struct {
+	*Node
+	Tags   []string
+	Weight float64
+}
+
+// NewGroundStation creates a ground station without links, router and computing.
+func NewGroundStation(name string, tags ...string) *GroundStation {
+	gs := &GroundStation{Node: NewNode(name), Tags: tags}
+	gs.self = gs
+	return gs
+}
+
+func (gs *GroundStation) GetTags() []string  { return gs.Tags }
+func (gs *GroundStation) GetWeight() float64 { return gs.Weight }
+
+// linkProtocol holds the links of a node.
+type linkProtocol struct {
+	links []*Link
+}
+
+func (p *linkProtocol) Mount(types.Node)                {}
+func (p *linkProtocol) ConnectLink(types.Link) error    { return nil }
+func (p *linkProtocol) DisconnectLink(types.Link) error { return nil }
+
+func (p *linkProtocol) UpdateLinks() ([]types.Link, error) {
+	return p.Established(), nil
+}
+
+// Established returns the links which are not down
+func (p *linkProtocol) Established() []types.Link {
+	var links []types.Link
+	for _, l := range p.links {
+		if !l.Down {
+			links = append(links, l)
+		}
+	}
+	return links
+}
+
+func (p *linkProtocol) Links() []types.Link {
+	links := make([]types.Link, len(p.links))
+	for i, l := range p.links {
+		links[i] = l
+	}
+	return links
+}
+
+// Link connects two nodes with a fixed latency and bandwidth. A link which is down is not established.
+type Link struct {
+	A, B      types.Node
+	LatencyMs float64
+	Bps       float64
+	Down      bool
+}
+
+// Connect links the two nodes with the latency in ms and the bandwidth in bits per second.
+func Connect(a, b types.Node, latency, bandwidth float64) *Link {
+	l := &Link{A: a, B: b, LatencyMs: latency, Bps: bandwidth}
+	protocolOf(a).links = append(protocolOf(a).links, l)
+	protocolOf(b).links = append(protocolOf(b).links, l)
+	return l
+}
+
+// protocolOf returns the link protocol of a node of this package.
+func protocolOf(n types.Node) *linkProtocol {
+	return n.GetLinkNodeProtocol().(*linkProtocol)
+}
+
+func (l *Link) Distance() float64               { return l.A.DistanceTo(l.B) }
+func (l *Link) Latency() float64                { return l.LatencyMs }
+func (l *Link) Bandwidth() float64              { return l.Bps }
+func (l *Link) IsReachable() bool               { return !l.Down }
+func (l *Link) Nodes() (types.Node, types.Node) { return l.A, l.B }
+
+func (l *Link) GetOther(self types.Node) types.Node {
+	switch self {
+	case l.A:
+		return l.B
+	case l.B:
+		return l.A
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package simtest
+
+import (
+	"time"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var _ types.SimulationController = (*Simulation)(nil)
+
+// Simulation holds nodes at a simulation time which only moves when stepped, without plugins.
+type Simulation struct {
+	Nodes []types.Node
+	Time  time.Time
+}
+
+// NewSimulation creates a simulation of the nodes at the given time.
+func NewSimulation(at time.Time, nodes ...types.Node) *Simulation {
+	return &Simulation{Nodes: nodes, Time: at}
+}
+
+func (s *Simulation) InjectSatellites(nodes []types.Node) error {
+	s.Nodes = append(s.Nodes, nodes...)
+	return nil
+}
+
+func (s *Simulation) InjectGroundStations(nodes []types.Node) error {
+	s.Nodes = append(s.Nodes, nodes...)
+	return nil
+}
+
+// StartAutorun returns a closed channel, the simulation only moves when stepped
+func (s *Simulation) StartAutorun() <-chan struct{} {
+	done := make(chan struct{})
+	close(done)
+	return done
+}
+
+func (s *Simulation) StopAutorun() {}
+
+func (s *Simulation) StepBySeconds(seconds float64) {
+	s.Time = s.Time.Add(time.Duration(seconds * float64(time.Second)))
+}
+
+func (s *Simulation) StepByTime(newTime time.Time) {
+	s.Time = newTime
+}
+
+func (s *Simulation) GetAllNodes() []types.Node {
+	return s.Nodes
+}
+
+func (s *Simulation) GetSatellites() []types.Satellite {
+	var satellites []types.Satellite
+	for _, n := range s.Nodes {
+		if sat, ok := n.(types.Satellite); ok {
+			satellites = append(satellites, sat)
+		}
+	}
+	return satellites
+}
+
+func (s *Simulation) GetGroundStations() []types.GroundStation {
+	var stations []types.GroundStation
+	for _, n := range s.Nodes {
+		if gs, ok := n.(types.GroundStation); ok {
+			stations = append(stations, gs)
+		}
+	}
+	return stations
+}
+
+func (s *Simulation) GetSimulationTime() time.Time {
+	return s.Time
+}
+
+func (s *Simulation) GetStatePluginRepository() *types.StatePluginRepository {
+	return nil
+}
+
+func (s *Simulation) Close() {}
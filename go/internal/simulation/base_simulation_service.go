@@ -2,6 +2,7 @@ package simulation
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"sync"
 	"time"
@@ -152,3 +153,21 @@ func (s *BaseSimulationService) StepByTime(newTime time.Time) {
 func (s *BaseSimulationService) setSimulationTime(time time.Time) {
 	s.simTime = time
 }
+
+// closePlugins closes the simulation and state plugins holding resources, e.g. output files.
+func closePlugins(simPlugins []types.SimulationPlugin, statePlugins []types.StatePlugin) {
+	for _, plugin := range simPlugins {
+		if closer, ok := plugin.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				log.Printf("Failed to close simulation plugin %s: %v", plugin.Name(), err)
+			}
+		}
+	}
+	for _, plugin := range statePlugins {
+		if closer, ok := plugin.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				log.Printf("Failed to close state plugin %s: %v", plugin.GetName(), err)
+			}
+		}
+	}
+}
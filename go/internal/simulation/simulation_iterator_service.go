@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/internal/deployment"
+	"github.com/keniack/stardustGo/internal/routing"
 	"github.com/keniack/stardustGo/pkg/types"
 )
 
@@ -14,6 +16,7 @@ var _ types.SimulationController = (*SimulationIteratorService)(nil)
 type SimulationIteratorService struct {
 	BaseSimulationService
 
+	routerBuilder         *routing.RouterBuilder
 	simulationStates      []types.SimulationState
 	simPlugins            []types.SimulationPlugin
 	statePluginRepository types.StatePluginRepository
@@ -21,8 +24,9 @@ type SimulationIteratorService struct {
 	currentIx             int
 }
 
-func NewSimulationIteratorService(config *configs.SimulationConfig, simulationStates []types.SimulationState, simPlugins []types.SimulationPlugin, statePluginRepository types.StatePluginRepository) *SimulationIteratorService {
+func NewSimulationIteratorService(config *configs.SimulationConfig, routerBuilder *routing.RouterBuilder, simulationStates []types.SimulationState, simPlugins []types.SimulationPlugin, statePluginRepository types.StatePluginRepository) *SimulationIteratorService {
 	service := &SimulationIteratorService{
+		routerBuilder:         routerBuilder,
 		simulationStates:      simulationStates,
 		simPlugins:            simPlugins,
 		statePluginRepository: statePluginRepository,
@@ -33,12 +37,18 @@ func NewSimulationIteratorService(config *configs.SimulationConfig, simulationSt
 	return service
 }
 
+// Inject sets the orchestrator and mounts it to the simulation
+func (s *SimulationIteratorService) Inject(o *deployment.DeploymentOrchestrator) {
+	s.BaseSimulationService.Inject(o)
+	o.Mount(s)
+}
+
 func (s *SimulationIteratorService) GetStatePluginRepository() *types.StatePluginRepository {
 	return &s.statePluginRepository
 }
 
 func (s *SimulationIteratorService) Close() {
-
+	closePlugins(s.simPlugins, s.statePluginRepository.GetAllPlugins())
 }
 
 func (s *SimulationIteratorService) runSimulationStep(nextTime func(time.Time) time.Time) {
@@ -78,6 +88,9 @@ func (s *SimulationIteratorService) runSimulationStep(nextTime func(time.Time) t
 	}
 	wg.Wait()
 
+	// Topology changed, so shared routing state of the previous step is stale
+	s.routerBuilder.UpdateTopology(s.simTime)
+
 	// Routing and computation (if enabled)
 	if s.config.UsePreRouteCalc {
 		for _, node := range s.all {
@@ -93,7 +106,9 @@ func (s *SimulationIteratorService) runSimulationStep(nextTime func(time.Time) t
 	// Check if the orchestrator needs to reschedule
 	if s.orchestrator != nil {
 		log.Println("Checking orchestrator for reschedule...")
-		// s.orchestrator.CheckReschedule()
+		if err := s.orchestrator.CheckReschedule(); err != nil {
+			log.Printf("Orchestrator reschedule error: %v", err)
+		}
 	}
 
 	// Execute post-step state plugins
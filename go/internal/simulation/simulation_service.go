@@ -7,6 +7,7 @@ import (
 
 	"github.com/keniack/stardustGo/configs"
 	"github.com/keniack/stardustGo/internal/computing"
+	"github.com/keniack/stardustGo/internal/deployment"
 	"github.com/keniack/stardustGo/internal/routing"
 	"github.com/keniack/stardustGo/pkg/types"
 )
@@ -52,6 +53,12 @@ func NewSimulationService(
 	return simService
 }
 
+// Inject sets the orchestrator and mounts it to the simulation
+func (s *SimulationService) Inject(o *deployment.DeploymentOrchestrator) {
+	s.BaseSimulationService.Inject(o)
+	o.Mount(s)
+}
+
 func (s *SimulationService) GetStatePluginRepository() *types.StatePluginRepository {
 	return s.statePluginRepo
 }
@@ -60,6 +67,7 @@ func (s *SimulationService) Close() {
 	if s.simulationStateSerializer != nil {
 		s.simulationStateSerializer.Save(s)
 	}
+	closePlugins(s.simplugins, s.statePluginRepo.GetAllPlugins())
 }
 
 // runSimulationStep is the core loop to simulate node and orchestrator logic
@@ -99,6 +107,9 @@ func (s *SimulationService) runSimulationStep(nextTime func(time.Time) time.Time
 	}
 	wg.Wait()
 
+	// Topology changed, so shared routing state of the previous step is stale
+	s.routerBuilder.UpdateTopology(s.simTime)
+
 	// Routing and computation (if enabled)
 	if s.config.UsePreRouteCalc {
 		for _, node := range s.all {
@@ -114,7 +125,9 @@ func (s *SimulationService) runSimulationStep(nextTime func(time.Time) time.Time
 	// Check if the orchestrator needs to reschedule
 	if s.orchestrator != nil {
 		log.Println("Checking orchestrator for reschedule...")
-		// s.orchestrator.CheckReschedule()
+		if err := s.orchestrator.CheckReschedule(); err != nil {
+			log.Printf("Orchestrator reschedule error: %v", err)
+		}
 	}
 
 	// Execute post-step state plugins
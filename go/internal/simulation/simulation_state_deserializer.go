@@ -69,8 +69,11 @@ func (d *SimulationStateDeserializer) LoadIterator() types.SimulationController
 	nodeNames := make(map[string]node.PrecomputedNode)
 	satellites := make([]types.Node, len(metadata.Satellites))
 	for i, sat := range metadata.Satellites {
-		router, _ := d.routerBuilder.Build()
-		computing := d.computingBuilder.Build()
+		router, err := d.routerBuilder.BuildProtocol(sat.Router)
+		if err != nil {
+			log.Fatalf("Failed to build router of %s: %v", sat.Name, err)
+		}
+		computing := d.computingBuilder.WithComputingType(sat.ComputingType).WithNode(computing.NodeDescriptor{Name: sat.Name, Satellite: true}).WithProfile(sat.Profile).Build()
 		satellite := node.NewSimulatedSatellite(sat.Name, router, computing, links.NewLinkFilterProtocol(innerProtocol))
 		satellites[i] = satellite
 		nodeNames[sat.Name] = satellite
@@ -78,9 +81,14 @@ func (d *SimulationStateDeserializer) LoadIterator() types.SimulationController
 
 	groundStations := make([]types.Node, len(metadata.Grounds))
 	for i, gs := range metadata.Grounds {
-		router, _ := d.routerBuilder.Build()
-		computing := d.computingBuilder.Build()
+		router, err := d.routerBuilder.BuildProtocol(gs.Router)
+		if err != nil {
+			log.Fatalf("Failed to build router of %s: %v", gs.Name, err)
+		}
+		computing := d.computingBuilder.WithComputingType(gs.ComputingType).WithNode(computing.NodeDescriptor{Name: gs.Name, Tags: gs.Tags}).WithProfile(gs.Profile).Build()
 		groundStation := node.NewSimulatedGroundStation(gs.Name, router, computing, links.NewLinkFilterProtocol(innerProtocol))
+		groundStation.Tags = gs.Tags
+		groundStation.Weight = gs.Weight
 		groundStations[i] = groundStation
 		nodeNames[gs.Name] = groundStation
 	}
@@ -127,11 +135,24 @@ func (d *SimulationStateDeserializer) LoadIterator() types.SimulationController
 		}
 	}
 
+	// Contact plan of all states for store-and-forward routing
+	times := make([]time.Time, len(metadata.States))
+	for i, state := range metadata.States {
+		times[i] = state.Time
+	}
+	allNodes := append(append([]types.Node{}, satellites...), groundStations...)
+	latency := func(i int, l types.Link) float64 {
+		n1, n2 := l.Nodes()
+		p1, p2 := positions[n1.GetName()][i].position, positions[n2.GetName()][i].position
+		return linktypes.PropagationLatency(p1.Subtract(p2).Magnitude())
+	}
+	d.routerBuilder.ContactGraph().SetContactPlan(routing.NewContactPlan(allNodes, times, establishedLinks, latency))
+
 	// STATE PLUGINS
 	plugins, _ := d.statePluginBuilder.BuildPlugins(metadata.StatePlugins)
 	statePluginRepository := *types.NewStatePluginRepository(plugins)
 
-	simService := NewSimulationIteratorService(d.config, metadata.States, d.simPlugins, statePluginRepository)
+	simService := NewSimulationIteratorService(d.config, d.routerBuilder, metadata.States, d.simPlugins, statePluginRepository)
 	simService.Inject(d.orchestrator)
 	simService.InjectSatellites(satellites)
 	simService.InjectGroundStations(groundStations)
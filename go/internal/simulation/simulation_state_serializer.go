@@ -6,6 +6,8 @@ import (
 	"log"
 	"os"
 
+	"github.com/keniack/stardustGo/internal/computing"
+	"github.com/keniack/stardustGo/internal/routing"
 	"github.com/keniack/stardustGo/pkg/types"
 )
 
@@ -66,6 +68,8 @@ func (s *SimulationStateSerializer) Save(simualtionController types.SimulationCo
 			Index:         i,
 			Name:          sat.GetName(),
 			ComputingType: sat.GetComputing().GetComputingType(),
+			Profile:       computing.ProfileOf(sat.GetComputing()),
+			Router:        routing.ProtocolOf(sat.GetRouter()),
 		}
 	}
 
@@ -74,6 +78,10 @@ func (s *SimulationStateSerializer) Save(simualtionController types.SimulationCo
 		s.metadata.Grounds[i] = types.RawGroundStation{
 			Name:          gs.GetName(),
 			ComputingType: gs.GetComputing().GetComputingType(),
+			Profile:       computing.ProfileOf(gs.GetComputing()),
+			Router:        routing.ProtocolOf(gs.GetRouter()),
+			Tags:          gs.GetTags(),
+			Weight:        gs.GetWeight(),
 		}
 	}
 
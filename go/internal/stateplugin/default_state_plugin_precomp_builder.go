@@ -2,6 +2,7 @@ package stateplugin
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/keniack/stardustGo/pkg/types"
 )
@@ -10,6 +11,7 @@ var _ types.StatePluginBuilder = (*DefaultStatePluginPrecompBuilder)(nil)
 
 type DefaultStatePluginPrecompBuilder struct {
 	filename string
+	live     []types.StatePlugin // plugins evaluating the loaded states again instead of reading precomputed ones
 }
 
 // NewStatePluginPrecompBuilder creates a new instance of StatePluginPrecompBuilder
@@ -19,10 +21,18 @@ func NewStatePluginPrecompBuilder(filename string) *DefaultStatePluginPrecompBui
 	}
 }
 
+// AddLivePlugin adds a plugin which is built in addition to the precomputed ones and evaluates the loaded states again.
+func (pb *DefaultStatePluginPrecompBuilder) AddLivePlugin(plugin types.StatePlugin) {
+	pb.live = append(pb.live, plugin)
+}
+
 // BuildPlugins constructs plugin instances based on provided names
 func (pb *DefaultStatePluginPrecompBuilder) BuildPlugins(pluginNames []string) ([]types.StatePlugin, error) {
-	var plugins []types.StatePlugin
+	plugins := append([]types.StatePlugin(nil), pb.live...)
 	for _, name := range pluginNames {
+		if slices.ContainsFunc(pb.live, func(p types.StatePlugin) bool { return p.GetName() == name }) {
+			continue
+		}
 		switch name {
 		case "DummySunStatePlugin":
 			plugins = append(plugins, NewDummySunStatePrecompPlugin(pb.filename))
@@ -40,8 +40,7 @@ func (d *DummySunStatePlugin) GetName() string {
 }
 
 func (d *DummySunStatePlugin) GetType() reflect.Type {
-	var dummy SunStatePlugin
-	return reflect.TypeOf(dummy)
+	return reflect.TypeOf((*SunStatePlugin)(nil)).Elem()
 }
 
 // PostSimulationStep updates the sunlight exposure for each satellite
@@ -46,8 +46,7 @@ func (p *DummySunStatePluginPrecomp) GetName() string {
 }
 
 func (d *DummySunStatePluginPrecomp) GetType() reflect.Type {
-	var dummy SunStatePlugin
-	return reflect.TypeOf(dummy)
+	return reflect.TypeOf((*SunStatePlugin)(nil)).Elem()
 }
 
 func (p *DummySunStatePluginPrecomp) PostSimulationStep(simulationController types.SimulationController) {
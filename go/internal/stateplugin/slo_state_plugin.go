@@ -0,0 +1,401 @@
+package stateplugin
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/internal/deployment"
+	"github.com/keniack/stardustGo/internal/routing"
+	"github.com/keniack/stardustGo/pkg/helper"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var _ types.StatePlugin = (*SloEvaluationPlugin)(nil)
+var _ SloStatePlugin = (*SloEvaluationPlugin)(nil)
+var _ io.Closer = (*SloEvaluationPlugin)(nil)
+
+// SLO types
+const (
+	LatencySlo      = "latency"      // percentile of the route latencies of all pairs of From and To nodes
+	ReachabilitySlo = "reachability" // every From node reaches at least one To node
+)
+
+// SloStatePlugin reports the evaluation of the SLOs after every step.
+type SloStatePlugin interface {
+	types.StatePlugin
+
+	// Evaluations returns the evaluation of the SLO at every step
+	Evaluations(slo string) []SloEvaluation
+
+	// Violations returns the violations of the SLO, the last one may be ongoing
+	Violations(slo string) []SloViolation
+
+	// Availability returns the fraction of the evaluated time the SLO was met
+	Availability(slo string) float64
+}
+
+// SloEvaluation is the state of an SLO at one simulation step.
+type SloEvaluation struct {
+	Time    time.Time
+	Value   float64 // latency in ms compared to the threshold, infinite if a route is missing
+	Failing int     // routes of a latency SLO or From nodes of a reachability SLO above the threshold
+	Met     bool
+}
+
+// SloViolation is a period the SLO was not met, from the last step it was met until the last step it was violated.
+type SloViolation struct {
+	Start time.Time
+	End   time.Time
+	Worst float64 // highest latency in ms during the violation
+}
+
+// Duration returns the duration of the violation.
+func (v SloViolation) Duration() time.Duration {
+	return v.End.Sub(v.Start)
+}
+
+// SloEvaluationPlugin evaluates SLOs on the routes calculated by the routers of the nodes after every step.
+// Each evaluation stands for the time since the previous step, which adds to the violation time if the SLO is not met.
+type SloEvaluationPlugin struct {
+	config     configs.SloConfig
+	objectives []*objective
+
+	timelineFile   *os.File
+	timelineWriter *bufio.Writer
+}
+
+// objective is an SLO and its evaluations.
+type objective struct {
+	config      configs.SloDefinitionConfig
+	from, to    endpoint
+	resolved    bool
+	samples     []latencySample // route latencies within the window of a latency SLO
+	evaluations []SloEvaluation
+	violations  []SloViolation
+	ongoing     bool // the last violation is not over
+	evaluated   time.Duration
+	violated    time.Duration
+}
+
+// latencySample are the route latencies of a latency SLO at one step.
+type latencySample struct {
+	time      time.Time
+	latencies []float64
+}
+
+// endpoint selects the nodes of one end of the routes.
+type endpoint struct {
+	config   configs.SloEndpointConfig
+	selector func(types.Node) bool
+	nodes    []types.Node
+}
+
+// NewSloEvaluationPlugin creates the plugin evaluating the configured SLOs.
+func NewSloEvaluationPlugin(config configs.SloConfig) (*SloEvaluationPlugin, error) {
+	p := &SloEvaluationPlugin{config: config}
+	names := make(map[string]bool)
+	for _, cfg := range config.Objectives {
+		if cfg.Name == "" || names[cfg.Name] {
+			return nil, fmt.Errorf("SLO name missing or duplicate: %q", cfg.Name)
+		}
+		names[cfg.Name] = true
+
+		cfg.Type = strings.ToLower(cfg.Type)
+		if cfg.Type == "" {
+			cfg.Type = LatencySlo
+		}
+		if cfg.Type != LatencySlo && cfg.Type != ReachabilitySlo {
+			return nil, fmt.Errorf("unknown type of SLO %s: %s", cfg.Name, cfg.Type)
+		}
+		if cfg.Percentile <= 0 || cfg.Percentile > 100 {
+			cfg.Percentile = 95
+		}
+		if cfg.From.Service != "" {
+			return nil, fmt.Errorf("SLO %s: routes cannot start at a service", cfg.Name)
+		}
+
+		o := &objective{config: cfg}
+		var err error
+		if o.from, err = newEndpoint(cfg.From); err != nil {
+			return nil, fmt.Errorf("invalid From of SLO %s: %w", cfg.Name, err)
+		}
+		if o.to, err = newEndpoint(cfg.To); err != nil {
+			return nil, fmt.Errorf("invalid To of SLO %s: %w", cfg.Name, err)
+		}
+		p.objectives = append(p.objectives, o)
+	}
+	return p, nil
+}
+
+func newEndpoint(cfg configs.SloEndpointConfig) (endpoint, error) {
+	selector, err := deployment.NewNodeSelector(cfg.NodeSelector)
+	return endpoint{config: cfg, selector: selector}, err
+}
+
+func (p *SloEvaluationPlugin) GetName() string {
+	return "SloStatePlugin"
+}
+
+func (p *SloEvaluationPlugin) GetType() reflect.Type {
+	return reflect.TypeOf((*SloStatePlugin)(nil)).Elem()
+}
+
+// PostSimulationStep evaluates all SLOs and writes the configured outputs
+func (p *SloEvaluationPlugin) PostSimulationStep(simulationController types.SimulationController) {
+	now := simulationController.GetSimulationTime()
+	for _, o := range p.objectives {
+		if !o.resolved {
+			o.resolved = true
+			if err := o.resolve(simulationController); err != nil {
+				log.Printf("SLO %s is not evaluated: %v", o.config.Name, err)
+			}
+		}
+		if len(o.from.nodes) == 0 {
+			continue
+		}
+		p.record(o, o.evaluate(now))
+	}
+
+	if err := p.write(); err != nil {
+		log.Printf("Failed to write SLO output: %v", err)
+	}
+}
+
+// AddState does nothing, the SLOs are evaluated again on the loaded states
+func (p *SloEvaluationPlugin) AddState(simulationController types.SimulationController) {
+}
+
+// Save does nothing, the outputs are written after every step
+func (p *SloEvaluationPlugin) Save(filename string) {
+}
+
+// Close flushes and closes the timeline file
+func (p *SloEvaluationPlugin) Close() error {
+	if p.timelineFile == nil {
+		return nil
+	}
+	return errors.Join(p.timelineWriter.Flush(), p.timelineFile.Close())
+}
+
+// Evaluations returns the evaluation of the SLO at every step.
+func (p *SloEvaluationPlugin) Evaluations(slo string) []SloEvaluation {
+	if o, ok := p.objective(slo); ok {
+		return append([]SloEvaluation(nil), o.evaluations...)
+	}
+	return nil
+}
+
+// Violations returns the violations of the SLO, the last one may be ongoing.
+func (p *SloEvaluationPlugin) Violations(slo string) []SloViolation {
+	if o, ok := p.objective(slo); ok {
+		return append([]SloViolation(nil), o.violations...)
+	}
+	return nil
+}
+
+// Availability returns the fraction of the evaluated time the SLO was met.
+func (p *SloEvaluationPlugin) Availability(slo string) float64 {
+	if o, ok := p.objective(slo); ok {
+		return o.availability()
+	}
+	return 0
+}
+
+func (p *SloEvaluationPlugin) objective(slo string) (*objective, bool) {
+	for _, o := range p.objectives {
+		if o.config.Name == slo {
+			return o, true
+		}
+	}
+	return nil, false
+}
+
+// record adds the evaluation to the timeline of the SLO and extends or ends its violation.
+func (p *SloEvaluationPlugin) record(o *objective, e SloEvaluation) {
+	elapsed := time.Duration(0)
+	if n := len(o.evaluations); n > 0 {
+		elapsed = e.Time.Sub(o.evaluations[n-1].Time)
+	}
+	o.evaluated += elapsed
+
+	switch {
+	case !e.Met && o.ongoing:
+		v := &o.violations[len(o.violations)-1]
+		v.End, v.Worst = e.Time, max(v.Worst, e.Value)
+		o.violated += elapsed
+	case !e.Met:
+		o.ongoing = true
+		o.violations = append(o.violations, SloViolation{Start: e.Time.Add(-elapsed), End: e.Time, Worst: e.Value})
+		o.violated += elapsed
+		log.Printf("SLO %s violated: %.3f ms above %.3f ms", o.config.Name, e.Value, o.config.Threshold)
+	case o.ongoing:
+		o.ongoing = false
+		log.Printf("SLO %s met again after %s", o.config.Name, o.violations[len(o.violations)-1].Duration())
+	}
+	o.evaluations = append(o.evaluations, e)
+
+	if p.config.TimelineFile != "" && p.timelineWriter == nil {
+		file, err := os.Create(p.config.TimelineFile)
+		if err != nil {
+			log.Printf("Failed to create SLO timeline file: %v", err)
+			p.config.TimelineFile = ""
+			return
+		}
+		p.timelineFile = file
+		p.timelineWriter = bufio.NewWriter(file)
+		fmt.Fprintln(p.timelineWriter, "time,slo,value_ms,threshold_ms,failing,met")
+	}
+	if p.timelineWriter != nil {
+		fmt.Fprintf(p.timelineWriter, "%s,%s,%.3f,%.3f,%d,%t\n", e.Time.Format(time.RFC3339), o.config.Name,
+			e.Value, o.config.Threshold, e.Failing, e.Met)
+	}
+}
+
+// write flushes the timeline and rewrites the violations and the summary, which change with ongoing violations.
+func (p *SloEvaluationPlugin) write() error {
+	if p.timelineWriter != nil {
+		if err := p.timelineWriter.Flush(); err != nil {
+			return err
+		}
+	}
+	if p.config.ViolationFile != "" {
+		var b strings.Builder
+		b.WriteString("slo,start,end,duration_s,worst_ms,ongoing\n")
+		for _, o := range p.objectives {
+			for i, v := range o.violations {
+				fmt.Fprintf(&b, "%s,%s,%s,%.3f,%.3f,%t\n", o.config.Name, v.Start.Format(time.RFC3339), v.End.Format(time.RFC3339),
+					v.Duration().Seconds(), v.Worst, o.ongoing && i == len(o.violations)-1)
+			}
+		}
+		if err := os.WriteFile(p.config.ViolationFile, []byte(b.String()), 0644); err != nil {
+			return err
+		}
+	}
+	if p.config.SummaryFile != "" {
+		var b strings.Builder
+		b.WriteString("slo,type,threshold_ms,evaluated_s,violated_s,violations,availability\n")
+		for _, o := range p.objectives {
+			fmt.Fprintf(&b, "%s,%s,%.3f,%.3f,%.3f,%d,%.6f\n", o.config.Name, o.config.Type, o.config.Threshold,
+				o.evaluated.Seconds(), o.violated.Seconds(), len(o.violations), o.availability())
+		}
+		if err := os.WriteFile(p.config.SummaryFile, []byte(b.String()), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve selects the nodes of both endpoints, the nodes of the simulation do not change.
+func (o *objective) resolve(simulation types.SimulationController) error {
+	var err error
+	if o.from.nodes, err = o.from.resolve(simulation); err != nil {
+		return err
+	}
+	if o.to.config.Service != "" {
+		return nil
+	}
+	if o.to.nodes, err = o.to.resolve(simulation); err != nil {
+		o.from.nodes = nil
+	}
+	return err
+}
+
+// evaluate measures the route latencies of the SLO.
+func (o *objective) evaluate(now time.Time) SloEvaluation {
+	threshold := o.config.Threshold
+	e := SloEvaluation{Time: now}
+
+	if o.config.Type == ReachabilitySlo {
+		for _, f := range o.from.nodes {
+			best := math.Inf(1)
+			if o.to.config.Service != "" {
+				best = routing.ServiceLatency(f, o.to.config.Service)
+			}
+			for _, t := range o.to.nodes {
+				best = min(best, routing.RouteLatency(f, t))
+			}
+			e.Value = max(e.Value, best)
+			if best > threshold {
+				e.Failing++
+			}
+		}
+		e.Met = e.Failing == 0
+		return e
+	}
+
+	var latencies []float64
+	for _, f := range o.from.nodes {
+		if o.to.config.Service != "" {
+			latencies = append(latencies, routing.ServiceLatency(f, o.to.config.Service))
+		}
+		for _, t := range o.to.nodes {
+			if t != f {
+				latencies = append(latencies, routing.RouteLatency(f, t))
+			}
+		}
+	}
+	for _, l := range latencies {
+		if l > threshold {
+			e.Failing++
+		}
+	}
+
+	// The percentile covers the latencies of all steps within the window
+	window := helper.Seconds(o.config.Window)
+	o.samples = append(o.samples, latencySample{time: now, latencies: latencies})
+	o.samples = slices.DeleteFunc(o.samples, func(s latencySample) bool { return s.time.Before(now) && !s.time.After(now.Add(-window)) })
+	var all []float64
+	for _, s := range o.samples {
+		all = append(all, s.latencies...)
+	}
+	e.Value = helper.Percentile(all, o.config.Percentile)
+	e.Met = len(all) > 0 && e.Value <= threshold
+	return e
+}
+
+// availability returns the fraction of the evaluated time the SLO was met, for a single evaluation whether it was met.
+func (o *objective) availability() float64 {
+	if o.evaluated <= 0 {
+		if len(o.evaluations) > 0 && o.evaluations[len(o.evaluations)-1].Met {
+			return 1
+		}
+		return 0
+	}
+	return 1 - o.violated.Seconds()/o.evaluated.Seconds()
+}
+
+// resolve returns the nodes of the simulation selected by the endpoint.
+func (e *endpoint) resolve(simulation types.SimulationController) ([]types.Node, error) {
+	var nodes []types.Node
+	for _, n := range simulation.GetAllNodes() {
+		if len(e.config.Nodes) > 0 && !slices.Contains(e.config.Nodes, n.GetName()) {
+			continue
+		}
+		if e.selector != nil && !e.selector(n) {
+			continue
+		}
+		if ct := e.config.ComputingType; ct != types.None && ct != types.Any && n.GetComputing().GetComputingType() != ct {
+			continue
+		}
+		nodes = append(nodes, n)
+	}
+	for _, name := range e.config.Nodes {
+		if !slices.ContainsFunc(nodes, func(n types.Node) bool { return n.GetName() == name }) {
+			return nil, fmt.Errorf("node %s not found or not selected", name)
+		}
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no node selected")
+	}
+	return nodes, nil
+}
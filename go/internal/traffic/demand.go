@@ -0,0 +1,115 @@
+package traffic
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/keniack/stardustGo/internal/network"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var _ types.Flow = Demand{}
+
+// Demand is traffic from one ground station to another.
+// A constant demand sends Rate bits per second for its Duration, a transfer sends Bytes as fast as the network allows.
+type Demand struct {
+	ID       string
+	Source   types.Node
+	Target   types.Node
+	Start    time.Time
+	Duration time.Duration // duration of a constant demand, 0 for a transfer
+	Rate     float64       // bits per second of a constant demand, 0 for a transfer
+	Bytes    int64         // total bytes of the demand
+}
+
+// FlowID returns the ID of the demand, so multipath routers keep the demand on one path
+func (d Demand) FlowID() string {
+	return d.ID
+}
+
+// IsTransfer reports if the demand is a transfer of a fixed size instead of a constant rate.
+func (d Demand) IsTransfer() bool {
+	return d.Rate == 0
+}
+
+// Pair is an ordered pair of source and target node.
+type Pair struct {
+	Source types.Node
+	Target types.Node
+}
+
+// Matrix is the traffic matrix: the mean rate in bits per second per ordered pair of nodes.
+type Matrix map[Pair]float64
+
+// NewMatrix averages the demands over the time window [from, to).
+// Constant demands count with their overlap with the window, transfers with all bytes if they start in the window.
+func NewMatrix(demands []Demand, from, to time.Time) Matrix {
+	m := make(Matrix)
+	window := to.Sub(from).Seconds()
+	if window <= 0 {
+		return m
+	}
+	for _, d := range demands {
+		pair := Pair{Source: d.Source, Target: d.Target}
+		if d.IsTransfer() {
+			if !d.Start.Before(from) && d.Start.Before(to) {
+				m[pair] += float64(d.Bytes*8) / window
+			}
+			continue
+		}
+		start, end := d.Start, d.Start.Add(d.Duration)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if overlap := end.Sub(start).Seconds(); overlap > 0 {
+			m[pair] += d.Rate * overlap / window
+		}
+	}
+	return m
+}
+
+// Total returns the summed rate of all pairs in bits per second.
+func (m Matrix) Total() float64 {
+	total := 0.0
+	for _, rate := range m {
+		total += rate
+	}
+	return total
+}
+
+// Inject adds every demand as flow to the packet network. Constant demands send packets of the given size
+// at their rate, transfers at transferRate bits per second until all bytes are sent.
+func Inject(net *network.Network, demands []Demand, packetSize int, transferRate float64) error {
+	if packetSize <= 0 {
+		return fmt.Errorf("packet size must be positive, got %d", packetSize)
+	}
+	for _, d := range demands {
+		flow := &network.Flow{
+			ID:         d.ID,
+			Source:     d.Source,
+			Target:     d.Target,
+			PacketSize: packetSize,
+			Rate:       d.Rate,
+			Start:      d.Start,
+			Stop:       d.Start.Add(d.Duration),
+		}
+		if d.IsTransfer() {
+			if transferRate <= 0 {
+				return fmt.Errorf("transfer %s requires a positive transfer rate", d.ID)
+			}
+			// Send the number of packets covering the transfer, the last one is rounded up to the packet size
+			packets := math.Ceil(float64(d.Bytes) / float64(packetSize))
+			interval := float64(packetSize*8) / transferRate
+			flow.Rate = transferRate
+			flow.Stop = d.Start.Add(time.Duration((packets - 0.5) * interval * float64(time.Second)))
+		}
+		if err := net.AddFlow(flow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package traffic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// Traffic models
+const (
+	MatrixModel  = "matrix"  // constant rates between named stations
+	GravityModel = "gravity" // constant rates proportional to the product of the station weights
+	PoissonModel = "poisson" // transfers arriving as Poisson process between weighted random stations
+	TraceModel   = "trace"   // transfers replayed from a CSV trace
+)
+
+// Generate creates the demand of the traffic config between the ground stations, sorted by start time.
+// Stations without weight count with weight 1. The demand is deterministic for the same config and stations.
+func Generate(cfg configs.TrafficConfig, stations []types.GroundStation, simStart time.Time) ([]Demand, error) {
+	start := cfg.Start
+	if start.IsZero() {
+		start = simStart
+	}
+	duration := time.Duration(cfg.Duration * float64(time.Second))
+
+	selected := stations
+	if cfg.Tag != "" {
+		selected = nil
+		for _, gs := range stations {
+			if slices.Contains(gs.GetTags(), cfg.Tag) {
+				selected = append(selected, gs)
+			}
+		}
+	}
+
+	var demands []Demand
+	var err error
+	switch strings.ToLower(cfg.Model) {
+	case MatrixModel:
+		demands, err = matrixDemand(cfg, selected, start, duration)
+	case GravityModel:
+		demands, err = gravityDemand(cfg, selected, start, duration)
+	case PoissonModel:
+		demands, err = poissonDemand(cfg, selected, start, duration)
+	case TraceModel:
+		demands, err = LoadTrace(cfg.Trace, selected, start)
+	default:
+		return nil, fmt.Errorf("unknown traffic model: %s", cfg.Model)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(demands, func(i, j int) bool { return demands[i].Start.Before(demands[j].Start) })
+	return demands, nil
+}
+
+// constantDemand creates a demand sending at the rate for the duration.
+func constantDemand(id string, source, target types.Node, start time.Time, duration time.Duration, rate float64) Demand {
+	return Demand{
+		ID:       id,
+		Source:   source,
+		Target:   target,
+		Start:    start,
+		Duration: duration,
+		Rate:     rate,
+		Bytes:    int64(rate * duration.Seconds() / 8),
+	}
+}
+
+func matrixDemand(cfg configs.TrafficConfig, stations []types.GroundStation, start time.Time, duration time.Duration) ([]Demand, error) {
+	if duration <= 0 {
+		return nil, errors.New("traffic model matrix requires a positive Duration")
+	}
+	byName := stationsByName(stations)
+	demands := make([]Demand, 0, len(cfg.Matrix))
+	for i, entry := range cfg.Matrix {
+		source, ok := byName[entry.Source]
+		if !ok {
+			return nil, fmt.Errorf("unknown ground station in traffic matrix: %s", entry.Source)
+		}
+		target, ok := byName[entry.Target]
+		if !ok {
+			return nil, fmt.Errorf("unknown ground station in traffic matrix: %s", entry.Target)
+		}
+		if entry.Rate <= 0 {
+			return nil, fmt.Errorf("rate from %s to %s must be positive", entry.Source, entry.Target)
+		}
+		id := fmt.Sprintf("%s>%s#%d", entry.Source, entry.Target, i)
+		demands = append(demands, constantDemand(id, source, target, start, duration, entry.Rate))
+	}
+	return demands, nil
+}
+
+// gravityDemand distributes the total rate over all ordered pairs of stations proportional to
+// w(source) * w(target) / distance^DistanceExponent.
+func gravityDemand(cfg configs.TrafficConfig, stations []types.GroundStation, start time.Time, duration time.Duration) ([]Demand, error) {
+	if duration <= 0 {
+		return nil, errors.New("traffic model gravity requires a positive Duration")
+	}
+	if cfg.TotalRate <= 0 {
+		return nil, errors.New("traffic model gravity requires a positive TotalRate")
+	}
+
+	type pairWeight struct {
+		source, target types.GroundStation
+		weight         float64
+	}
+	var pairs []pairWeight
+	sum := 0.0
+	for _, source := range stations {
+		for _, target := range stations {
+			if source == target {
+				continue
+			}
+			weight := stationWeight(source) * stationWeight(target)
+			if cfg.DistanceExponent != 0 {
+				distance := source.DistanceTo(target) / 1000
+				if distance <= 0 {
+					return nil, fmt.Errorf("distance between %s and %s unknown, positions not updated yet", source.GetName(), target.GetName())
+				}
+				weight /= math.Pow(distance, cfg.DistanceExponent)
+			}
+			pairs = append(pairs, pairWeight{source: source, target: target, weight: weight})
+			sum += weight
+		}
+	}
+	if sum == 0 {
+		return nil, errors.New("traffic model gravity requires at least two ground stations")
+	}
+
+	demands := make([]Demand, 0, len(pairs))
+	for _, p := range pairs {
+		id := fmt.Sprintf("%s>%s", p.source.GetName(), p.target.GetName())
+		demands = append(demands, constantDemand(id, p.source, p.target, start, duration, cfg.TotalRate*p.weight/sum))
+	}
+	return demands, nil
+}
+
+// poissonDemand creates transfers with exponentially distributed inter-arrival times.
+// Source and target of every transfer are drawn proportional to the station weights.
+func poissonDemand(cfg configs.TrafficConfig, stations []types.GroundStation, start time.Time, duration time.Duration) ([]Demand, error) {
+	if duration <= 0 {
+		return nil, errors.New("traffic model poisson requires a positive Duration")
+	}
+	if cfg.ArrivalRate <= 0 {
+		return nil, errors.New("traffic model poisson requires a positive ArrivalRate")
+	}
+	if len(stations) < 2 {
+		return nil, errors.New("traffic model poisson requires at least two ground stations")
+	}
+	sizes, err := NewSizeDistribution(cfg.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cumulative weights to draw stations by binary search
+	cumulative := make([]float64, len(stations))
+	sum := 0.0
+	for i, gs := range stations {
+		sum += stationWeight(gs)
+		cumulative[i] = sum
+	}
+	draw := func(r *rand.Rand) int {
+		return sort.SearchFloat64s(cumulative, r.Float64()*sum)
+	}
+
+	r := rand.New(rand.NewPCG(uint64(cfg.Seed), 0))
+	var demands []Demand
+	elapsed := 0.0
+	for {
+		elapsed += r.ExpFloat64() / cfg.ArrivalRate
+		if elapsed >= duration.Seconds() {
+			break
+		}
+		source := draw(r)
+		target := draw(r)
+		for target == source {
+			target = draw(r)
+		}
+		demands = append(demands, Demand{
+			ID:     fmt.Sprintf("%s>%s#%d", stations[source].GetName(), stations[target].GetName(), len(demands)),
+			Source: stations[source],
+			Target: stations[target],
+			Start:  start.Add(time.Duration(elapsed * float64(time.Second))),
+			Bytes:  sizes.Sample(r),
+		})
+	}
+	return demands, nil
+}
+
+// stationWeight returns the weight of the station in the traffic models, 1 if not set.
+func stationWeight(gs types.GroundStation) float64 {
+	if w := gs.GetWeight(); w > 0 {
+		return w
+	}
+	return 1
+}
+
+// stationsByName maps the names to the stations, the first one wins for duplicate names.
+func stationsByName(stations []types.GroundStation) map[string]types.GroundStation {
+	byName := make(map[string]types.GroundStation, len(stations))
+	for _, gs := range stations {
+		if _, exists := byName[gs.GetName()]; !exists {
+			byName[gs.GetName()] = gs
+		}
+	}
+	return byName
+}
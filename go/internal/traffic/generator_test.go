@@ -0,0 +1,107 @@
+package traffic
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/keniack/stardustGo/configs"
+	"github.com/keniack/stardustGo/internal/simtest"
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+var start = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// stations creates the ground stations A, B and C with the weights 1, 2 and 3, A and B are tagged "eu".
+func stations() []types.GroundStation {
+	a := simtest.NewGroundStation("A", "eu")
+	b := simtest.NewGroundStation("B", "eu")
+	b.Weight = 2
+	c := simtest.NewGroundStation("C")
+	c.Weight = 3
+	return []types.GroundStation{a, b, c}
+}
+
+func TestGravityDemand(t *testing.T) {
+	demands, err := Generate(configs.TrafficConfig{Model: GravityModel, Duration: 10, TotalRate: 22_000}, stations(), start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The pairs are weighted with the product of the station weights, which sum up to 22
+	want := map[string]float64{"A>B": 2000, "A>C": 3000, "B>A": 2000, "B>C": 6000, "C>A": 3000, "C>B": 6000}
+	if len(demands) != len(want) {
+		t.Fatalf("%d demands, want %d", len(demands), len(want))
+	}
+	for _, d := range demands {
+		if math.Abs(d.Rate-want[d.ID]) > 1e-9 {
+			t.Errorf("rate of %s = %v, want %v", d.ID, d.Rate, want[d.ID])
+		}
+		if !d.Start.Equal(start) || d.Duration != 10*time.Second || d.IsTransfer() {
+			t.Errorf("demand %s is not constant for 10 s from the start", d.ID)
+		}
+	}
+
+	matrix := NewMatrix(demands, start.Add(5*time.Second), start.Add(15*time.Second))
+	if total := matrix.Total(); math.Abs(total-11_000) > 1e-9 {
+		t.Errorf("mean rate over a window half covered by the demand = %v, want 11000", total)
+	}
+}
+
+func TestPoissonDemand(t *testing.T) {
+	cfg := configs.TrafficConfig{
+		Model:       PoissonModel,
+		Seed:        7,
+		Duration:    100,
+		ArrivalRate: 2,
+		Tag:         "eu",
+		Size:        configs.SizeDistributionConfig{Mean: 1000},
+	}
+	demands, err := Generate(cfg, stations(), start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(demands) < 150 || len(demands) > 250 {
+		t.Errorf("%d transfers in 100 s at 2 per second", len(demands))
+	}
+	for i, d := range demands {
+		if d.Source == d.Target || d.Source.GetName() == "C" || d.Target.GetName() == "C" {
+			t.Errorf("transfer %s between the wrong stations", d.ID)
+		}
+		if !d.IsTransfer() || d.Bytes != 1000 {
+			t.Errorf("transfer %s of %d bytes, want 1000", d.ID, d.Bytes)
+		}
+		if d.Start.Before(start) || !d.Start.Before(start.Add(100*time.Second)) || i > 0 && d.Start.Before(demands[i-1].Start) {
+			t.Errorf("transfer %s starts out of order at %v", d.ID, d.Start)
+		}
+	}
+
+	again, err := Generate(cfg, stations(), start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again) != len(demands) || again[0].ID != demands[0].ID || !again[0].Start.Equal(demands[0].Start) {
+		t.Error("same seed created a different demand")
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  configs.TrafficConfig
+	}{
+		{"unknown model", configs.TrafficConfig{Model: "uniform", Duration: 1}},
+		{"matrix without duration", configs.TrafficConfig{Model: MatrixModel}},
+		{"matrix with unknown station", configs.TrafficConfig{Model: MatrixModel, Duration: 1, Matrix: []configs.TrafficMatrixEntry{{Source: "A", Target: "X", Rate: 1}}}},
+		{"gravity without rate", configs.TrafficConfig{Model: GravityModel, Duration: 1}},
+		{"gravity without stations", configs.TrafficConfig{Model: GravityModel, Duration: 1, TotalRate: 1, Tag: "none"}},
+		{"poisson without size", configs.TrafficConfig{Model: PoissonModel, Duration: 1, ArrivalRate: 1}},
+		{"trace without file", configs.TrafficConfig{Model: TraceModel}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Generate(tt.cfg, stations(), start); err == nil {
+				t.Error("config accepted")
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package traffic
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/keniack/stardustGo/configs"
+)
+
+// Transfer size distributions
+const (
+	ConstantSize    = "constant"
+	ExponentialSize = "exponential"
+	ParetoSize      = "pareto"
+	LognormalSize   = "lognormal"
+)
+
+// SizeDistribution draws transfer sizes in bytes.
+type SizeDistribution interface {
+	Sample(r *rand.Rand) int64
+}
+
+// NewSizeDistribution creates the configured size distribution, constant if no type is set.
+func NewSizeDistribution(cfg configs.SizeDistributionConfig) (SizeDistribution, error) {
+	if cfg.Mean <= 0 {
+		return nil, fmt.Errorf("mean transfer size must be positive, got %g", cfg.Mean)
+	}
+	switch strings.ToLower(cfg.Type) {
+	case "", ConstantSize:
+		return constantSize{mean: cfg.Mean}, nil
+	case ExponentialSize:
+		return exponentialSize{mean: cfg.Mean}, nil
+	case ParetoSize:
+		if cfg.Shape <= 1 {
+			return nil, fmt.Errorf("shape of %s sizes must be greater than 1 for a finite mean, got %g", ParetoSize, cfg.Shape)
+		}
+		return paretoSize{scale: cfg.Mean * (cfg.Shape - 1) / cfg.Shape, shape: cfg.Shape}, nil
+	case LognormalSize:
+		sigma := cfg.Shape
+		if sigma <= 0 {
+			sigma = 1
+		}
+		return lognormalSize{mu: math.Log(cfg.Mean) - sigma*sigma/2, sigma: sigma}, nil
+	default:
+		return nil, fmt.Errorf("unknown size distribution: %s", cfg.Type)
+	}
+}
+
+// toBytes rounds a sampled size to whole bytes, at least one.
+func toBytes(size float64) int64 {
+	return max(int64(math.Round(size)), 1)
+}
+
+type constantSize struct{ mean float64 }
+
+func (d constantSize) Sample(*rand.Rand) int64 { return toBytes(d.mean) }
+
+type exponentialSize struct{ mean float64 }
+
+func (d exponentialSize) Sample(r *rand.Rand) int64 { return toBytes(r.ExpFloat64() * d.mean) }
+
+// paretoSize is heavy-tailed: many small transfers and few very large ones.
+type paretoSize struct{ scale, shape float64 }
+
+func (d paretoSize) Sample(r *rand.Rand) int64 {
+	return toBytes(d.scale / math.Pow(1-r.Float64(), 1/d.shape))
+}
+
+// lognormalSize has mu chosen so the mean of the sizes is the configured mean.
+type lognormalSize struct{ mu, sigma float64 }
+
+func (d lognormalSize) Sample(r *rand.Rand) int64 {
+	return toBytes(math.Exp(d.mu + d.sigma*r.NormFloat64()))
+}
@@ -0,0 +1,91 @@
+package traffic
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/keniack/stardustGo/pkg/types"
+)
+
+// LoadTrace reads recorded transfers from a CSV file, see ParseTrace.
+func LoadTrace(path string, stations []types.GroundStation, start time.Time) ([]Demand, error) {
+	if path == "" {
+		return nil, errors.New("traffic model trace requires a Trace file")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseTrace(file, stations, start)
+}
+
+// ParseTrace reads transfers with the columns start time, source, target and bytes.
+// The start time is either an RFC 3339 timestamp or seconds after start. A header row and lines starting with # are skipped.
+func ParseTrace(r io.Reader, stations []types.GroundStation, start time.Time) ([]Demand, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+
+	byName := stationsByName(stations)
+	var demands []Demand
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		bytes, err := strconv.ParseInt(strings.TrimSpace(record[3]), 10, 64)
+		if err != nil {
+			if line == 1 {
+				continue // header
+			}
+			return nil, fmt.Errorf("trace line %d: invalid bytes %q", line, record[3])
+		}
+		if bytes <= 0 {
+			return nil, fmt.Errorf("trace line %d: bytes must be positive", line)
+		}
+		at, err := parseTraceTime(strings.TrimSpace(record[0]), start)
+		if err != nil {
+			return nil, fmt.Errorf("trace line %d: %w", line, err)
+		}
+		sourceName, targetName := strings.TrimSpace(record[1]), strings.TrimSpace(record[2])
+		source, ok := byName[sourceName]
+		if !ok {
+			return nil, fmt.Errorf("trace line %d: unknown ground station %s", line, sourceName)
+		}
+		target, ok := byName[targetName]
+		if !ok {
+			return nil, fmt.Errorf("trace line %d: unknown ground station %s", line, targetName)
+		}
+		demands = append(demands, Demand{
+			ID:     fmt.Sprintf("%s>%s#%d", sourceName, targetName, len(demands)),
+			Source: source,
+			Target: target,
+			Start:  at,
+			Bytes:  bytes,
+		})
+	}
+	return demands, nil
+}
+
+// parseTraceTime parses an RFC 3339 timestamp or an offset in seconds after start.
+func parseTraceTime(value string, start time.Time) (time.Time, error) {
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return start.Add(time.Duration(seconds * float64(time.Second))), nil
+	}
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid start time %q", value)
+	}
+	return at, nil
+}
@@ -0,0 +1,56 @@
+package traffic
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTrace(t *testing.T) {
+	trace := `start,source,target,bytes
+# offsets and timestamps
+0.5, A, B, 1000
+2025-01-01T00:01:00Z, C, A, 20
+`
+	demands, err := ParseTrace(strings.NewReader(trace), stations(), start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(demands) != 2 {
+		t.Fatalf("%d transfers, want 2", len(demands))
+	}
+	tests := []struct {
+		id    string
+		start time.Time
+		bytes int64
+	}{
+		{"A>B#0", start.Add(500 * time.Millisecond), 1000},
+		{"C>A#1", start.Add(time.Minute), 20},
+	}
+	for i, tt := range tests {
+		d := demands[i]
+		if d.ID != tt.id || !d.Start.Equal(tt.start) || d.Bytes != tt.bytes {
+			t.Errorf("transfer %d = %s at %v with %d bytes, want %s at %v with %d bytes", i, d.ID, d.Start, d.Bytes, tt.id, tt.start, tt.bytes)
+		}
+	}
+}
+
+func TestParseTraceErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		trace string
+	}{
+		{"unknown station", "0,A,X,10\n"},
+		{"invalid bytes", "0,A,B,10\n1,A,B,many\n"},
+		{"no bytes", "0,A,B,0\n"},
+		{"invalid time", "yesterday,A,B,10\n"},
+		{"missing column", "0,A,10\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseTrace(strings.NewReader(tt.trace), stations(), start); err == nil {
+				t.Error("trace accepted")
+			}
+		})
+	}
+}
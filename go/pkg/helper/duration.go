@@ -0,0 +1,18 @@
+package helper
+
+import "time"
+
+// Seconds converts seconds to a duration.
+func Seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// Milliseconds converts milliseconds to a duration.
+func Milliseconds(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// ToMilliseconds converts a duration to milliseconds.
+func ToMilliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
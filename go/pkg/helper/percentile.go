@@ -0,0 +1,17 @@
+package helper
+
+import (
+	"math"
+	"sort"
+)
+
+// Percentile returns the p-th percentile of the values by the nearest rank method, 0 for no values.
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[min(max(rank, 1), len(sorted))-1]
+}
@@ -0,0 +1,35 @@
+package helper
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPercentile(t *testing.T) {
+	values := []float64{40, 15, 50, 35, 20}
+	tests := []struct {
+		name   string
+		values []float64
+		p      float64
+		want   float64
+	}{
+		{"empty", nil, 50, 0},
+		{"single", []float64{7}, 99, 7},
+		{"zero is the minimum", values, 0, 15},
+		{"30th", values, 30, 20},
+		{"40th", values, 40, 20},
+		{"median", values, 50, 35},
+		{"99th", values, 99, 50},
+		{"maximum", values, 100, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Percentile(tt.values, tt.p); got != tt.want {
+				t.Errorf("Percentile(%v, %v) = %v, want %v", tt.values, tt.p, got, tt.want)
+			}
+		})
+	}
+	if !slices.Equal(values, []float64{40, 15, 50, 35, 20}) {
+		t.Errorf("Percentile sorted its input: %v", values)
+	}
+}
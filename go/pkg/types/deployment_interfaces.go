@@ -21,6 +21,15 @@ type DeployableService interface {
 	Remove() error
 }
 
+// HardwareRequirements is implemented by services requiring accelerators or storage besides CPU and memory.
+type HardwareRequirements interface {
+	// GetAcceleratorUsage returns the number of accelerators required by the service.
+	GetAcceleratorUsage() int
+
+	// GetStorageUsage returns the storage in GB required by the service.
+	GetStorageUsage() float64
+}
+
 // DeploymentSpecification defines the structure for a deployment specification.
 type DeploymentSpecification interface {
 	// Type returns the type of the deployment.
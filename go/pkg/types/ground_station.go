@@ -3,4 +3,10 @@ package types
 // GroundStation represents a ground station node
 type GroundStation interface {
 	Node
+
+	// GetTags returns the tags of the ground station, e.g. "gateway"
+	GetTags() []string
+
+	// GetWeight returns the weight of the ground station in traffic models, e.g. its population, 0 if not set
+	GetWeight() float64
 }
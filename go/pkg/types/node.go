@@ -26,3 +26,9 @@ type Node interface {
 	// GetLinkNodeProtocol returns the link protocol instance associated with the node
 	GetLinkNodeProtocol() LinkNodeProtocol
 }
+
+// PositionPredictor is a node whose position can be computed for any simulation time, e.g. from its orbit.
+type PositionPredictor interface {
+	// PositionAt returns the position of the node at the simulation time without moving the node
+	PositionAt(simTime time.Time) Vector
+}
@@ -0,0 +1,25 @@
+package types
+
+// Path is a loop-free route through the network.
+type Path struct {
+	Nodes   []Node  // nodes from source to target, including both
+	Links   []Link  // links between consecutive nodes
+	Latency float64 // accumulated latency in milliseconds
+}
+
+// Bandwidth returns the bottleneck bandwidth of the path in bits per second.
+func (p Path) Bandwidth() float64 {
+	if len(p.Links) == 0 {
+		return 0
+	}
+	bandwidth := p.Links[0].Bandwidth()
+	for _, l := range p.Links[1:] {
+		bandwidth = min(bandwidth, l.Bandwidth())
+	}
+	return bandwidth
+}
+
+// Hops returns the number of links of the path.
+func (p Path) Hops() int {
+	return len(p.Links)
+}
@@ -2,3 +2,62 @@ package types
 
 // Payload represents a simulation payload interface.
 type Payload interface{}
+
+// Flow is implemented by payloads belonging to a flow.
+// Multipath routers keep all payloads of the same flow on the same path.
+type Flow interface {
+	// FlowID returns the identifier of the flow, e.g. a 5-tuple or a connection name
+	FlowID() string
+}
+
+// QosRequirements are the quality of service requirements of a payload.
+// Zero values mean no requirement.
+type QosRequirements struct {
+	MinBandwidth float64 // minimum bandwidth in bits per second of every link on the path
+	MaxLatency   float64 // maximum end-to-end latency in milliseconds
+	MaxHops      int     // maximum number of links on the path
+}
+
+// Requirements returns the requirements, so QosRequirements can be embedded into payloads
+func (q QosRequirements) Requirements() QosRequirements {
+	return q
+}
+
+// QosPayload is implemented by payloads with quality of service requirements.
+type QosPayload interface {
+	// Requirements returns the quality of service requirements of the payload
+	Requirements() QosRequirements
+}
+
+// RegionConstraints restrict the geographic regions a payload may be routed through.
+// Region names refer to the regions loaded by the router configuration. Empty lists mean no constraint.
+type RegionConstraints struct {
+	AvoidRegions  []string // no node on the path, except the source, may be located in these regions
+	EgressRegions []string // the path must leave the constellation via a ground station in one of these regions
+}
+
+// Constraints returns the constraints, so RegionConstraints can be embedded into payloads
+func (r RegionConstraints) Constraints() RegionConstraints {
+	return r
+}
+
+// RegionConstrainedPayload is implemented by payloads with geographic routing constraints.
+type RegionConstrainedPayload interface {
+	// Constraints returns the geographic routing constraints of the payload
+	Constraints() RegionConstraints
+}
+
+// ProbePayload is implemented by payloads which only measure a route, e.g. for monitoring.
+// Routing a probe has no side effects: service balancers do not assign it to a replica.
+type ProbePayload interface {
+	// Probe reports if the payload only measures the route
+	Probe() bool
+}
+
+// RouteProbe is the payload of route queries without side effects.
+type RouteProbe struct{}
+
+// Probe marks RouteProbe as a ProbePayload
+func (RouteProbe) Probe() bool {
+	return true
+}
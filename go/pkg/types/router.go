@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 // Router represents a router capable of resolving routes to services or nodes.
 type Router interface {
 	// CanPreRouteCalc indicates if the protocol can pre-calculate a routing table
@@ -21,6 +23,13 @@ type Router interface {
 	// i.e. fill into routing table
 	ReceiveServiceAdvertismentsAsync(serviceName string, outlink Link, route RouteResult) error
 
+	// WithdrawServiceAsync withdraws a service advertisement of the mounted node from other routers
+	WithdrawServiceAsync(serviceName string) error
+
+	// ReceiveServiceWithdrawalAsync removes the routes to the service hosted on origin
+	// and passes the withdrawal on to other routers
+	ReceiveServiceWithdrawalAsync(serviceName string, origin Node) error
+
 	// RouteToNode returns a route from mounted node to target node
 	// i.e. read from routing table or calculate on demand
 	RouteToNode(target Node, payload Payload) (RouteResult, error)
@@ -28,3 +37,32 @@ type Router interface {
 	// RouteToService returns a route from mounted node to target service
 	RouteToService(serviceName string, payload Payload) (RouteResult, error)
 }
+
+// ForwardingRouter is a Router which forwards payloads hop by hop.
+type ForwardingRouter interface {
+	Router
+
+	// NextHop returns the link over which the mounted node forwards the payload towards the target,
+	// or nil if the target is unreachable
+	NextHop(target Node, payload Payload) (Link, error)
+}
+
+// MultipathRouter is a Router which knows several paths to a target.
+type MultipathRouter interface {
+	Router
+
+	// KShortestPathsToNode returns up to k loop-free paths from mounted node to target node ordered by latency
+	KShortestPathsToNode(target Node, k int) ([]Path, error)
+
+	// EqualCostPathsToNode returns the paths whose latency is within the configured tolerance of the shortest path
+	EqualCostPathsToNode(target Node) ([]Path, error)
+}
+
+// TimedRouter is a Router whose routing information changes between simulation steps, e.g. while routing
+// updates are still propagating. Discrete-event simulations move it to the time of their route queries.
+type TimedRouter interface {
+	Router
+
+	// AdvanceTo moves the router to the given time of a route query, earlier times than before are ignored
+	AdvanceTo(t time.Time)
+}
@@ -1,6 +1,7 @@
 package types
 
 // SimulationPlugin defines the interface for simulation plugins.
+// Plugins holding resources such as output files implement io.Closer, the simulation closes them when it is closed.
 type SimulationPlugin interface {
 	// Name returns the name of the simulation plugin
 	Name() string
@@ -32,11 +32,17 @@ type RawSatellite struct {
 	Index         int
 	Name          string
 	ComputingType ComputingType
+	Profile       string // hardware profile of the computing unit, empty without
+	Router        string // router protocol of the satellite
 }
 
 type RawGroundStation struct {
 	Name          string
 	ComputingType ComputingType
+	Profile       string   // hardware profile of the computing unit, empty without
+	Router        string   // router protocol of the ground station
+	Tags          []string // tags of the ground station
+	Weight        float64  // weight of the ground station in traffic models
 }
 
 func NewSimulationMetadata() SimulationMetadata {
@@ -5,6 +5,7 @@ import (
 )
 
 // StatePlugin provides the interface of state plugins
+// Plugins holding resources such as output files implement io.Closer, the simulation closes them when it is closed.
 type StatePlugin interface {
 
 	// GetName returns the name of the plugin
@@ -52,7 +53,7 @@ func (r *StatePluginRepository) GetAllPlugins() []StatePlugin {
 // GetStatePlugin is a generic function that retrieves a plugin of type T from the repository.
 // It panics if the plugin is not found or if the type assertion fails.
 func GetStatePlugin[T StatePlugin](r *StatePluginRepository) T {
-	typ := reflect.TypeOf(*new(T))
+	typ := reflect.TypeOf((*T)(nil)).Elem()
 	plugin, ok := r.plugins[typ]
 	if !ok {
 		panic("plugin not found")